@@ -2,17 +2,34 @@ package api
 
 import (
 	"database/sql"
-	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 	db "github.com/nokkvi/simplebank/db/sqlc"
 	"github.com/nokkvi/simplebank/token"
+	"github.com/nokkvi/simplebank/util"
 )
 
+// accountResponse is an account with its balances also given as decimals in its currency
+type accountResponse struct {
+	db.Account
+	DecimalBalance          util.Money `json:"decimalBalance"`
+	DecimalAvailableBalance util.Money `json:"decimalAvailableBalance"`
+}
+
+func newAccountResponse(account db.Account) accountResponse {
+	return accountResponse{
+		Account: account,
+		DecimalBalance: util.NewMoney(account.Balance, account.Currency),
+		DecimalAvailableBalance: util.NewMoney(account.AvailableBalance, account.Currency),
+	}
+}
+
 type createAccountRequest struct {
 	Currency string `json:"currency" binding:"required,currency"`
+	Product  string `json:"product" binding:"omitempty,alphanum,max=50"`
+	Nickname string `json:"nickname" binding:"omitempty,max=50"`
 }
 
 func (server *Server) createAccount(ctx *gin.Context) {
@@ -22,27 +39,58 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
+	if req.Product == "" {
+		req.Product = db.DefaultAccountProduct
+	}
+
 	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
 	arg := db.CreateAccountParams{
 		Owner: authPayload.Username,
 		Currency: req.Currency,
 		Balance: 0,
+		Product: sql.NullString{String: req.Product, Valid: true},
+		Nickname: sql.NullString{String: req.Nickname, Valid: req.Nickname != ""},
 	}
 
-	account, err := server.store.CreateAccount(ctx, arg)
+	// the random account number may already belong to another account, then another one is tried
+	var account db.Account
+	var err error
+	for attempt := 0; attempt < accountNumberAttempts; attempt++ {
+		var number string
+		number, err = util.NewAccountNumber(server.config.BankCountryCode, server.config.BankCode)
+		if err != nil {
+			break
+		}
+
+		arg.Number = sql.NullString{String: number, Valid: true}
+		account, err = server.store.CreateAccountTx(ctx, arg)
+		if !accountNumberTaken(err) {
+			break
+		}
+	}
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
+		// running out of account numbers to try is the bank's problem, not the user's
+		if pqErr, ok := err.(*pq.Error); ok && !accountNumberTaken(err) {
 			switch pqErr.Code.Name() {
 			case "foreign_key_violation", "unique_violation": 
 				ctx.JSON(http.StatusForbidden, errorResponse(err))
 				return
 			}
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		storeErrorResponse(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, account)
+	ctx.JSON(http.StatusCreated, newAccountResponse(account))
+}
+
+// accountNumberAttempts is how many random account numbers are tried for a new account before giving up
+const accountNumberAttempts = 3
+
+// accountNumberTaken reports whether opening an account failed because its account number belongs to another account
+func accountNumberTaken(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code.Name() == "unique_violation" && pqErr.Constraint == "accounts_number_key"
 }
 
 type getAccountRequest struct {
@@ -56,7 +104,28 @@ func (server *Server) getAccount(ctx *gin.Context) {
 		return
 	}
 
-	account, err := server.store.GetAccount(ctx, req.ID)
+	account, valid := server.authorizeAccount(ctx, req.ID, viewAccount)
+	if !valid {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+type getAccountByNumberRequest struct {
+	Number string `uri:"number" binding:"required,account_number"`
+}
+
+// getAccountByNumber looks an account up by its public account number
+// A mistyped number fails its check digits and is a bad request, without looking for an account that may not be the one meant
+func (server *Server) getAccountByNumber(ctx *gin.Context) {
+	var req getAccountByNumberRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccountByNumber(ctx, req.Number)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -66,14 +135,50 @@ func (server *Server) getAccount(ctx *gin.Context) {
 		return
 	}
 
-	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	if !server.permitted(ctx, account, viewAccount) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+type setAccountNicknameRequest struct {
+	Nickname string `json:"nickname" binding:"max=50"`
+}
+
+// setAccountNickname names the user's account so they can tell it apart from their others, an empty nickname removes it
+func (server *Server) setAccountNickname(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req setAccountNicknameRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, manageAccount)
+	if !valid {
+		return
+	}
+
+	account, err := server.store.UpdateAccountNickname(ctx, db.UpdateAccountNicknameParams{
+		ID: account.ID,
+		Nickname: sql.NullString{String: req.Nickname, Valid: req.Nickname != ""},
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
 type listAccountsRequest struct {
@@ -90,7 +195,7 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 
 	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
 	arg := db.ListAccountsParams{
-		Owner: authPayload.Username,
+		Username: authPayload.Username,
 		Limit: req.PageSize,
 		Offset: (req.PageID - 1) * req.PageSize,
 	}
@@ -100,44 +205,114 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
+	rsp := make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		rsp[i] = newAccountResponse(account)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 }
 
 type deleteAccountRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
+type closeAccountRequest struct {
+	Reason string `form:"reason" binding:"max=200"`
+}
+
+// closedByOwner is the reason recorded when an owner closes an account without giving one
+const closedByOwner = "closed by the owner"
+
+// deleteAccount closes the user's account, it must be empty and its entries and transfers are kept
 func (server *Server) deleteAccount(ctx *gin.Context) {
 	var req deleteAccountRequest
-
 	if err := ctx.ShouldBindUri(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	account, err := server.store.GetAccount(ctx, req.ID)
+	var query closeAccountRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, req.ID, manageAccount)
+	if !valid {
+		return
+	}
+
+	if query.Reason == "" {
+		query.Reason = closedByOwner
+	}
+
+	_, err := server.store.ChangeAccountStatusTx(ctx, db.ChangeAccountStatusTxParams{
+		AccountID: account.ID,
+		Status: db.AccountClosed,
+		Actor: ctx.MustGet(autorizationPayloadKey).(*token.Payload).Username,
+		Reason: query.Reason,
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, req.ID)
+}
+
+type changeAccountStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active frozen closed"`
+	Reason string `json:"reason" binding:"required,max=200"`
+}
+
+// changeAccountStatus freezes, unfreezes or closes an account on behalf of the bank
+func (server *Server) changeAccountStatus(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req changeAccountStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
 	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	result, err := server.store.ChangeAccountStatusTx(ctx, db.ChangeAccountStatusTxParams{
+		AccountID: uri.ID,
+		Status: req.Status,
+		Actor: authPayload.Username,
+		Reason: req.Reason,
+	})
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// listAccountStatusChanges lists every status the user's account went through, oldest first
+func (server *Server) listAccountStatusChanges(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, viewAccount)
+	if !valid {
 		return
 	}
 
-	err = server.store.DeleteAccount(ctx, account.ID)
+	changes, err := server.store.ListAccountStatusChanges(ctx, account.ID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, req.ID)
+	ctx.JSON(http.StatusOK, changes)
 }
-
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/token"
+)
+
+// accountPermission is something a member of an account may be allowed to do with it
+type accountPermission int
+
+const (
+	// viewAccount lets a member see the account, its entries, transfers and statements
+	viewAccount accountPermission = iota
+	// payFromAccount lets a member move money out of the account
+	payFromAccount
+	// manageAccount lets a member close the account and choose its members and approvers
+	manageAccount
+)
+
+func (permission accountPermission) String() string {
+	switch permission {
+	case viewAccount:
+		return "view it"
+	case payFromAccount:
+		return "pay from it"
+	case manageAccount:
+		return "manage it"
+	}
+
+	return "access it"
+}
+
+// memberPermissions are the permissions each role of an account member grants
+var memberPermissions = map[string][]accountPermission{
+	db.MemberOwner:   {viewAccount, payFromAccount, manageAccount},
+	db.MemberCoOwner: {viewAccount, payFromAccount, manageAccount},
+	db.MemberViewer:  {viewAccount},
+	db.MemberPayer:   {viewAccount, payFromAccount},
+}
+
+// authorizeAccount loads an account and checks that the authenticated user may act on it with the permission
+// It writes the error response and returns false when the account can't be loaded or the user may not
+func (server *Server) authorizeAccount(ctx *gin.Context, id int64, permission accountPermission) (db.Account, bool) {
+	account, err := server.store.GetAccount(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return account, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+
+	return account, server.permitted(ctx, account, permission)
+}
+
+// permitted is like authorizeAccount for an account that is already loaded
+func (server *Server) permitted(ctx *gin.Context, account db.Account, permission accountPermission) bool {
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	status, err := server.checkAccountPermission(ctx, account, authPayload.Username, permission)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return false
+	}
+
+	return true
+}
+
+// permittedEither is like permitted but lets the user through with the permission on either of two accounts,
+// as for the transfers and holds between them
+func (server *Server) permittedEither(ctx *gin.Context, account1 db.Account, account2 db.Account, permission accountPermission) bool {
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	status, err := server.checkAccountPermission(ctx, account1, authPayload.Username, permission)
+	if err == nil {
+		return true
+	}
+
+	if status != http.StatusInternalServerError {
+		status2, err2 := server.checkAccountPermission(ctx, account2, authPayload.Username, permission)
+		if err2 == nil {
+			return true
+		}
+		// a member of the first account who lacks the permission hears about that rather than about the second account
+		if status2 != http.StatusUnauthorized {
+			status, err = status2, err2
+		}
+	}
+
+	ctx.JSON(status, errorResponse(err))
+	return false
+}
+
+// checkAccountPermission checks that a user may act on an account with the permission
+// It returns the response status to fail the request with when they may not: unauthorized for users who aren't members
+// of the account and forbidden for members whose role doesn't grant the permission
+func (server *Server) checkAccountPermission(ctx *gin.Context, account db.Account, username string, permission accountPermission) (int, error) {
+	role, err := server.accountRole(ctx, account, username)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if role == "" {
+		return http.StatusUnauthorized, errors.New("account doesn't belong to the authenticated user")
+	}
+
+	for _, granted := range memberPermissions[role] {
+		if granted == permission {
+			return http.StatusOK, nil
+		}
+	}
+
+	return http.StatusForbidden, fmt.Errorf("the %s of an account is not allowed to %s", role, permission)
+}
+
+// accountRole returns the role of a user on an account, or an empty role if the user isn't a member
+// The user who opened the account is always its owner, without looking it up
+func (server *Server) accountRole(ctx *gin.Context, account db.Account, username string) (string, error) {
+	if account.Owner == username {
+		return db.MemberOwner, nil
+	}
+
+	member, err := server.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: account.ID,
+		Username: username,
+	})
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return member.Role, err
+}
+
+type addAccountMemberRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Role     string `json:"role" binding:"required,oneof=co_owner viewer payer"`
+}
+
+// addAccountMember shares the user's account with another user, or changes the role of a user it is already shared with
+func (server *Server) addAccountMember(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req addAccountMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, manageAccount)
+	if !valid {
+		return
+	}
+
+	if req.Username == account.Owner {
+		err := errors.New("the owner of an account can't be given another role")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	member, err := server.store.UpsertAccountMember(ctx, db.UpsertAccountMemberParams{
+		AccountID: account.ID,
+		Username: req.Username,
+		Role: req.Role,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+// listAccountMembers lists the users the account is shared with and their roles, its owner first
+func (server *Server) listAccountMembers(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, viewAccount)
+	if !valid {
+		return
+	}
+
+	members, err := server.store.ListAccountMembers(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, members)
+}
+
+type removeAccountMemberRequest struct {
+	ID       int64  `uri:"id" binding:"required,min=1"`
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// removeAccountMember stops sharing the user's account with another user
+// The owner who opened the account can't be removed
+func (server *Server) removeAccountMember(ctx *gin.Context) {
+	var uri removeAccountMemberRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, manageAccount)
+	if !valid {
+		return
+	}
+
+	if uri.Username == account.Owner {
+		err := errors.New("the owner of an account can't be removed from it")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	count, err := server.store.DeleteAccountMember(ctx, db.DeleteAccountMemberParams{
+		AccountID: account.ID,
+		Username: uri.Username,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if count == 0 {
+		err := fmt.Errorf("%s is not a member of the account", uri.Username)
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+)
+
+type addAccountApproverRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+}
+
+// addAccountApprover grants another user the right to approve or reject the transfers out of the user's account
+// that are above its approval threshold
+func (server *Server) addAccountApprover(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req addAccountApproverRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, manageAccount)
+	if !valid {
+		return
+	}
+
+	if req.Username == account.Owner {
+		err := errors.New("the owner of an account can't approve its transfers")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	approver, err := server.store.CreateAccountApprover(ctx, db.CreateAccountApproverParams{
+		AccountID: account.ID,
+		Username: req.Username,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation", "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, approver)
+}
+
+// listAccountApprovers lists the users who can approve the transfers out of the user's account
+func (server *Server) listAccountApprovers(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, viewAccount)
+	if !valid {
+		return
+	}
+
+	approvers, err := server.store.ListAccountApprovers(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, approvers)
+}
+
+type removeAccountApproverRequest struct {
+	ID       int64  `uri:"id" binding:"required,min=1"`
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// removeAccountApprover takes back the right to approve the transfers out of the user's account
+// Requests the approver already decided on stay as they are
+func (server *Server) removeAccountApprover(ctx *gin.Context) {
+	var uri removeAccountApproverRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, manageAccount)
+	if !valid {
+		return
+	}
+
+	err := server.store.DeleteAccountApprover(ctx, db.DeleteAccountApproverParams{
+		AccountID: account.ID,
+		Username: uri.Username,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+type setApprovalThresholdRequest struct {
+	// ApprovalThreshold of zero lets every transfer out of the account through without approval
+	ApprovalThreshold util.Amount `json:"approval_threshold" binding:"min=0"`
+}
+
+// setApprovalThreshold sets the amount above which transfers out of an account wait for one of its approvers
+// It is up to a banker, so the owner who makes the transfers can't lift it on their own
+func (server *Server) setApprovalThreshold(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req setApprovalThresholdRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	threshold, valid := validAmount(ctx, req.ApprovalThreshold, account.Currency)
+	if !valid {
+		return
+	}
+
+	account, err = server.store.UpdateAccountApprovalThreshold(ctx, db.UpdateAccountApprovalThresholdParams{
+		ID: account.ID,
+		ApprovalThreshold: sql.NullInt64{Int64: threshold, Valid: threshold > 0},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/token"
+	"github.com/nokkvi/simplebank/util"
+)
+
+type batchTransferLeg struct {
+	FromAccountID int64       `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64       `json:"to_account_id" binding:"required,min=1"`
+	Amount        util.Amount `json:"amount" binding:"required,gt=0"`
+	Currency      string      `json:"currency" binding:"required,currency"`
+	transferDetailsRequest
+}
+
+type batchTransferRequest struct {
+	Transfers []batchTransferLeg `json:"transfers" binding:"required,min=1,max=100,dive"`
+}
+
+// batchTransferResponse is the result of every leg of a batch, in the order of the legs
+type batchTransferResponse struct {
+	Transfers []transferTxResponse `json:"transfers"`
+}
+
+func (server *Server) createBatchTransfer(ctx *gin.Context) {
+	var req batchTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var header idempotencyHeader
+	if err := ctx.ShouldBindHeader(&header); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+
+	// accounts are loaded once, however many legs they appear in
+	accounts := make(map[int64]db.Account)
+	arg := db.BatchTransferTxParams{
+		Legs: make([]db.BatchTransferLeg, len(req.Transfers)),
+		InitiatedBy: authPayload.Username,
+		DefaultLimits: server.defaultTransferLimits(),
+	}
+
+	for i, leg := range req.Transfers {
+		money, err := leg.Amount.Money(leg.Currency)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, legErrorResponse(i, err))
+			return
+		}
+
+		// the request hashes the same for its idempotency key whether the amounts were decimals or in minor units
+		req.Transfers[i].Amount = util.MinorAmount(money.Amount)
+
+		status, err := server.checkBatchLeg(ctx, accounts, leg, money.Amount, authPayload.Username)
+		if err != nil {
+			ctx.JSON(status, legErrorResponse(i, err))
+			return
+		}
+
+		arg.Legs[i] = db.BatchTransferLeg{
+			FromAccountID: leg.FromAccountID,
+			ToAccountID: leg.ToAccountID,
+			Amount: money.Amount,
+			TransferDetails: leg.details(),
+		}
+	}
+
+	if header.Key != "" {
+		arg.IdempotencyKey = &db.IdempotencyKeyParams{
+			Username: authPayload.Username,
+			Key: header.Key,
+			RequestHash: hashRequest(req),
+		}
+	}
+
+	result, err := server.store.BatchTransferTx(ctx, arg)
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	rsp := batchTransferResponse{Transfers: make([]transferTxResponse, len(result.Transfers))}
+	for i, transfer := range result.Transfers {
+		rsp.Transfers[i] = newTransferTxResponse(transfer)
+	}
+
+	ctx.JSON(http.StatusCreated, rsp)
+}
+
+// checkBatchLeg checks that the user can pay from the from account of a leg and that both accounts are in the leg's currency
+// Legs above the from account's approval threshold aren't allowed, they can't wait for an approver without holding up the batch
+// It returns the response status to fail the batch with when they aren't
+func (server *Server) checkBatchLeg(ctx *gin.Context, accounts map[int64]db.Account, leg batchTransferLeg, amount int64, username string) (int, error) {
+	fromAccount, status, err := server.batchAccount(ctx, accounts, leg.FromAccountID, leg.Currency)
+	if err != nil {
+		return status, err
+	}
+
+	status, err = server.checkAccountPermission(ctx, fromAccount, username, payFromAccount)
+	if err != nil {
+		return status, err
+	}
+
+	if db.NeedsApproval(fromAccount, amount) {
+		return http.StatusUnprocessableEntity, errApprovalRequired
+	}
+
+	_, status, err = server.batchAccount(ctx, accounts, leg.ToAccountID, leg.Currency)
+	return status, err
+}
+
+func (server *Server) batchAccount(ctx *gin.Context, accounts map[int64]db.Account, accountID int64, currency string) (db.Account, int, error) {
+	account, ok := accounts[accountID]
+	if !ok {
+		var err error
+		account, err = server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return account, http.StatusNotFound, err
+			}
+			return account, http.StatusInternalServerError, err
+		}
+		accounts[accountID] = account
+	}
+
+	if account.Currency != currency {
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", accountID, account.Currency, currency)
+		return account, http.StatusBadRequest, err
+	}
+
+	return account, http.StatusOK, nil
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/token"
+	"github.com/nokkvi/simplebank/util"
+)
+
+type cashMovementRequest struct {
+	AccountID int64       `json:"account_id" binding:"required,min=1"`
+	Amount    util.Amount `json:"amount" binding:"required,gt=0"`
+	Fee       util.Amount `json:"fee" binding:"min=0"`
+	Currency  string      `json:"currency" binding:"required,currency"`
+	transferDetailsRequest
+}
+
+// cashMovementResponse is a deposit or withdrawal with its amounts also given as decimals in the currency of the account
+type cashMovementResponse struct {
+	Movement    cashMovement        `json:"movement"`
+	Transfer    transferTxResponse  `json:"transfer"`
+	FeeTransfer *transferTxResponse `json:"fee_transfer"`
+}
+
+// cashMovement is a cash movement with its amount and fee also given as decimals
+type cashMovement struct {
+	db.CashMovement
+	DecimalAmount util.Money `json:"decimalAmount"`
+	DecimalFee    util.Money `json:"decimalFee"`
+}
+
+func newCashMovementResponse(result db.CashTxResult, currency string) cashMovementResponse {
+	rsp := cashMovementResponse{
+		Movement: cashMovement{
+			CashMovement: result.Movement,
+			DecimalAmount: util.NewMoney(result.Movement.Amount, currency),
+			DecimalFee: util.NewMoney(result.Movement.Fee, currency),
+		},
+		Transfer: newTransferTxResponse(result.Transfer),
+	}
+	if result.FeeTransfer != nil {
+		feeTransfer := newTransferTxResponse(*result.FeeTransfer)
+		rsp.FeeTransfer = &feeTransfer
+	}
+
+	return rsp
+}
+
+// createDeposit pays cash taken in at the bank into a customer's account
+func (server *Server) createDeposit(ctx *gin.Context) {
+	server.moveCash(ctx, db.CashDeposit)
+}
+
+// createWithdrawal pays cash out of a customer's account
+func (server *Server) createWithdrawal(ctx *gin.Context) {
+	server.moveCash(ctx, db.CashWithdrawal)
+}
+
+// moveCash posts a deposit or withdrawal against the bank's cash account on behalf of the banker handling the cash
+func (server *Server) moveCash(ctx *gin.Context, kind string) {
+	var req cashMovementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var header idempotencyHeader
+	if err := ctx.ShouldBindHeader(&header); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	amount, valid := validAmount(ctx, req.Amount, req.Currency)
+	if !valid {
+		return
+	}
+
+	fee, valid := validAmount(ctx, req.Fee, req.Currency)
+	if !valid {
+		return
+	}
+
+	// the request hashes the same for its idempotency key whether the amounts were decimals or in minor units
+	req.Amount = util.MinorAmount(amount)
+	req.Fee = util.MinorAmount(fee)
+
+	account, valid := server.validAccount(ctx, req.AccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	arg := db.CashTxParams{
+		AccountID: account.ID,
+		Amount: amount,
+		Fee: fee,
+		Banker: authPayload.Username,
+		TransferDetails: req.details(),
+	}
+
+	if header.Key != "" {
+		// the same key can't replay a deposit as a withdrawal
+		arg.IdempotencyKey = &db.IdempotencyKeyParams{
+			Username: authPayload.Username,
+			Key: header.Key,
+			RequestHash: hashRequest(gin.H{"kind": kind, "request": req}),
+		}
+	}
+
+	var result db.CashTxResult
+	var err error
+	if kind == db.CashDeposit {
+		result, err = server.store.DepositTx(ctx, arg)
+	} else {
+		result, err = server.store.WithdrawTx(ctx, arg)
+	}
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newCashMovementResponse(result, account.Currency))
+}
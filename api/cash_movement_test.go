@@ -0,0 +1,331 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDeposit(t *testing.T) {
+	user, _ := randomUser(t)
+	banker, _ := randomUser(t)
+	banker.Role = util.BankerRole
+	account := randomAccount(user.Username)
+	account.Currency = util.USD
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			body:     gin.H{"account_id": account.ID, "amount": 500, "fee": 2, "currency": account.Currency, "memo": "cash at the counter"},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.CashTxParams{
+					AccountID:       account.ID,
+					Amount:          500,
+					Fee:             2,
+					Banker:          banker.Username,
+					TransferDetails: db.TransferDetails{Memo: "cash at the counter"},
+				}
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.CashTxResult{}, nil)
+				store.EXPECT().WithdrawTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:     "DecimalAmounts",
+			body:     gin.H{"account_id": account.ID, "amount": "5.00", "fee": "0.02", "currency": account.Currency},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.CashTxParams{
+					AccountID: account.ID,
+					Amount:    500,
+					Fee:       2,
+					Banker:    banker.Username,
+				}
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.CashTxResult{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:     "TooManyDecimals",
+			body:     gin.H{"account_id": account.ID, "amount": "5.00", "fee": "0.025", "currency": account.Currency},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "CurrencyMismatch",
+			body:     gin.H{"account_id": account.ID, "amount": 500, "currency": util.EUR},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "AccountNotFound",
+			body:     gin.H{"account_id": account.ID, "amount": 500, "currency": account.Currency},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "SystemAccount",
+			body:     gin.H{"account_id": account.ID, "amount": 500, "currency": account.Currency},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CashTxResult{}, db.ErrSystemAccount)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyMatchErrorCode(t, recorder.Body, errCodeSystemAccount)
+			},
+		},
+		{
+			name:     "NegativeFee",
+			body:     gin.H{"account_id": account.ID, "amount": 500, "fee": -1, "currency": account.Currency},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Depositor",
+			body:     gin.H{"account_id": account.ID, "amount": 500, "currency": account.Currency},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/deposits", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, autorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCreateWithdrawal(t *testing.T) {
+	user, _ := randomUser(t)
+	banker, _ := randomUser(t)
+	banker.Role = util.BankerRole
+	account := randomAccount(user.Username)
+
+	testCases := []struct {
+		name           string
+		body           gin.H
+		idempotencyKey string
+		buildStubs     func(store *mockdb.MockStore)
+		checkResponse  func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"account_id": account.ID, "amount": 500, "currency": account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.CashTxParams{
+					AccountID: account.ID,
+					Amount:    500,
+					Banker:    banker.Username,
+				}
+				store.EXPECT().WithdrawTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.CashTxResult{}, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:           "IdempotencyKey",
+			body:           gin.H{"account_id": account.ID, "amount": 500, "currency": account.Currency},
+			idempotencyKey: "withdrawal-1",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().WithdrawTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(_ interface{}, arg db.CashTxParams) (db.CashTxResult, error) {
+						require.NotNil(t, arg.IdempotencyKey)
+						require.Equal(t, banker.Username, arg.IdempotencyKey.Username)
+						require.Equal(t, "withdrawal-1", arg.IdempotencyKey.Key)
+						require.NotEmpty(t, arg.IdempotencyKey.RequestHash)
+						return db.CashTxResult{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			body: gin.H{"account_id": account.ID, "amount": 500, "fee": 2, "currency": account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().WithdrawTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CashTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyMatchErrorCode(t, recorder.Body, errCodeInsufficientFunds)
+			},
+		},
+		{
+			name: "ZeroAmount",
+			body: gin.H{"account_id": account.ID, "amount": 0, "currency": account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().WithdrawTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/withdrawals", bytes.NewReader(data))
+			require.NoError(t, err)
+			if tc.idempotencyKey != "" {
+				request.Header.Set("Idempotency-Key", tc.idempotencyKey)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, autorizationTypeBearer, banker.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCashMovementResponseDecimalAmounts(t *testing.T) {
+	account := db.Account{ID: 1, Currency: util.USD, Balance: 1000, AvailableBalance: 1000}
+	cash := db.Account{ID: 2, Currency: util.USD}
+	fees := db.Account{ID: 3, Currency: util.USD}
+
+	data, err := json.Marshal(newCashMovementResponse(db.CashTxResult{
+		Movement: db.CashMovement{ID: 4, Kind: db.CashWithdrawal, AccountID: account.ID, Amount: 1050, Fee: 150},
+		Transfer: db.TransferTxResult{
+			Transfer: db.Transfer{FromAccountID: account.ID, ToAccountID: cash.ID, Amount: 1050, ToAmount: 1050},
+			FromAccount: account,
+			ToAccount: cash,
+		},
+		FeeTransfer: &db.TransferTxResult{
+			Transfer: db.Transfer{FromAccountID: account.ID, ToAccountID: fees.ID, Amount: 150, ToAmount: 150},
+			FromAccount: account,
+			ToAccount: fees,
+		},
+	}, account.Currency))
+	require.NoError(t, err)
+
+	var rsp struct {
+		Movement struct {
+			DecimalAmount string `json:"decimalAmount"`
+			DecimalFee    string `json:"decimalFee"`
+		} `json:"movement"`
+		Transfer struct {
+			Transfer struct {
+				DecimalAmount string `json:"decimalAmount"`
+			} `json:"transfer"`
+		} `json:"transfer"`
+		FeeTransfer *struct {
+			Transfer struct {
+				DecimalAmount string `json:"decimalAmount"`
+			} `json:"transfer"`
+		} `json:"fee_transfer"`
+	}
+	require.NoError(t, json.Unmarshal(data, &rsp))
+	require.Equal(t, "10.50", rsp.Movement.DecimalAmount)
+	require.Equal(t, "1.50", rsp.Movement.DecimalFee)
+	require.Equal(t, "10.50", rsp.Transfer.Transfer.DecimalAmount)
+	require.NotNil(t, rsp.FeeTransfer)
+	require.Equal(t, "1.50", rsp.FeeTransfer.Transfer.DecimalAmount)
+}
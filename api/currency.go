@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+)
+
+// listCurrencies lists every currency the bank knows about, enabled or not, with its minor units
+func (server *Server) listCurrencies(ctx *gin.Context) {
+	currencies, err := server.store.ListCurrencies(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, currencies)
+}
+
+type currencyURI struct {
+	Code string `uri:"code" binding:"required"`
+}
+
+type upsertCurrencyRequest struct {
+	NumericCode int32  `json:"numeric_code" binding:"required"`
+	MinorUnits  int32  `json:"minor_units"`
+	Symbol      string `json:"symbol" binding:"max=8"`
+	Enabled     *bool  `json:"enabled" binding:"required"`
+}
+
+// upsertCurrency adds a currency or changes it, and updates the currency registry so the currency validator sees it at once
+// Other servers see the change the next time they refresh their registry
+// The minor units of a currency can't change once accounts are held in it
+func (server *Server) upsertCurrency(ctx *gin.Context) {
+	var uri currencyURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req upsertCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	registered := util.Currency{
+		Code: uri.Code,
+		NumericCode: req.NumericCode,
+		MinorUnits: req.MinorUnits,
+		Symbol: req.Symbol,
+		Enabled: *req.Enabled,
+	}
+	if err := registered.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	currency, err := server.store.UpsertCurrencyTx(ctx, db.UpsertCurrencyParams{
+		Code: registered.Code,
+		NumericCode: registered.NumericCode,
+		MinorUnits: registered.MinorUnits,
+		Symbol: registered.Symbol,
+		Enabled: registered.Enabled,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	util.PutCurrency(currency.Registered())
+	ctx.JSON(http.StatusOK, currency)
+}
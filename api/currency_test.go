@@ -0,0 +1,191 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertCurrency(t *testing.T) {
+	user, _ := randomUser(t)
+	banker, _ := randomUser(t)
+	banker.Role = util.BankerRole
+
+	jpy := db.Currency{Code: "JPY", NumericCode: 392, MinorUnits: 0, Symbol: "¥", Enabled: true}
+
+	// the other tests only know the currencies the bank started with
+	currencies := util.Currencies()
+	t.Cleanup(func() { util.SetCurrencies(currencies) })
+
+	testCases := []struct {
+		name          string
+		code          string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			code:     "JPY",
+			body:     gin.H{"numeric_code": 392, "minor_units": 0, "symbol": "¥", "enabled": true},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+
+				arg := db.UpsertCurrencyParams{
+					Code:        "JPY",
+					NumericCode: 392,
+					MinorUnits:  0,
+					Symbol:      "¥",
+					Enabled:     true,
+				}
+				store.EXPECT().UpsertCurrencyTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(jpy, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				// the currency validator accepts it straight away
+				require.True(t, util.IsSupportedCurrency("JPY"))
+			},
+		},
+		{
+			name:     "CurrencyInUse",
+			code:     util.USD,
+			body:     gin.H{"numeric_code": 840, "minor_units": 3, "symbol": "$", "enabled": true},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertCurrencyTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Currency{}, db.ErrCurrencyInUse)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchErrorCode(t, recorder.Body, errCodeCurrencyInUse)
+
+				// the registry keeps the minor units the accounts were opened with
+				currency, ok := util.GetCurrency(util.USD)
+				require.True(t, ok)
+				require.Equal(t, int32(2), currency.MinorUnits)
+			},
+		},
+		{
+			name:     "InvalidCode",
+			code:     "yen",
+			body:     gin.H{"numeric_code": 392, "minor_units": 0, "enabled": true},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertCurrencyTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "TooManyMinorUnits",
+			code:     "KWD",
+			body:     gin.H{"numeric_code": 414, "minor_units": 5, "enabled": true},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertCurrencyTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "MissingEnabled",
+			code:     "KWD",
+			body:     gin.H{"numeric_code": 414, "minor_units": 3},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertCurrencyTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Depositor",
+			code:     "JPY",
+			body:     gin.H{"numeric_code": 392, "minor_units": 0, "enabled": true},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().UpsertCurrencyTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/currencies/%s", tc.code)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, autorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListCurrencies(t *testing.T) {
+	user, _ := randomUser(t)
+
+	currencies := []db.Currency{
+		{Code: util.CAD, NumericCode: 124, MinorUnits: 2, Symbol: "CA$", Enabled: true},
+		{Code: "KWD", NumericCode: 414, MinorUnits: 3, Symbol: "KD", Enabled: false},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return(currencies, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/currencies", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, autorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got []db.Currency
+	err = json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Equal(t, currencies, got)
+}
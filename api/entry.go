@@ -2,14 +2,28 @@ package api
 
 import (
 	"database/sql"
-	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/nokkvi/simplebank/db/sqlc"
-	"github.com/nokkvi/simplebank/token"
+	"github.com/nokkvi/simplebank/util"
 )
 
+// entryResponse is an entry with its amount also given as a decimal in the currency of its account
+type entryResponse struct {
+	db.Entry
+	Currency      string     `json:"currency"`
+	DecimalAmount util.Money `json:"decimalAmount"`
+}
+
+func newEntryResponse(entry db.Entry, account db.Account) entryResponse {
+	return entryResponse{
+		Entry: entry,
+		Currency: account.Currency,
+		DecimalAmount: util.NewMoney(entry.Amount, account.Currency),
+	}
+}
+
 type GetEntryRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
@@ -42,22 +56,22 @@ func (server *Server) getEntry(ctx *gin.Context) {
 		return
 	}
 	
-	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		err := errors.New("Account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	if !server.permitted(ctx, account, viewAccount) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, entry)
+	ctx.JSON(http.StatusOK, newEntryResponse(entry, account))
 }
 
 type ListEntriesRequest struct {
 	AccountID int64 `form:"account_id" binding:"required,min=1"`
+	Search    string `form:"search" binding:"max=140"`
 	PageID     int32 `form:"page_id" binding:"required,min=1"`
 	PageSize    int32 `form:"page_size" binding:"required,min=5,max=10"`
 }
 
+// listEntries lists the entries of one of the user's accounts
+// The search matches entries whose memo or reference contains it, ignoring case
 func (server *Server) listEntries(ctx *gin.Context) {
 	var req ListEntriesRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
@@ -76,17 +90,15 @@ func (server *Server) listEntries(ctx *gin.Context) {
 		return
 	}
 	
-	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		err := errors.New("Account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	if !server.permitted(ctx, account, viewAccount) {
 		return
 	}
 
 	arg := db.ListEntriesParams{
 		AccountID: req.AccountID,
-		Limit: req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		Search: searchPattern(req.Search),
+		PageLimit: req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
 	}
 	entries, err := server.store.ListEntries(ctx, arg)
 	if err != nil {
@@ -94,5 +106,10 @@ func (server *Server) listEntries(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, entries)
+	rsp := make([]entryResponse, len(entries))
+	for i, entry := range entries {
+		rsp[i] = newEntryResponse(entry, account)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 }
\ No newline at end of file
@@ -43,7 +43,7 @@ func TestGetEntry(t *testing.T) {
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchEntry(t, recorder.Body, entry)
+				requireBodyMatchEntry(t, recorder.Body, entry, account)
 			},
 		},
 		{
@@ -141,7 +141,7 @@ func TestListEntries(t *testing.T) {
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchEntries(t, recorder.Body, entries[:5])
+				requireBodyMatchEntries(t, recorder.Body, entries[:5], account)
 				require.Equal(t, len(entries[:5]), 5)
 			},
 		},
@@ -159,7 +159,7 @@ func TestListEntries(t *testing.T) {
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchEntries(t, recorder.Body, entries[45:])
+				requireBodyMatchEntries(t, recorder.Body, entries[45:], account)
 				require.Equal(t, len(entries[45:]), 4)
 			},
 		},
@@ -261,6 +261,9 @@ func randomEntry(accountID int64) db.Entry {
 		AccountID: accountID,
 		Amount: util.RandomMoney(),
 		CreatedAt: util.RandomDate(),
+		Memo: util.RandomString(12),
+		Reference: util.RandomString(8),
+		Metadata: json.RawMessage(`{}`),
 	}
 }
 
@@ -274,22 +277,42 @@ func randomEntries(accountID int64, size int) []db.Entry {
 	return entries
 }
 
-func requireBodyMatchEntry(t *testing.T, body *bytes.Buffer, entry db.Entry) {
+func requireBodyMatchEntry(t *testing.T, body *bytes.Buffer, entry db.Entry, account db.Account) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
 
-	var gotEntry db.Entry
-	err = json.Unmarshal(data, &gotEntry)
+	want, err := json.Marshal(newEntryResponse(entry, account))
 	require.NoError(t, err)
-	require.Equal(t, entry, gotEntry)
+	require.JSONEq(t, string(want), string(data))
 }
 
-func requireBodyMatchEntries(t *testing.T, body *bytes.Buffer, entries []db.Entry) {
+func requireBodyMatchEntries(t *testing.T, body *bytes.Buffer, entries []db.Entry, account db.Account) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
 
-	var gotEntries []db.Entry
-	err = json.Unmarshal(data, &gotEntries)
+	rsp := make([]entryResponse, len(entries))
+	for i, entry := range entries {
+		rsp[i] = newEntryResponse(entry, account)
+	}
+	want, err := json.Marshal(rsp)
 	require.NoError(t, err)
-	require.Equal(t, entries, gotEntries)
+	require.JSONEq(t, string(want), string(data))
+}
+
+func TestEntryResponseDecimalAmount(t *testing.T) {
+	account := db.Account{ID: 1, Currency: util.USD}
+	entry := db.Entry{ID: 2, AccountID: account.ID, Amount: -1050}
+
+	data, err := json.Marshal(newEntryResponse(entry, account))
+	require.NoError(t, err)
+
+	var rsp struct {
+		Amount        int64  `json:"amount"`
+		Currency      string `json:"currency"`
+		DecimalAmount string `json:"decimalAmount"`
+	}
+	require.NoError(t, json.Unmarshal(data, &rsp))
+	require.Equal(t, int64(-1050), rsp.Amount)
+	require.Equal(t, util.USD, rsp.Currency)
+	require.Equal(t, "-10.50", rsp.DecimalAmount)
 }
\ No newline at end of file
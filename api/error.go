@@ -0,0 +1,100 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+)
+
+// Stable error codes returned to clients alongside the error message
+const (
+	errCodeInsufficientFunds         = "insufficient_funds"
+	errCodeIdempotencyKeyMismatch    = "idempotency_key_mismatch"
+	errCodeQuoteUsed                 = "quote_used"
+	errCodeQuoteExpired              = "quote_expired"
+	errCodeAmountTooSmall            = "amount_too_small"
+	errCodeReversalExceedsTransfer   = "reversal_exceeds_transfer"
+	errCodeReverseReversal           = "reverse_reversal"
+	errCodeHoldNotAuthorized         = "hold_not_authorized"
+	errCodeHoldExpired               = "hold_expired"
+	errCodeCaptureExceedsHold        = "capture_exceeds_hold"
+	errCodeTransferLimitExceeded     = "transfer_limit_exceeded"
+	errCodeApprovalRequired          = "approval_required"
+	errCodeTransferRequestNotPending = "transfer_request_not_pending"
+	errCodeAccountFrozen             = "account_frozen"
+	errCodeAccountClosed             = "account_closed"
+	errCodeAccountStatusTransition   = "account_status_transition"
+	errCodeAccountBalanceNotZero     = "account_balance_not_zero"
+	errCodeOverdraftInUse            = "overdraft_in_use"
+	errCodeSystemAccount             = "system_account"
+	errCodeProductAccountLimit       = "product_account_limit"
+	errCodeCurrencyInUse             = "currency_in_use"
+
+	errCodeScheduledTransferInactive = "scheduled_transfer_inactive"
+)
+
+// storeErrors maps the domain errors returned by the store to a response status and error code
+var storeErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{db.ErrInsufficientFunds, http.StatusUnprocessableEntity, errCodeInsufficientFunds},
+	{db.ErrIdempotencyKeyMismatch, http.StatusConflict, errCodeIdempotencyKeyMismatch},
+	{db.ErrQuoteUsed, http.StatusUnprocessableEntity, errCodeQuoteUsed},
+	{db.ErrQuoteExpired, http.StatusUnprocessableEntity, errCodeQuoteExpired},
+	{db.ErrAmountTooSmall, http.StatusUnprocessableEntity, errCodeAmountTooSmall},
+	{db.ErrReversalExceedsTransfer, http.StatusUnprocessableEntity, errCodeReversalExceedsTransfer},
+	{db.ErrReverseReversal, http.StatusUnprocessableEntity, errCodeReverseReversal},
+	{db.ErrHoldNotAuthorized, http.StatusConflict, errCodeHoldNotAuthorized},
+	{db.ErrHoldExpired, http.StatusUnprocessableEntity, errCodeHoldExpired},
+	{db.ErrCaptureExceedsHold, http.StatusUnprocessableEntity, errCodeCaptureExceedsHold},
+	{db.ErrTransferLimitExceeded, http.StatusUnprocessableEntity, errCodeTransferLimitExceeded},
+	{db.ErrApprovalRequired, http.StatusUnprocessableEntity, errCodeApprovalRequired},
+	{db.ErrTransferRequestNotPending, http.StatusConflict, errCodeTransferRequestNotPending},
+	{db.ErrAccountFrozen, http.StatusUnprocessableEntity, errCodeAccountFrozen},
+	{db.ErrAccountClosed, http.StatusUnprocessableEntity, errCodeAccountClosed},
+	{db.ErrAccountStatusTransition, http.StatusConflict, errCodeAccountStatusTransition},
+	{db.ErrAccountBalanceNotZero, http.StatusUnprocessableEntity, errCodeAccountBalanceNotZero},
+	{db.ErrOverdraftInUse, http.StatusConflict, errCodeOverdraftInUse},
+	{db.ErrSystemAccount, http.StatusUnprocessableEntity, errCodeSystemAccount},
+	{db.ErrProductAccountLimit, http.StatusForbidden, errCodeProductAccountLimit},
+	{db.ErrCurrencyInUse, http.StatusConflict, errCodeCurrencyInUse},
+}
+
+// errorCodeResponse is like errorResponse but also carries a machine readable error code
+func errorCodeResponse(code string, err error) gin.H {
+	return gin.H{"error": err.Error(), "code": code}
+}
+
+// legErrorResponse is like errorResponse but also carries the index of the batch leg that failed
+func legErrorResponse(leg int, err error) gin.H {
+	return gin.H{"error": err.Error(), "leg": leg}
+}
+
+// storeErrorResponse writes the response for an error returned by a store transaction
+// Known domain errors get their own status and code, anything else is an internal error
+// Errors of a single leg of a batch transfer also carry the index of that leg
+func storeErrorResponse(ctx *gin.Context, err error) {
+	status, body := http.StatusInternalServerError, errorResponse(err)
+	if errors.Is(err, sql.ErrNoRows) {
+		status = http.StatusNotFound
+	}
+
+	for _, storeErr := range storeErrors {
+		if errors.Is(err, storeErr.err) {
+			status, body = storeErr.status, errorCodeResponse(storeErr.code, err)
+			break
+		}
+	}
+
+	var legErr *db.BatchLegError
+	if errors.As(err, &legErr) {
+		body["leg"] = legErr.Leg
+	}
+
+	ctx.JSON(status, body)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/token"
+	"github.com/nokkvi/simplebank/util"
+)
+
+func (server *Server) listExchangeRates(ctx *gin.Context) {
+	rates, err := server.store.ListExchangeRates(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rates)
+}
+
+type exchangeRateRequest struct {
+	FromCurrency string `json:"from_currency" binding:"required,currency"`
+	ToCurrency   string `json:"to_currency" binding:"required,currency,nefield=FromCurrency"`
+	Rate         string `json:"rate" binding:"required"`
+}
+
+type updateExchangeRatesRequest struct {
+	Rates []exchangeRateRequest `json:"rates" binding:"required,min=1,dive"`
+}
+
+func (server *Server) updateExchangeRates(ctx *gin.Context) {
+	var req updateExchangeRatesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	for _, rate := range req.Rates {
+		if _, err := util.ParseRate(rate.Rate); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	arg := db.UpsertExchangeRatesTxParams{
+		Rates: make([]db.UpsertExchangeRateParams, 0, len(req.Rates)),
+	}
+	for _, rate := range req.Rates {
+		arg.Rates = append(arg.Rates, db.UpsertExchangeRateParams{
+			FromCurrency: rate.FromCurrency,
+			ToCurrency: rate.ToCurrency,
+			Rate: rate.Rate,
+		})
+	}
+
+	rates, err := server.store.UpsertExchangeRatesTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rates)
+}
+
+type createExchangeQuoteRequest struct {
+	FromCurrency string `json:"from_currency" binding:"required,currency"`
+	ToCurrency   string `json:"to_currency" binding:"required,currency,nefield=FromCurrency"`
+}
+
+func (server *Server) createExchangeQuote(ctx *gin.Context) {
+	var req createExchangeQuoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rate, err := server.store.GetExchangeRate(ctx, db.GetExchangeRateParams{
+		FromCurrency: req.FromCurrency,
+		ToCurrency: req.ToCurrency,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	quote, err := server.store.CreateExchangeQuote(ctx, db.CreateExchangeQuoteParams{
+		ID: uuid.New(),
+		Username: authPayload.Username,
+		FromCurrency: rate.FromCurrency,
+		ToCurrency: rate.ToCurrency,
+		Rate: rate.Rate,
+		ExpiresAt: time.Now().Add(server.config.ExchangeQuoteDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, quote)
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/token"
+	"github.com/nokkvi/simplebank/util"
+)
+
+// holdResponse is a hold with its amounts also given as decimals in the currency of its accounts
+type holdResponse struct {
+	db.Hold
+	DecimalAmount         util.Money `json:"decimalAmount"`
+	DecimalCapturedAmount util.Money `json:"decimalCapturedAmount"`
+}
+
+func newHoldResponse(hold db.Hold, currency string) holdResponse {
+	return holdResponse{
+		Hold: hold,
+		DecimalAmount: util.NewMoney(hold.Amount, currency),
+		DecimalCapturedAmount: util.NewMoney(hold.CapturedAmount, currency),
+	}
+}
+
+// holdTxResponse is a hold together with the account it holds money of
+type holdTxResponse struct {
+	Hold    holdResponse    `json:"hold"`
+	Account accountResponse `json:"account"`
+}
+
+func newHoldTxResponse(result db.HoldTxResult) holdTxResponse {
+	return holdTxResponse{
+		Hold: newHoldResponse(result.Hold, result.Account.Currency),
+		Account: newAccountResponse(result.Account),
+	}
+}
+
+// captureHoldResponse is the transfer a hold was captured with together with the hold
+type captureHoldResponse struct {
+	transferTxResponse
+	Hold holdResponse `json:"hold"`
+}
+
+type authorizeHoldRequest struct {
+	FromAccountID int64       `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64       `json:"to_account_id" binding:"required,min=1"`
+	Amount        util.Amount `json:"amount" binding:"required,gt=0"`
+	Currency      string      `json:"currency" binding:"required,currency"`
+}
+
+// authorizeHold reserves money of the authenticated user's account for a later transfer to another account
+// The hold expires after the configured hold duration unless the recipient captures or voids it first
+func (server *Server) authorizeHold(ctx *gin.Context) {
+	var req authorizeHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	amount, valid := validAmount(ctx, req.Amount, req.Currency)
+	if !valid {
+		return
+	}
+
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	if !server.permitted(ctx, fromAccount, payFromAccount) {
+		return
+	}
+
+	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	result, err := server.store.AuthorizeHoldTx(ctx, db.AuthorizeHoldTxParams{
+		FromAccountID: req.FromAccountID,
+		ToAccountID: req.ToAccountID,
+		Amount: amount,
+		ExpiresAt: time.Now().Add(server.config.HoldDuration),
+		InitiatedBy: authPayload.Username,
+		DefaultLimits: server.defaultTransferLimits(),
+	})
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newHoldTxResponse(result))
+}
+
+type getHoldRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getHold(ctx *gin.Context) {
+	var req getHoldRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hold, fromAccount, toAccount, valid := server.holdAccounts(ctx, req.ID)
+	if !valid {
+		return
+	}
+
+	if !server.permittedEither(ctx, fromAccount, toAccount, viewAccount) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newHoldResponse(hold, fromAccount.Currency))
+}
+
+type captureHoldRequest struct {
+	// Amount is in the currency of the held account
+	Amount util.Amount `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// captureHold transfers the held money to the hold's to account, all of it unless an amount is given
+// Only the owner of the account the hold is for can capture it
+func (server *Server) captureHold(ctx *gin.Context) {
+	var uri getHoldRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// the body is optional, without one the whole hold is captured
+	var req captureHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hold, toAccount, valid := server.recipientHold(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	// a hold is only ever between accounts of the same currency
+	amount, valid := validAmount(ctx, req.Amount, toAccount.Currency)
+	if !valid {
+		return
+	}
+
+	result, err := server.store.CaptureHoldTx(ctx, db.CaptureHoldTxParams{
+		HoldID: hold.ID,
+		Amount: amount,
+		DefaultLimits: server.defaultTransferLimits(),
+	})
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, captureHoldResponse{
+		transferTxResponse: newTransferTxResponse(result.TransferTxResult),
+		Hold: newHoldResponse(result.Hold, toAccount.Currency),
+	})
+}
+
+// voidHold releases the held money without transferring any of it
+// Only the owner of the account the hold is for can void it, the owner of the held account has to wait for it to expire
+func (server *Server) voidHold(ctx *gin.Context) {
+	var req getHoldRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hold, _, valid := server.recipientHold(ctx, req.ID)
+	if !valid {
+		return
+	}
+
+	result, err := server.store.ReleaseHoldTx(ctx, db.ReleaseHoldTxParams{
+		HoldID: hold.ID,
+		Status: db.HoldVoided,
+	})
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newHoldTxResponse(result))
+}
+
+// holdAccounts loads a hold together with the account it holds money of and the account it is for
+func (server *Server) holdAccounts(ctx *gin.Context, id int64) (hold db.Hold, fromAccount db.Account, toAccount db.Account, valid bool) {
+	hold, err := server.store.GetHold(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	fromAccount, err = server.store.GetAccount(ctx, hold.FromAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	toAccount, err = server.store.GetAccount(ctx, hold.ToAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	return hold, fromAccount, toAccount, true
+}
+
+// recipientHold loads a hold and the account it is for, and checks that the authenticated user can pay from that account
+func (server *Server) recipientHold(ctx *gin.Context, id int64) (db.Hold, db.Account, bool) {
+	hold, _, toAccount, valid := server.holdAccounts(ctx, id)
+	if !valid {
+		return hold, toAccount, false
+	}
+
+	if !server.permitted(ctx, toAccount, payFromAccount) {
+		return hold, toAccount, false
+	}
+
+	return hold, toAccount, true
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+)
+
+// listAccountProducts lists the products accounts can be opened as, with the interest they pay and their rules
+func (server *Server) listAccountProducts(ctx *gin.Context) {
+	products, err := server.store.ListAccountProducts(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, products)
+}
+
+type accountProductURI struct {
+	Code string `uri:"code" binding:"required,alphanum,max=50"`
+}
+
+type upsertAccountProductRequest struct {
+	Name               string `json:"name" binding:"required,max=100"`
+	AnnualInterestRate string `json:"annual_interest_rate" binding:"required"`
+	DayCountConvention string `json:"day_count_convention" binding:"required,daycount"`
+	// MaxAccounts is how many open accounts of the product a user may have in each currency, one when not given
+	MaxAccounts int32 `json:"max_accounts" binding:"omitempty,min=1"`
+	// the overdraft new accounts of the product are opened with, the limit and fee are in minor units of the account's currency
+	OverdraftLimit      int64  `json:"overdraft_limit" binding:"min=0"`
+	OverdraftAnnualRate string `json:"overdraft_annual_rate"`
+	OverdraftDailyFee   int64  `json:"overdraft_daily_fee" binding:"min=0"`
+}
+
+// upsertAccountProduct creates a product or changes its name, interest and rules
+// The new rate applies from the next day accrued, the rest only to accounts opened afterwards
+func (server *Server) upsertAccountProduct(ctx *gin.Context) {
+	var uri accountProductURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req upsertAccountProductRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := util.ParseInterestRate(req.AnnualInterestRate); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.MaxAccounts == 0 {
+		req.MaxAccounts = 1
+	}
+	if req.OverdraftAnnualRate == "" {
+		req.OverdraftAnnualRate = "0"
+	}
+	if _, err := util.ParseInterestRate(req.OverdraftAnnualRate); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	product, err := server.store.UpsertAccountProduct(ctx, db.UpsertAccountProductParams{
+		Code: uri.Code,
+		Name: req.Name,
+		AnnualInterestRate: req.AnnualInterestRate,
+		DayCountConvention: req.DayCountConvention,
+		MaxAccounts: req.MaxAccounts,
+		OverdraftLimit: req.OverdraftLimit,
+		OverdraftAnnualRate: req.OverdraftAnnualRate,
+		OverdraftDailyFee: req.OverdraftDailyFee,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, product)
+}
+
+type listInterestAccrualsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=31"`
+}
+
+// listInterestAccruals lists the interest the user's account earned each day, latest first
+func (server *Server) listInterestAccruals(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listInterestAccrualsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, viewAccount)
+	if !valid {
+		return
+	}
+
+	accruals, err := server.store.ListInterestAccruals(ctx, db.ListInterestAccrualsParams{
+		AccountID: account.ID,
+		Limit: req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, accruals)
+}
@@ -0,0 +1,294 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertAccountProduct(t *testing.T) {
+	user, _ := randomUser(t)
+	banker, _ := randomUser(t)
+	banker.Role = util.BankerRole
+
+	product := db.AccountProduct{
+		Code:                "savings",
+		Name:                "Savings",
+		AnnualInterestRate:  "0.035",
+		DayCountConvention:  util.DayCountActual365,
+		MaxAccounts:         1,
+		OverdraftAnnualRate: "0",
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			body:     gin.H{"name": product.Name, "annual_interest_rate": product.AnnualInterestRate, "day_count_convention": product.DayCountConvention},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+
+				arg := db.UpsertAccountProductParams{
+					Code:                product.Code,
+					Name:                product.Name,
+					AnnualInterestRate:  product.AnnualInterestRate,
+					DayCountConvention:  product.DayCountConvention,
+					MaxAccounts:         1,
+					OverdraftAnnualRate: "0",
+				}
+				store.EXPECT().UpsertAccountProduct(gomock.Any(), gomock.Eq(arg)).Times(1).Return(product, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotProduct db.AccountProduct
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotProduct)
+				require.NoError(t, err)
+				require.Equal(t, product, gotProduct)
+			},
+		},
+		{
+			name: "WithRules",
+			body: gin.H{
+				"name":                  product.Name,
+				"annual_interest_rate":  product.AnnualInterestRate,
+				"day_count_convention":  product.DayCountConvention,
+				"max_accounts":          3,
+				"overdraft_limit":       50000,
+				"overdraft_annual_rate": "0.2",
+				"overdraft_daily_fee":   100,
+			},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+
+				arg := db.UpsertAccountProductParams{
+					Code:                product.Code,
+					Name:                product.Name,
+					AnnualInterestRate:  product.AnnualInterestRate,
+					DayCountConvention:  product.DayCountConvention,
+					MaxAccounts:         3,
+					OverdraftLimit:      50000,
+					OverdraftAnnualRate: "0.2",
+					OverdraftDailyFee:   100,
+				}
+				store.EXPECT().UpsertAccountProduct(gomock.Any(), gomock.Eq(arg)).Times(1).Return(product, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidOverdraftRate",
+			body:     gin.H{"name": product.Name, "annual_interest_rate": product.AnnualInterestRate, "day_count_convention": product.DayCountConvention, "overdraft_annual_rate": "20%"},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertAccountProduct(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidMaxAccounts",
+			body:     gin.H{"name": product.Name, "annual_interest_rate": product.AnnualInterestRate, "day_count_convention": product.DayCountConvention, "max_accounts": -1},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertAccountProduct(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidRate",
+			body:     gin.H{"name": product.Name, "annual_interest_rate": "3.5%", "day_count_convention": product.DayCountConvention},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertAccountProduct(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidDayCount",
+			body:     gin.H{"name": product.Name, "annual_interest_rate": product.AnnualInterestRate, "day_count_convention": "act/366"},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertAccountProduct(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Depositor",
+			body:     gin.H{"name": product.Name, "annual_interest_rate": product.AnnualInterestRate, "day_count_convention": product.DayCountConvention},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().UpsertAccountProduct(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "InternalError",
+			body:     gin.H{"name": product.Name, "annual_interest_rate": product.AnnualInterestRate, "day_count_convention": product.DayCountConvention},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertAccountProduct(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountProduct{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/account_products/%s", product.Code)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, autorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListInterestAccruals(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	accruals := []db.InterestAccrual{
+		{
+			AccountID:          account.ID,
+			AccrualDate:        time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC),
+			Balance:            account.Balance,
+			AnnualInterestRate: "0.035",
+			DayCountConvention: util.DayCountActual365,
+			Amount:             "0.0958904109",
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			query:    "page_id=2&page_size=5",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.ListInterestAccrualsParams{
+					AccountID: account.ID,
+					Limit:     5,
+					Offset:    5,
+				}
+				store.EXPECT().ListInterestAccruals(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accruals, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotAccruals []db.InterestAccrual
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotAccruals)
+				require.NoError(t, err)
+				require.Equal(t, accruals, gotAccruals)
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			query:    "page_id=1&page_size=5",
+			username: "unauthorized",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListInterestAccruals(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidPageSize",
+			query:    "page_id=1&page_size=100",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListInterestAccruals(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/interest_accruals?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, autorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
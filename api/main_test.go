@@ -1,11 +1,14 @@
 package api
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
 	db "github.com/nokkvi/simplebank/db/sqlc"
 	"github.com/nokkvi/simplebank/util"
 	"github.com/stretchr/testify/require"
@@ -16,12 +19,30 @@ var account db.Account
 func newTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
 		TokenSymmetricKey: util.RandomString(32),
+		BankCountryCode: "XB",
+		BankCode: "TEST",
 		AccessTokenDuration: time.Minute,
+		ExchangeQuoteDuration: 30 * time.Second,
+		HoldDuration: time.Hour,
+		DailyTransferLimitAmount: 1000,
+		DailyTransferLimitCount: 10,
+		MonthlyTransferLimitAmount: 10000,
+		MonthlyTransferLimitCount: 100,
 	}
 
 	server, err := NewServer(config, store)
 	require.NoError(t, err)
 
+	// the auth middleware loads the user of every request, the ones a test doesn't expect to be loaded
+	// are depositors who never changed their password, the test's own expectations come first as they are set up earlier
+	if mockStore, ok := store.(*mockdb.MockStore); ok {
+		mockStore.EXPECT().GetUser(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+			func(_ context.Context, username string) (db.User, error) {
+				return db.User{Username: username, Role: util.DepositorRole}, nil
+			},
+		)
+	}
+
 	return server
 }
 
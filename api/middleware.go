@@ -1,12 +1,14 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	db "github.com/nokkvi/simplebank/db/sqlc"
 	"github.com/nokkvi/simplebank/token"
 )
 
@@ -14,9 +16,12 @@ const (
 	authorizationHeaderKey = "authorization"
 	autorizationTypeBearer = "bearer"
 	autorizationPayloadKey = "autorization_payload"
+	autorizationUserKey    = "autorization_user"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+// authMiddleware lets through requests with a valid access token and loads the user it was issued to
+// Tokens issued before the user last changed their password are rejected, as they may have been taken with the old one
+func authMiddleware(tokenMaker token.Maker, store db.Store) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len (authorizationHeader) == 0 {
@@ -46,7 +51,41 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			return
 		}
 
+		user, err := store.GetUser(ctx, payload.Username)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if payload.IssuedAt.Before(user.PasswordChangedAt) {
+			err := errors.New("token was issued before the password was changed")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
 		ctx.Set(autorizationPayloadKey, payload)
+		ctx.Set(autorizationUserKey, user)
 		ctx.Next()
 	}
-}
\ No newline at end of file
+}
+
+// roleMiddleware only lets through authenticated users that have one of the given roles
+// It must run after authMiddleware
+func roleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user := ctx.MustGet(autorizationUserKey).(db.User)
+		for _, role := range roles {
+			if user.Role == role {
+				ctx.Next()
+				return
+			}
+		}
+
+		err := fmt.Errorf("role %s is not allowed to access this resource", user.Role)
+		ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+)
+
+type setOverdraftRequest struct {
+	Limit      util.Amount `json:"limit" binding:"min=0"`
+	AnnualRate string      `json:"annual_rate" binding:"required"`
+	DailyFee   util.Amount `json:"daily_fee" binding:"min=0"`
+}
+
+// setOverdraft approves an account for an overdraft on behalf of the bank, or takes it away with a limit of zero
+func (server *Server) setOverdraft(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req setOverdraftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := util.ParseInterestRate(req.AnnualRate); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	limit, valid := validAmount(ctx, req.Limit, account.Currency)
+	if !valid {
+		return
+	}
+
+	dailyFee, valid := validAmount(ctx, req.DailyFee, account.Currency)
+	if !valid {
+		return
+	}
+
+	account, err = server.store.SetOverdraftTx(ctx, db.SetOverdraftTxParams{
+		AccountID: account.ID,
+		Limit: limit,
+		AnnualRate: req.AnnualRate,
+		DailyFee: dailyFee,
+	})
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+type listOverdraftChargesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=31"`
+}
+
+// listOverdraftCharges lists what the user's account was charged for its overdraft each day, latest first
+func (server *Server) listOverdraftCharges(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listOverdraftChargesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, viewAccount)
+	if !valid {
+		return
+	}
+
+	charges, err := server.store.ListOverdraftCharges(ctx, db.ListOverdraftChargesParams{
+		AccountID: account.ID,
+		Limit: req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, charges)
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getReconciliation checks the whole ledger and returns the discrepancies it found, without changing anything
+func (server *Server) getReconciliation(ctx *gin.Context) {
+	report, err := server.store.ReconcileTx(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
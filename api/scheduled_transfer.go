@@ -0,0 +1,278 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/token"
+	"github.com/nokkvi/simplebank/util"
+)
+
+type createScheduledTransferRequest struct {
+	FromAccountID int64       `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64       `json:"to_account_id" binding:"required,min=1"`
+	Amount        util.Amount `json:"amount" binding:"required,gt=0"`
+	Currency      string      `json:"currency" binding:"required,currency"`
+	Recurrence    string      `json:"recurrence" binding:"required,recurrence"`
+	DayOfMonth    int32       `json:"day_of_month" binding:"required_if=Recurrence monthly,omitempty,min=1,max=31"`
+	StartAt       time.Time   `json:"start_at" binding:"required"`
+}
+
+func (server *Server) createScheduledTransfer(ctx *gin.Context) {
+	var req createScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.StartAt.Before(time.Now()) {
+		err := errors.New("start_at must be in the future")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	amount, valid := validAmount(ctx, req.Amount, req.Currency)
+	if !valid {
+		return
+	}
+
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	if !server.permitted(ctx, fromAccount, payFromAccount) {
+		return
+	}
+
+	// scheduled transfers run without anyone to approve them, so they have to stay within the approval threshold
+	if db.NeedsApproval(fromAccount, amount) {
+		ctx.JSON(http.StatusUnprocessableEntity, errorCodeResponse(errCodeApprovalRequired, db.ErrApprovalRequired))
+		return
+	}
+
+	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	arg := db.CreateScheduledTransferParams{
+		Owner: authPayload.Username,
+		FromAccountID: req.FromAccountID,
+		ToAccountID: req.ToAccountID,
+		Amount: amount,
+		Recurrence: req.Recurrence,
+		DayOfMonth: req.DayOfMonth,
+		NextRunAt: util.FirstOccurrence(req.Recurrence, int(req.DayOfMonth), req.StartAt.UTC()),
+	}
+
+	scheduled, err := server.store.CreateScheduledTransfer(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, scheduled)
+}
+
+type getScheduledTransferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getScheduledTransfer(ctx *gin.Context) {
+	var req getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, valid := server.ownScheduledTransfer(ctx, req.ID)
+	if !valid {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+type listScheduledTransfersRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listScheduledTransfers(ctx *gin.Context) {
+	var req listScheduledTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	arg := db.ListScheduledTransfersParams{
+		Owner: authPayload.Username,
+		Limit: req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	scheduledTransfers, err := server.store.ListScheduledTransfers(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduledTransfers)
+}
+
+type updateScheduledTransferRequest struct {
+	// Amount is in the currency of the account the scheduled transfer is from
+	Amount util.Amount `json:"amount" binding:"required,gt=0"`
+}
+
+func (server *Server) updateScheduledTransfer(ctx *gin.Context) {
+	var uri getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, valid := server.activeScheduledTransfer(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	fromAccount, err := server.store.GetAccount(ctx, scheduled.FromAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	amount, valid := validAmount(ctx, req.Amount, fromAccount.Currency)
+	if !valid {
+		return
+	}
+
+	if db.NeedsApproval(fromAccount, amount) {
+		ctx.JSON(http.StatusUnprocessableEntity, errorCodeResponse(errCodeApprovalRequired, db.ErrApprovalRequired))
+		return
+	}
+
+	scheduled, err = server.store.UpdateScheduledTransferAmount(ctx, db.UpdateScheduledTransferAmountParams{
+		ID: scheduled.ID,
+		Amount: amount,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+func (server *Server) cancelScheduledTransfer(ctx *gin.Context) {
+	var req getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, valid := server.activeScheduledTransfer(ctx, req.ID)
+	if !valid {
+		return
+	}
+
+	scheduled, err := server.store.CancelScheduledTransfer(ctx, scheduled.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+type listScheduledTransferRunsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listScheduledTransferRuns(ctx *gin.Context) {
+	var uri getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listScheduledTransferRunsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, valid := server.ownScheduledTransfer(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	arg := db.ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit: req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	runs, err := server.store.ListScheduledTransferRuns(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runs)
+}
+
+// ownScheduledTransfer loads a scheduled transfer and checks that it belongs to the authenticated user
+func (server *Server) ownScheduledTransfer(ctx *gin.Context, id int64) (db.ScheduledTransfer, bool) {
+	scheduled, err := server.store.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return scheduled, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return scheduled, false
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	if scheduled.Owner != authPayload.Username {
+		err := errors.New("scheduled transfer doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return scheduled, false
+	}
+
+	return scheduled, true
+}
+
+// activeScheduledTransfer is like ownScheduledTransfer but also checks that the scheduled transfer can still change
+func (server *Server) activeScheduledTransfer(ctx *gin.Context, id int64) (db.ScheduledTransfer, bool) {
+	scheduled, valid := server.ownScheduledTransfer(ctx, id)
+	if !valid {
+		return scheduled, false
+	}
+
+	if scheduled.Status != db.ScheduledTransferActive {
+		err := fmt.Errorf("scheduled transfer [%d] is %s", id, scheduled.Status)
+		ctx.JSON(http.StatusConflict, errorCodeResponse(errCodeScheduledTransferInactive, err))
+		return scheduled, false
+	}
+
+	return scheduled, true
+}
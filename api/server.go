@@ -1,6 +1,7 @@
 package api
 
 import (
+	"expvar"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
@@ -25,6 +26,10 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
+	if err := util.ValidateBankCode(config.BankCountryCode, config.BankCode); err != nil {
+		return nil, fmt.Errorf("cannot make account numbers: %w", err)
+	}
+
 	server := &Server{
 		config: config,
 		store: store,
@@ -34,6 +39,10 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("recurrence", validRecurrence)
+		v.RegisterValidation("daycount", validDayCount)
+		v.RegisterValidation("account_number", validAccountNumber)
+		v.RegisterCustomTypeFunc(amountSign, util.Amount{})
 	}
 
 	server.setupRouter()
@@ -47,17 +56,78 @@ func (server *Server) setupRouter() {
 	router.POST("/users/login", server.loginUser)
 	router.POST("/tokens/renew_access", server.renewAccessToken)
 
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.store))
 
+	authRoutes.PATCH("/users/me", server.updateUser)
+	authRoutes.POST("/users/me/password", server.changePassword)
 	authRoutes.POST("/accounts", server.createAccount)
 	authRoutes.GET("/accounts/:id", server.getAccount)
+	authRoutes.GET("/accounts/number/:number", server.getAccountByNumber)
 	authRoutes.GET("/accounts", server.listAccounts)
 	authRoutes.DELETE("/accounts/:id", server.deleteAccount)
+	authRoutes.PUT("/accounts/:id/nickname", server.setAccountNickname)
+	authRoutes.GET("/accounts/:id/status_changes", server.listAccountStatusChanges)
+	authRoutes.GET("/accounts/:id/statements", server.getStatement)
+	authRoutes.GET("/accounts/:id/balance", server.getBalance)
+	authRoutes.GET("/accounts/:id/interest_accruals", server.listInterestAccruals)
+	authRoutes.GET("/accounts/:id/overdraft_charges", server.listOverdraftCharges)
+	authRoutes.POST("/accounts/:id/members", server.addAccountMember)
+	authRoutes.GET("/accounts/:id/members", server.listAccountMembers)
+	authRoutes.DELETE("/accounts/:id/members/:username", server.removeAccountMember)
+	authRoutes.POST("/accounts/:id/approvers", server.addAccountApprover)
+	authRoutes.GET("/accounts/:id/approvers", server.listAccountApprovers)
+	authRoutes.DELETE("/accounts/:id/approvers/:username", server.removeAccountApprover)
 
 	authRoutes.GET("/entries", server.listEntries)
 	authRoutes.GET("/entries/:id", server.getEntry)
 
 	authRoutes.POST("/transfers", server.createTransfer)
+	authRoutes.POST("/transfers/batch", server.createBatchTransfer)
+	authRoutes.GET("/transfers/:id", server.getTransfer)
+	authRoutes.GET("/transfers", server.listTransfers)
+	authRoutes.POST("/transfers/:id/reverse", server.reverseTransfer)
+
+	authRoutes.GET("/transfer_requests/:id", server.getTransferRequest)
+	authRoutes.GET("/transfer_requests", server.listTransferRequests)
+	authRoutes.POST("/transfer_requests/:id/approve", server.approveTransferRequest)
+	authRoutes.POST("/transfer_requests/:id/reject", server.rejectTransferRequest)
+
+	authRoutes.POST("/scheduled_transfers", server.createScheduledTransfer)
+	authRoutes.GET("/scheduled_transfers/:id", server.getScheduledTransfer)
+	authRoutes.GET("/scheduled_transfers", server.listScheduledTransfers)
+	authRoutes.PATCH("/scheduled_transfers/:id", server.updateScheduledTransfer)
+	authRoutes.DELETE("/scheduled_transfers/:id", server.cancelScheduledTransfer)
+	authRoutes.GET("/scheduled_transfers/:id/runs", server.listScheduledTransferRuns)
+
+	authRoutes.POST("/holds", server.authorizeHold)
+	authRoutes.GET("/holds/:id", server.getHold)
+	authRoutes.POST("/holds/:id/capture", server.captureHold)
+	authRoutes.POST("/holds/:id/void", server.voidHold)
+
+	authRoutes.GET("/transfer_limits", server.listTransferAllowances)
+
+	authRoutes.GET("/account_products", server.listAccountProducts)
+
+	authRoutes.GET("/currencies", server.listCurrencies)
+
+	authRoutes.GET("/notifications", server.listNotifications)
+
+	authRoutes.GET("/exchange_rates", server.listExchangeRates)
+	authRoutes.POST("/exchange_quotes", server.createExchangeQuote)
+
+	bankerRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.store), roleMiddleware(util.BankerRole))
+
+	bankerRoutes.PUT("/exchange_rates", server.updateExchangeRates)
+	bankerRoutes.PUT("/transfer_limits", server.setTransferLimit)
+	bankerRoutes.PUT("/accounts/:id/approval_threshold", server.setApprovalThreshold)
+	bankerRoutes.PUT("/accounts/:id/status", server.changeAccountStatus)
+	bankerRoutes.PUT("/accounts/:id/overdraft", server.setOverdraft)
+	bankerRoutes.GET("/reconciliation", server.getReconciliation)
+	bankerRoutes.POST("/deposits", server.createDeposit)
+	bankerRoutes.POST("/withdrawals", server.createWithdrawal)
+	bankerRoutes.PUT("/account_products/:code", server.upsertAccountProduct)
+	bankerRoutes.PUT("/currencies/:code", server.upsertCurrency)
+	bankerRoutes.GET("/debug/vars", gin.WrapH(expvar.Handler()))
 
 	server.router = router
 }
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+)
+
+type getStatementRequest struct {
+	From   time.Time `form:"from" time_format:"2006-01-02" time_utc:"1" binding:"required"`
+	To     time.Time `form:"to" time_format:"2006-01-02" time_utc:"1" binding:"required,gtefield=From"`
+	Format string    `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// statementDateFormat is the format of the from and to dates of a statement
+const statementDateFormat = "2006-01-02"
+
+// getStatement returns the statement of the user's account between two dates, both included, as JSON or CSV
+func (server *Server) getStatement(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req getStatementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, viewAccount)
+	if !valid {
+		return
+	}
+
+	// to includes the whole day
+	statement, err := server.store.StatementTx(ctx, db.StatementTxParams{
+		AccountID: account.ID,
+		From: req.From,
+		To: req.To.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	if req.Format != "csv" {
+		ctx.JSON(http.StatusOK, statement)
+		return
+	}
+
+	data, err := statementCSV(statement)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%d-%s-%s.csv", account.ID, req.From.Format(statementDateFormat), req.To.Format(statementDateFormat))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Data(http.StatusOK, "text/csv", data)
+}
+
+// statementCSV writes a statement as CSV, one row per entry between a row for the opening balance and rows for the totals and the closing balance
+func statementCSV(statement db.Statement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	from := statement.From.Format(time.RFC3339)
+	to := statement.To.Format(time.RFC3339)
+	rows := [][]string{
+		{"date", "entry_id", "description", "reference", "amount", "balance"},
+		{from, "", "opening balance", "", "", strconv.FormatInt(statement.OpeningBalance, 10)},
+	}
+
+	for _, line := range statement.Lines {
+		rows = append(rows, []string{
+			line.Entry.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatInt(line.Entry.ID, 10),
+			line.Entry.Memo,
+			line.Entry.Reference,
+			strconv.FormatInt(line.Entry.Amount, 10),
+			strconv.FormatInt(line.RunningBalance, 10),
+		})
+	}
+
+	rows = append(rows,
+		[]string{to, "", "total credits", "", strconv.FormatInt(statement.TotalCredits, 10), ""},
+		[]string{to, "", "total debits", "", strconv.FormatInt(-statement.TotalDebits, 10), ""},
+		[]string{to, "", "closing balance", "", "", strconv.FormatInt(statement.ClosingBalance, 10)},
+	)
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type getBalanceRequest struct {
+	At time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// getBalance returns the balance the user's account had at a point in time, given in RFC 3339, or now when no time is given
+// Entries made at exactly that time are not counted, the same way they are left off a statement that ends then
+func (server *Server) getBalance(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req getBalanceRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.authorizeAccount(ctx, uri.ID, viewAccount)
+	if !valid {
+		return
+	}
+
+	if req.At.IsZero() {
+		req.At = time.Now()
+	}
+
+	balance, err := server.store.BalanceAtTx(ctx, db.BalanceAtTxParams{
+		AccountID: account.ID,
+		At: req.At,
+	})
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, balance)
+}
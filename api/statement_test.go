@@ -0,0 +1,299 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatement(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	statement := randomStatement(account)
+
+	from := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		query         string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "JSON",
+			query:    "from=2022-03-01&to=2022-03-31",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				// to includes the whole day
+				arg := db.StatementTxParams{
+					AccountID: account.ID,
+					From:      from,
+					To:        to,
+				}
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(statement, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotStatement db.Statement
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotStatement)
+				require.NoError(t, err)
+				require.Equal(t, statement.OpeningBalance, gotStatement.OpeningBalance)
+				require.Equal(t, statement.ClosingBalance, gotStatement.ClosingBalance)
+				require.Len(t, gotStatement.Lines, len(statement.Lines))
+			},
+		},
+		{
+			name:     "CSV",
+			query:    "from=2022-03-01&to=2022-03-31&format=csv",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(1).Return(statement, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), fmt.Sprintf("statement-%d-2022-03-01-2022-03-31.csv", account.ID))
+
+				rows, err := csv.NewReader(recorder.Body).ReadAll()
+				require.NoError(t, err)
+
+				// header, opening balance, one row per entry, totals and closing balance
+				require.Len(t, rows, len(statement.Lines)+5)
+				require.Equal(t, "opening balance", rows[1][2])
+				require.Equal(t, fmt.Sprint(statement.OpeningBalance), rows[1][5])
+
+				last := statement.Lines[len(statement.Lines)-1]
+				require.Equal(t, fmt.Sprint(last.Entry.ID), rows[len(rows)-4][1])
+				require.Equal(t, fmt.Sprint(last.RunningBalance), rows[len(rows)-4][5])
+
+				require.Equal(t, "closing balance", rows[len(rows)-1][2])
+				require.Equal(t, fmt.Sprint(statement.ClosingBalance), rows[len(rows)-1][5])
+			},
+		},
+		{
+			name:     "ToBeforeFrom",
+			query:    "from=2022-03-31&to=2022-03-01",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "MissingFrom",
+			query:    "to=2022-03-31",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidFormat",
+			query:    "from=2022-03-01&to=2022-03-31&format=pdf",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			query:    "from=2022-03-01&to=2022-03-31",
+			username: "unauthorized_user",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "InternalError",
+			query:    "from=2022-03-01&to=2022-03-31",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().StatementTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Statement{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/statements?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, autorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func randomStatement(account db.Account) db.Statement {
+	statement := db.Statement{
+		Account:        account,
+		OpeningBalance: account.Balance,
+		ClosingBalance: account.Balance,
+	}
+
+	for _, entry := range randomEntries(account.ID, 3) {
+		statement.ClosingBalance += entry.Amount
+		statement.TotalCredits += entry.Amount
+		statement.Lines = append(statement.Lines, db.StatementLine{
+			Entry:          entry,
+			RunningBalance: statement.ClosingBalance,
+		})
+	}
+
+	return statement
+}
+
+func TestGetBalance(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	at := time.Date(2022, time.March, 31, 23, 59, 59, 0, time.UTC)
+	balance := db.BalanceAt{
+		AccountID: account.ID,
+		Currency:  account.Currency,
+		At:        at,
+		Balance:   account.Balance,
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			query:    "at=2022-03-31T23:59:59Z",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.BalanceAtTxParams{
+					AccountID: account.ID,
+					At:        at,
+				}
+				store.EXPECT().BalanceAtTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(balance, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotBalance db.BalanceAt
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotBalance)
+				require.NoError(t, err)
+				require.Equal(t, balance, gotBalance)
+			},
+		},
+		{
+			name:     "Now",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().BalanceAtTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.BalanceAtTxParams) (db.BalanceAt, error) {
+						require.WithinDuration(t, time.Now(), arg.At, time.Second)
+						return balance, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidTime",
+			query:    "at=2022-03-31",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BalanceAtTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			query:    "at=2022-03-31T23:59:59Z",
+			username: "unauthorized_user",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().BalanceAtTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/balance?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, autorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
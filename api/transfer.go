@@ -1,21 +1,53 @@
 package api
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	db "github.com/nokkvi/simplebank/db/sqlc"
 	"github.com/nokkvi/simplebank/token"
+	"github.com/nokkvi/simplebank/util"
 )
 
+// transferRequest gives each account by either its id or its account number
 type transferRequest struct {
-	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
-	Currency      string `json:"currency" binding:"required,currency"`
+	FromAccountID     int64       `json:"from_account_id" binding:"omitempty,min=1"`
+	FromAccountNumber string      `json:"from_account_number" binding:"required_without=FromAccountID,excluded_with=FromAccountID,omitempty,account_number"`
+	ToAccountID       int64       `json:"to_account_id" binding:"omitempty,min=1"`
+	ToAccountNumber   string      `json:"to_account_number" binding:"required_without=ToAccountID,excluded_with=ToAccountID,omitempty,account_number"`
+	Amount            util.Amount `json:"amount" binding:"required,gt=0"`
+	Currency          string      `json:"currency" binding:"required,currency"`
+	QuoteID           string      `json:"quote_id" binding:"omitempty,uuid"`
+	transferDetailsRequest
+}
+
+// transferDetailsRequest is what a transfer is for, as given by the user making it
+type transferDetailsRequest struct {
+	Memo      string            `json:"memo" binding:"max=140"`
+	Reference string            `json:"reference" binding:"max=64"`
+	Metadata  map[string]string `json:"metadata" binding:"max=20,dive,keys,required,max=40,endkeys,max=256"`
+}
+
+func (req transferDetailsRequest) details() db.TransferDetails {
+	return db.TransferDetails{
+		Memo: req.Memo,
+		Reference: req.Reference,
+		Metadata: req.Metadata,
+	}
+}
+
+type idempotencyHeader struct {
+	Key string `header:"Idempotency-Key" binding:"omitempty,max=255"`
 }
 
 func (server *Server) createTransfer(ctx *gin.Context) {
@@ -25,36 +57,391 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
-	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	var header idempotencyHeader
+	if err := ctx.ShouldBindHeader(&header); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	amount, valid := validAmount(ctx, req.Amount, req.Currency)
 	if !valid {
 		return
 	}
 
+	// the request hashes the same for its idempotency key whether the amount was a decimal or in minor units
+	req.Amount = util.MinorAmount(amount)
+
 	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
-	if fromAccount.Owner != authPayload.Username {
-		err := errors.New("from account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+
+	// a transfer between currencies credits the to account in the currency the quote converts to
+	toCurrency := req.Currency
+	var quote db.ExchangeQuote
+	if req.QuoteID != "" {
+		quote, valid = server.validQuote(ctx, uuid.MustParse(req.QuoteID), authPayload.Username, req.Currency)
+		if !valid {
+			return
+		}
+		toCurrency = quote.ToCurrency
+	}
+
+	fromAccount, valid := server.transferAccount(ctx, req.FromAccountID, req.FromAccountNumber, req.Currency)
+	if !valid {
+		return
+	}
+
+	if !server.permitted(ctx, fromAccount, payFromAccount) {
 		return
 	}
 
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	toAccount, valid := server.transferAccount(ctx, req.ToAccountID, req.ToAccountNumber, toCurrency)
 	if !valid {
 		return
 	}
 
+	// like the amount, the request hashes the same whether the accounts were given by id or account number
+	req.FromAccountID, req.FromAccountNumber = fromAccount.ID, ""
+	req.ToAccountID, req.ToAccountNumber = toAccount.ID, ""
+
+	if db.NeedsApproval(fromAccount, amount) {
+		if req.QuoteID != "" {
+			ctx.JSON(http.StatusUnprocessableEntity, errorCodeResponse(errCodeApprovalRequired, errApprovalRequired))
+			return
+		}
+
+		server.requestTransfer(ctx, req, amount, header.Key)
+		return
+	}
+
 	arg := db.TransferTxParams{
 		FromAccountID:    req.FromAccountID,
 		ToAccountID: req.ToAccountID,
-		Amount:  req.Amount,
+		Amount:  amount,
+		TransferDetails: req.details(),
+		InitiatedBy: authPayload.Username,
+		DefaultLimits: server.defaultTransferLimits(),
+	}
+
+	if header.Key != "" {
+		arg.IdempotencyKey = &db.IdempotencyKeyParams{
+			Username: authPayload.Username,
+			Key: header.Key,
+			RequestHash: hashRequest(req),
+		}
+	}
+
+	var result db.TransferTxResult
+	var err error
+	if req.QuoteID != "" {
+		result, err = server.store.ExchangeTransferTx(ctx, db.ExchangeTransferTxParams{
+			TransferTxParams: arg,
+			QuoteID: quote.ID,
+		})
+	} else {
+		result, err = server.store.TransferTx(ctx, arg)
+	}
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newTransferTxResponse(result))
+}
+
+// transferResponse is a transfer together with how much of it was reversed and who and which currency is on either side
+type transferResponse struct {
+	db.Transfer
+	ReversalStatus      string `json:"reversalStatus"`
+	FromAccountOwner    string `json:"fromAccountOwner"`
+	FromAccountCurrency string `json:"fromAccountCurrency"`
+	ToAccountOwner      string `json:"toAccountOwner"`
+	ToAccountCurrency   string `json:"toAccountCurrency"`
+	// the amount in the currency of the from account and the amount credited in the currency of the to account
+	DecimalAmount   util.Money `json:"decimalAmount"`
+	DecimalToAmount util.Money `json:"decimalToAmount"`
+}
+
+func newTransferResponse(transfer db.Transfer, fromAccount db.Account, toAccount db.Account) transferResponse {
+	return transferResponse{
+		Transfer: transfer,
+		ReversalStatus: transfer.ReversalStatus(),
+		FromAccountOwner: fromAccount.Owner,
+		FromAccountCurrency: fromAccount.Currency,
+		ToAccountOwner: toAccount.Owner,
+		ToAccountCurrency: toAccount.Currency,
+		DecimalAmount: util.NewMoney(transfer.Amount, fromAccount.Currency),
+		DecimalToAmount: util.NewMoney(transfer.ToAmount, toAccount.Currency),
+	}
+}
+
+// transferTxResponse is the result of a transfer transaction with its amounts also given as decimals in their currencies
+type transferTxResponse struct {
+	Transfer    transferResponse `json:"transfer"`
+	FromAccount accountResponse  `json:"from_account"`
+	ToAccount   accountResponse  `json:"to_account"`
+	FromEntry   entryResponse    `json:"from_entry"`
+	ToEntry     entryResponse    `json:"to_entry"`
+}
+
+func newTransferTxResponse(result db.TransferTxResult) transferTxResponse {
+	return transferTxResponse{
+		Transfer: newTransferResponse(result.Transfer, result.FromAccount, result.ToAccount),
+		FromAccount: newAccountResponse(result.FromAccount),
+		ToAccount: newAccountResponse(result.ToAccount),
+		FromEntry: newEntryResponse(result.FromEntry, result.FromAccount),
+		ToEntry: newEntryResponse(result.ToEntry, result.ToAccount),
+	}
+}
+
+// reverseTransferResponse is a reversal together with the transfer it reverses, which went the other way
+type reverseTransferResponse struct {
+	transferTxResponse
+	OriginalTransfer transferResponse `json:"original_transfer"`
+}
+
+func newReverseTransferResponse(result db.ReverseTransferTxResult) reverseTransferResponse {
+	return reverseTransferResponse{
+		transferTxResponse: newTransferTxResponse(result.TransferTxResult),
+		OriginalTransfer: newTransferResponse(result.OriginalTransfer, result.ToAccount, result.FromAccount),
+	}
+}
+
+type getTransferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getTransfer(ctx *gin.Context) {
+	var req getTransferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	fromAccount, err := server.store.GetAccount(ctx, transfer.FromAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	toAccount, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !server.permittedEither(ctx, fromAccount, toAccount, viewAccount) {
+		return
 	}
 
-	result, err := server.store.TransferTx(ctx, arg)
+	ctx.JSON(http.StatusOK, newTransferResponse(transfer, fromAccount, toAccount))
+}
+
+type listTransfersRequest struct {
+	AccountID int64     `form:"account_id" binding:"omitempty,min=1"`
+	Direction string    `form:"direction" binding:"omitempty,oneof=incoming outgoing"`
+	MinAmount int64     `form:"min_amount" binding:"omitempty,gt=0"`
+	MaxAmount int64     `form:"max_amount" binding:"omitempty,gt=0,gtefield=MinAmount"`
+	FromDate  time.Time `form:"from_date" time_format:"2006-01-02" time_utc:"1"`
+	ToDate    time.Time `form:"to_date" time_format:"2006-01-02" time_utc:"1" binding:"omitempty,gtefield=FromDate"`
+	Search    string    `form:"search" binding:"max=140"`
+	PageID    int32     `form:"page_id" binding:"required,min=1"`
+	PageSize  int32     `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listTransfers lists the transfers from or to the authenticated user's accounts, most recent first
+// Incoming transfers are those to one of the user's accounts and outgoing ones those from one,
+// the account filter narrows that down to a single account, and the date range includes both its days
+// The search matches transfers whose memo or reference contains it, ignoring case
+func (server *Server) listTransfers(ctx *gin.Context) {
+	var req listTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+
+	arg := db.ListUserTransfersParams{
+		Direction: req.Direction,
+		Username: authPayload.Username,
+		MinAmount: sql.NullInt64{Int64: req.MinAmount, Valid: req.MinAmount > 0},
+		MaxAmount: sql.NullInt64{Int64: req.MaxAmount, Valid: req.MaxAmount > 0},
+		CreatedAfter: sql.NullTime{Time: req.FromDate, Valid: !req.FromDate.IsZero()},
+		Search: searchPattern(req.Search),
+		PageLimit: req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	}
+
+	// to_date includes the whole day
+	if !req.ToDate.IsZero() {
+		arg.CreatedBefore = sql.NullTime{Time: req.ToDate.AddDate(0, 0, 1), Valid: true}
+	}
+
+	if req.AccountID > 0 {
+		if _, valid := server.authorizeAccount(ctx, req.AccountID, viewAccount); !valid {
+			return
+		}
+
+		arg.AccountID = sql.NullInt64{Int64: req.AccountID, Valid: true}
+	}
+
+	transfers, err := server.store.ListUserTransfers(ctx, arg)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, result)
+	rsp := make([]transferResponse, len(transfers))
+	for i, row := range transfers {
+		transfer := db.Transfer{
+			ID: row.ID,
+			FromAccountID: row.FromAccountID,
+			ToAccountID: row.ToAccountID,
+			Amount: row.Amount,
+			CreatedAt: row.CreatedAt,
+			ToAmount: row.ToAmount,
+			ExchangeRate: row.ExchangeRate,
+			ReversalOf: row.ReversalOf,
+			ReversedAmount: row.ReversedAmount,
+			Memo: row.Memo,
+			Reference: row.Reference,
+			Metadata: row.Metadata,
+		}
+		fromAccount := db.Account{Owner: row.FromAccountOwner, Currency: row.FromAccountCurrency}
+		toAccount := db.Account{Owner: row.ToAccountOwner, Currency: row.ToAccountCurrency}
+		rsp[i] = newTransferResponse(transfer, fromAccount, toAccount)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type reverseTransferRequest struct {
+	// Amount is in the currency of the account the transfer was from
+	Amount util.Amount `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// reverseTransfer moves the money of a transfer back, all of it unless an amount is given
+// Only the owner of the account that received the transfer, or a banker, can reverse it
+func (server *Server) reverseTransfer(ctx *gin.Context) {
+	var uri getTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// the body is optional, without one the whole transfer is reversed
+	var req reverseTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var header idempotencyHeader
+	if err := ctx.ShouldBindHeader(&header); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	toAccount, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	status, err := server.checkAccountPermission(ctx, toAccount, authPayload.Username, payFromAccount)
+	if status == http.StatusInternalServerError {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+	if err != nil {
+		user := ctx.MustGet(autorizationUserKey).(db.User)
+		if user.Role != util.BankerRole {
+			err := errors.New("only those who can pay from the recipient account of the transfer or a banker can reverse it")
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+	}
+
+	arg := db.ReverseTransferTxParams{
+		TransferID: transfer.ID,
+		InitiatedBy: authPayload.Username,
+	}
+
+	if req.Amount.Sign() > 0 {
+		fromAccount, err := server.store.GetAccount(ctx, transfer.FromAccountID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		amount, valid := validAmount(ctx, req.Amount, fromAccount.Currency)
+		if !valid {
+			return
+		}
+		arg.Amount = amount
+	}
+
+	if header.Key != "" {
+		arg.IdempotencyKey = &db.IdempotencyKeyParams{
+			Username: authPayload.Username,
+			Key: header.Key,
+			RequestHash: hashRequest(arg),
+		}
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, arg)
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newReverseTransferResponse(result))
+}
+
+// transferAccount is like validAccount for an account given by either its id or its account number
+func (server *Server) transferAccount(ctx *gin.Context, accountID int64, number string, currency string) (db.Account, bool) {
+	if number == "" {
+		return server.validAccount(ctx, accountID, currency)
+	}
+
+	account, err := server.store.GetAccountByNumber(ctx, number)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return account, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+
+	if account.Currency != currency {
+		err := fmt.Errorf("account %s currency mismatch: %s vs %s", number, account.Currency, currency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return account, false
+	}
+
+	return account, true
 }
 
 func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
@@ -76,4 +463,60 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency s
 	}
 
 	return account, true
-} 
\ No newline at end of file
+}
+
+// validAmount returns an amount from a request in minor units of the currency
+// An amount with more decimals than the currency has is a bad request rather than rounded
+func validAmount(ctx *gin.Context, amount util.Amount, currency string) (int64, bool) {
+	money, err := amount.Money(currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return 0, false
+	}
+
+	return money.Amount, true
+}
+
+func (server *Server) validQuote(ctx *gin.Context, quoteID uuid.UUID, username string, currency string) (db.ExchangeQuote, bool) {
+	quote, err := server.store.GetExchangeQuote(ctx, quoteID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return quote, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return quote, false
+	}
+
+	if quote.Username != username {
+		err := errors.New("exchange quote doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return quote, false
+	}
+
+	if quote.FromCurrency != currency {
+		err := fmt.Errorf("exchange quote [%s] currency mismatch: %s vs %s", quoteID, quote.FromCurrency, currency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return quote, false
+	}
+
+	return quote, true
+}
+
+// searchPattern returns an ILIKE pattern matching text that contains search, or null to match everything when search is empty
+func searchPattern(search string) sql.NullString {
+	if search == "" {
+		return sql.NullString{}
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(search)
+	return sql.NullString{String: "%" + escaped + "%", Valid: true}
+}
+
+// hashRequest returns a hex encoded SHA-256 digest of the request's JSON encoding
+func hashRequest(req interface{}) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/token"
+)
+
+type listTransferAllowancesRequest struct {
+	AccountID int64 `form:"account_id" binding:"required,min=1"`
+}
+
+// listTransferAllowances returns what is left of the daily and monthly transfer limits of one of the user's accounts,
+// and of the user's own limits in the account's currency
+func (server *Server) listTransferAllowances(ctx *gin.Context) {
+	var req listTransferAllowancesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !server.permitted(ctx, account, viewAccount) {
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	allowances, err := server.store.TransferAllowancesTx(ctx, db.TransferAllowancesTxParams{
+		AccountID: account.ID,
+		Username: authPayload.Username,
+		DefaultLimits: server.defaultTransferLimits(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, allowances)
+}
+
+type setTransferLimitRequest struct {
+	Username  string `json:"username" binding:"required_without=AccountID,excluded_with=AccountID"`
+	AccountID int64  `json:"account_id" binding:"omitempty,min=1"`
+	Period    string `json:"period" binding:"required,oneof=daily monthly"`
+	MaxAmount int64  `json:"max_amount" binding:"required,gt=0"`
+	MaxCount  int32  `json:"max_count" binding:"required,gt=0"`
+}
+
+// setTransferLimit sets the daily or monthly transfer limit of a user or of an account, replacing the bank-wide default
+func (server *Server) setTransferLimit(ctx *gin.Context) {
+	var req setTransferLimitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var limit db.TransferLimit
+	var err error
+	if req.AccountID > 0 {
+		_, err = server.store.GetAccount(ctx, req.AccountID)
+		if err == nil {
+			limit, err = server.store.SetAccountTransferLimit(ctx, db.SetAccountTransferLimitParams{
+				AccountID: sql.NullInt64{Int64: req.AccountID, Valid: true},
+				Period: req.Period,
+				MaxAmount: req.MaxAmount,
+				MaxCount: req.MaxCount,
+			})
+		}
+	} else {
+		_, err = server.store.GetUser(ctx, req.Username)
+		if err == nil {
+			limit, err = server.store.SetUserTransferLimit(ctx, db.SetUserTransferLimitParams{
+				Username: sql.NullString{String: req.Username, Valid: true},
+				Period: req.Period,
+				MaxAmount: req.MaxAmount,
+				MaxCount: req.MaxCount,
+			})
+		}
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limit)
+}
+
+// defaultTransferLimits returns the bank-wide transfer limits by period
+func (server *Server) defaultTransferLimits() map[string]db.DefaultTransferLimit {
+	return db.DefaultTransferLimits(server.config)
+}
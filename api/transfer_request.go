@@ -0,0 +1,233 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/token"
+)
+
+// errApprovalRequired is returned for transfers above the approval threshold that can't wait for an approver
+var errApprovalRequired = errors.New("transfer exceeds the account's approval threshold, make it as a single transfer in the account's currency")
+
+// requestTransfer records a transfer above the from account's approval threshold as a pending request
+// The money only moves once one of the account's approvers approves it
+func (server *Server) requestTransfer(ctx *gin.Context, req transferRequest, amount int64, idempotencyKey string) {
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+
+	arg := db.RequestTransferTxParams{
+		FromAccountID: req.FromAccountID,
+		ToAccountID: req.ToAccountID,
+		Amount: amount,
+		RequestedBy: authPayload.Username,
+		TransferDetails: req.details(),
+	}
+
+	if idempotencyKey != "" {
+		arg.IdempotencyKey = &db.IdempotencyKeyParams{
+			Username: authPayload.Username,
+			Key: idempotencyKey,
+			RequestHash: hashRequest(req),
+		}
+	}
+
+	request, err := server.store.RequestTransferTx(ctx, arg)
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, request)
+}
+
+type getTransferRequestRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getTransferRequest returns a transfer request to the members of its from account or one of the account's approvers
+func (server *Server) getTransferRequest(ctx *gin.Context) {
+	var req getTransferRequestRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	request, fromAccount, valid := server.loadTransferRequest(ctx, req.ID)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	status, err := server.checkAccountPermission(ctx, fromAccount, authPayload.Username, viewAccount)
+	if status == http.StatusInternalServerError {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+	if err != nil {
+		if _, valid := server.approverOf(ctx, request, authPayload.Username); !valid {
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, request)
+}
+
+type listTransferRequestsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listTransferRequests lists the transfer requests out of the user's accounts and out of the accounts the user approves,
+// most recent first
+func (server *Server) listTransferRequests(ctx *gin.Context) {
+	var req listTransferRequestsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	requests, err := server.store.ListUserTransferRequests(ctx, db.ListUserTransferRequestsParams{
+		Username: authPayload.Username,
+		Status: sql.NullString{String: req.Status, Valid: req.Status != ""},
+		PageLimit: req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, requests)
+}
+
+// approveTransferRequestResponse is the transfer an approved request was made with together with the request
+type approveTransferRequestResponse struct {
+	transferTxResponse
+	TransferRequest db.TransferRequest `json:"transfer_request"`
+}
+
+// approveTransferRequest makes the transfer of a pending request
+// Only an approver of the from account other than the user who requested the transfer can approve it
+func (server *Server) approveTransferRequest(ctx *gin.Context) {
+	var req getTransferRequestRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	request, _, valid := server.loadTransferRequest(ctx, req.ID)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	approver, valid := server.approverOf(ctx, request, authPayload.Username)
+	if !valid {
+		return
+	}
+
+	result, err := server.store.ApproveTransferRequestTx(ctx, db.ApproveTransferRequestTxParams{
+		RequestID: request.ID,
+		Approver: approver.Username,
+		DefaultLimits: server.defaultTransferLimits(),
+	})
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, approveTransferRequestResponse{
+		transferTxResponse: newTransferTxResponse(result.TransferTxResult),
+		TransferRequest: result.TransferRequest,
+	})
+}
+
+// rejectTransferRequest turns down a pending request without moving any money
+// Only an approver of the from account other than the user who requested the transfer can reject it
+func (server *Server) rejectTransferRequest(ctx *gin.Context) {
+	var req getTransferRequestRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	request, _, valid := server.loadTransferRequest(ctx, req.ID)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	approver, valid := server.approverOf(ctx, request, authPayload.Username)
+	if !valid {
+		return
+	}
+
+	// only a pending request is updated, so none is left for one that was already decided
+	request, err := server.store.DecideTransferRequest(ctx, db.DecideTransferRequestParams{
+		ID: request.ID,
+		Status: db.TransferRequestRejected,
+		DecidedBy: sql.NullString{String: approver.Username, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = db.ErrTransferRequestNotPending
+		}
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request)
+}
+
+// loadTransferRequest loads a transfer request together with the account it transfers from
+func (server *Server) loadTransferRequest(ctx *gin.Context, id int64) (request db.TransferRequest, fromAccount db.Account, valid bool) {
+	request, err := server.store.GetTransferRequest(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	fromAccount, err = server.store.GetAccount(ctx, request.FromAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	return request, fromAccount, true
+}
+
+// approverOf checks that the user is an approver of the request's from account and didn't request the transfer themselves
+func (server *Server) approverOf(ctx *gin.Context, request db.TransferRequest, username string) (db.AccountApprover, bool) {
+	approver, err := server.store.GetAccountApprover(ctx, db.GetAccountApproverParams{
+		AccountID: request.FromAccountID,
+		Username: username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err := errors.New("transfer request doesn't belong to the authenticated user")
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return approver, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return approver, false
+	}
+
+	if request.RequestedBy == username {
+		err := errors.New("a transfer request can't be decided on by the user who made it")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return approver, false
+	}
+
+	return approver, true
+}
@@ -0,0 +1,297 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/token"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproveTransferRequest(t *testing.T) {
+	owner, _ := randomUser(t)
+	approver, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+
+	fromAccount := randomAccount(owner.Username)
+	toAccount := randomAccount(recipient.Username)
+	request := randomTransferRequest(fromAccount, toAccount)
+
+	approverOf := db.AccountApprover{AccountID: fromAccount.ID, Username: approver.Username}
+
+	testCases := []struct {
+		name          string
+		requestID     int64
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			requestID: request.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, autorizationTypeBearer, approver.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().
+					GetAccountApprover(gomock.Any(), gomock.Eq(db.GetAccountApproverParams{AccountID: fromAccount.ID, Username: approver.Username})).
+					Times(1).
+					Return(approverOf, nil)
+
+				arg := db.ApproveTransferRequestTxParams{
+					RequestID:     request.ID,
+					Approver:      approver.Username,
+					DefaultLimits: testDefaultTransferLimits(),
+				}
+				store.EXPECT().ApproveTransferRequestTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:      "NotApprover",
+			requestID: request.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, autorizationTypeBearer, recipient.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountApprover(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountApprover{}, sql.ErrNoRows)
+				store.EXPECT().ApproveTransferRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "OwnRequest",
+			requestID: request.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, autorizationTypeBearer, approver.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				ownRequest := request
+				ownRequest.RequestedBy = approver.Username
+
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(ownRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountApprover(gomock.Any(), gomock.Any()).Times(1).Return(approverOf, nil)
+				store.EXPECT().ApproveTransferRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "NotPending",
+			requestID: request.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, autorizationTypeBearer, approver.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountApprover(gomock.Any(), gomock.Any()).Times(1).Return(approverOf, nil)
+				store.EXPECT().
+					ApproveTransferRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApproveTransferRequestTxResult{}, db.ErrTransferRequestNotPending)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchErrorCode(t, recorder.Body, errCodeTransferRequestNotPending)
+			},
+		},
+		{
+			name:      "InsufficientFunds",
+			requestID: request.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, autorizationTypeBearer, approver.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountApprover(gomock.Any(), gomock.Any()).Times(1).Return(approverOf, nil)
+				store.EXPECT().
+					ApproveTransferRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApproveTransferRequestTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyMatchErrorCode(t, recorder.Body, errCodeInsufficientFunds)
+			},
+		},
+		{
+			name:      "NotFound",
+			requestID: request.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, autorizationTypeBearer, approver.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(db.TransferRequest{}, sql.ErrNoRows)
+				store.EXPECT().ApproveTransferRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidID",
+			requestID: 0,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, autorizationTypeBearer, approver.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ApproveTransferRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/transfer_requests/%d/approve", tc.requestID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRejectTransferRequest(t *testing.T) {
+	owner, _ := randomUser(t)
+	approver, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+
+	fromAccount := randomAccount(owner.Username)
+	toAccount := randomAccount(recipient.Username)
+	request := randomTransferRequest(fromAccount, toAccount)
+
+	approverOf := db.AccountApprover{AccountID: fromAccount.ID, Username: approver.Username}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, autorizationTypeBearer, approver.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountApprover(gomock.Any(), gomock.Any()).Times(1).Return(approverOf, nil)
+
+				arg := db.DecideTransferRequestParams{
+					ID:        request.ID,
+					Status:    db.TransferRequestRejected,
+					DecidedBy: sql.NullString{String: approver.Username, Valid: true},
+				}
+				rejected := request
+				rejected.Status = db.TransferRequestRejected
+				store.EXPECT().DecideTransferRequest(gomock.Any(), gomock.Eq(arg)).Times(1).Return(rejected, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotPending",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, autorizationTypeBearer, approver.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountApprover(gomock.Any(), gomock.Any()).Times(1).Return(approverOf, nil)
+				store.EXPECT().DecideTransferRequest(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferRequest{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchErrorCode(t, recorder.Body, errCodeTransferRequestNotPending)
+			},
+		},
+		{
+			name: "Owner",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, autorizationTypeBearer, owner.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountApprover(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountApprover{}, sql.ErrNoRows)
+				store.EXPECT().DecideTransferRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/transfer_requests/%d/reject", request.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func randomTransferRequest(fromAccount db.Account, toAccount db.Account) db.TransferRequest {
+	return db.TransferRequest{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        util.RandomMoney(),
+		RequestedBy:   fromAccount.Owner,
+		Status:        db.TransferRequestPending,
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/token"
 	"github.com/nokkvi/simplebank/util"
 )
 
@@ -25,6 +26,7 @@ type userResponse struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"passwordChangedAt"`
 	CreatedAt         time.Time `json:"createdAt"`
+	Role              string    `json:"role"`
 }
 
 func newUserResponse(user db.User) userResponse {
@@ -34,6 +36,7 @@ func newUserResponse(user db.User) userResponse {
 		Email: user.Email,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt: user.CreatedAt,
+		Role: user.Role,
 	}
 }
 
@@ -70,13 +73,7 @@ func (server *Server) createUser(ctx *gin.Context) {
 		return
 	}
 
-	rsp := userResponse {
-		Username: user.Username,
-		FullName: user.FullName,
-		Email: user.Email,
-		PasswordChangedAt: user.PasswordChangedAt,
-		CreatedAt: user.CreatedAt,
-	}
+	rsp := newUserResponse(user)
 	ctx.JSON(http.StatusCreated, rsp)
 }
 
@@ -153,4 +150,78 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		User: newUserResponse(user),
 	}
 	ctx.JSON(http.StatusOK, rsp)
-}
\ No newline at end of file
+}
+
+type updateUserRequest struct {
+	FullName string `json:"full_name" binding:"omitempty,max=100"`
+	Email    string `json:"email" binding:"omitempty,email"`
+}
+
+// updateUser changes the name or email of the user making the request, the fields left out stay as they are
+func (server *Server) updateUser(ctx *gin.Context) {
+	var req updateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(autorizationPayloadKey).(*token.Payload)
+	user, err := server.store.UpdateUser(ctx, db.UpdateUserParams{
+		Username: authPayload.Username,
+		FullName: sql.NullString{String: req.FullName, Valid: req.FullName != ""},
+		Email: sql.NullString{String: req.Email, Valid: req.Email != ""},
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// changePassword sets a new password for the user making the request once they give their current one
+// Every session of the user is blocked and the access tokens issued so far stop working, so they have to log in again
+func (server *Server) changePassword(ctx *gin.Context) {
+	var req changePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user := ctx.MustGet(autorizationUserKey).(db.User)
+	if err := util.CheckPassword(req.CurrentPassword, user.HashedPassword); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ChangePasswordTx(ctx, db.ChangePasswordTxParams{
+		Username: user.Username,
+		HashedPassword: hashedPassword,
+		ChangedAt: time.Now(),
+	})
+	if err != nil {
+		storeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(result.User))
+}
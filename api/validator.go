@@ -1,6 +1,8 @@
 package api
 
 import (
+	"reflect"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/nokkvi/simplebank/util"
 )
@@ -13,3 +15,35 @@ var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
 	return false
 }
 
+var validRecurrence validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if recurrence, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.IsSupportedRecurrence(recurrence)
+	}
+
+	return false
+}
+
+var validDayCount validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if convention, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.IsSupportedDayCount(convention)
+	}
+
+	return false
+}
+
+var validAccountNumber validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if number, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.ValidateAccountNumber(number) == nil
+	}
+
+	return false
+}
+
+// amountSign lets amounts be validated like integers by their sign, since what they are in minor units depends on their currency
+func amountSign(field reflect.Value) interface{} {
+	if amount, ok := field.Interface().(util.Amount); ok {
+		return amount.Sign()
+	}
+
+	return nil
+}
@@ -0,0 +1,3 @@
+COMMENT ON COLUMN "accounts"."balance" IS NULL;
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "balance_nonnegative";
@@ -0,0 +1,3 @@
+ALTER TABLE "accounts" ADD CONSTRAINT "balance_nonnegative" CHECK ("balance" >= 0);
+
+COMMENT ON COLUMN "accounts"."balance" IS 'must not be negative';
@@ -0,0 +1,11 @@
+COMMENT ON COLUMN "transfers"."amount" IS 'must be positive';
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "exchange_rate";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "to_amount";
+
+DROP TABLE IF EXISTS "exchange_quotes";
+
+DROP TABLE IF EXISTS "exchange_rates";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "role";
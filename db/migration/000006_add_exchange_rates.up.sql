@@ -0,0 +1,38 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'depositor';
+
+CREATE TABLE "exchange_rates" (
+  "from_currency" varchar NOT NULL,
+  "to_currency" varchar NOT NULL,
+  "rate" numeric NOT NULL CHECK ("rate" > 0),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("from_currency", "to_currency")
+);
+
+CREATE TABLE "exchange_quotes" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "from_currency" varchar NOT NULL,
+  "to_currency" varchar NOT NULL,
+  "rate" numeric NOT NULL,
+  "is_used" boolean NOT NULL DEFAULT false,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfers" ADD COLUMN "to_amount" bigint;
+
+UPDATE "transfers" SET "to_amount" = "amount";
+
+ALTER TABLE "transfers" ALTER COLUMN "to_amount" SET NOT NULL;
+
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate" numeric NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN "users"."role" IS 'depositor or banker';
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'amount of to_currency one unit of from_currency buys';
+
+COMMENT ON COLUMN "transfers"."amount" IS 'must be positive, in the from account currency';
+
+COMMENT ON COLUMN "transfers"."to_amount" IS 'credited to the to account, in its currency';
+
+ALTER TABLE "exchange_quotes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
@@ -0,0 +1,58 @@
+CREATE TABLE "scheduled_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "recurrence" varchar NOT NULL CHECK ("recurrence" IN ('once', 'weekly', 'monthly', 'last_business_day')),
+  "day_of_month" integer NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'active' CHECK ("status" IN ('active', 'completed', 'failed', 'cancelled')),
+  "next_run_at" timestamptz NOT NULL,
+  "next_attempt_at" timestamptz NOT NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "scheduled_transfer_runs" (
+  "id" bigserial PRIMARY KEY,
+  "scheduled_transfer_id" bigint NOT NULL,
+  "scheduled_for" timestamptz NOT NULL,
+  "status" varchar NOT NULL CHECK ("status" IN ('succeeded', 'failed')),
+  "transfer_id" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "scheduled_transfers" ("owner");
+
+CREATE INDEX ON "scheduled_transfers" ("status", "next_attempt_at");
+
+CREATE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id");
+
+CREATE UNIQUE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id", "scheduled_for") WHERE "status" = 'succeeded';
+
+COMMENT ON COLUMN "scheduled_transfers"."amount" IS 'must be positive';
+
+COMMENT ON COLUMN "scheduled_transfers"."recurrence" IS 'once, weekly, monthly or last_business_day';
+
+COMMENT ON COLUMN "scheduled_transfers"."day_of_month" IS 'day a monthly transfer runs on, clamped to the end of shorter months';
+
+COMMENT ON COLUMN "scheduled_transfers"."status" IS 'active, completed, failed or cancelled';
+
+COMMENT ON COLUMN "scheduled_transfers"."next_run_at" IS 'the occurrence that is due next';
+
+COMMENT ON COLUMN "scheduled_transfers"."next_attempt_at" IS 'when the next occurrence is tried, later than next_run_at after a failed attempt';
+
+COMMENT ON COLUMN "scheduled_transfers"."attempts" IS 'failed attempts of the next occurrence';
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."status" IS 'succeeded or failed';
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
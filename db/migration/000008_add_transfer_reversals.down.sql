@@ -0,0 +1,5 @@
+COMMENT ON COLUMN "transfers"."exchange_rate" IS NULL;
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "reversed_amount";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "reversal_of";
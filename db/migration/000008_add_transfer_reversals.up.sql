@@ -0,0 +1,15 @@
+ALTER TABLE "transfers" ADD COLUMN "reversal_of" bigint;
+
+ALTER TABLE "transfers" ADD COLUMN "reversed_amount" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "transfers" ADD CONSTRAINT "reversed_amount_within_amount" CHECK ("reversed_amount" >= 0 AND "reversed_amount" <= "amount");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "transfers" ("reversal_of");
+
+COMMENT ON COLUMN "transfers"."exchange_rate" IS 'a reversal records the rate of the transfer it reverses';
+
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'the transfer this one reverses';
+
+COMMENT ON COLUMN "transfers"."reversed_amount" IS 'how much of amount was reversed so far';
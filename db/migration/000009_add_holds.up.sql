@@ -0,0 +1,39 @@
+ALTER TABLE "accounts" ADD COLUMN "held_amount" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD COLUMN "available_balance" bigint NOT NULL GENERATED ALWAYS AS ("balance" - "held_amount") STORED;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "held_amount_within_balance" CHECK ("held_amount" >= 0 AND "held_amount" <= "balance");
+
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "status" varchar NOT NULL DEFAULT 'authorized' CHECK ("status" IN ('authorized', 'captured', 'voided', 'expired')),
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "holds" ("from_account_id");
+
+CREATE INDEX ON "holds" ("to_account_id");
+
+CREATE INDEX ON "holds" ("status", "expires_at");
+
+COMMENT ON COLUMN "accounts"."held_amount" IS 'sum of the authorized holds on the account';
+
+COMMENT ON COLUMN "accounts"."available_balance" IS 'balance that is not held';
+
+COMMENT ON COLUMN "holds"."amount" IS 'must be positive';
+
+COMMENT ON COLUMN "holds"."status" IS 'authorized, captured, voided or expired';
+
+COMMENT ON COLUMN "holds"."captured_amount" IS 'part of amount that was transferred on capture';
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
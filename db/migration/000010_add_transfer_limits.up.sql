@@ -0,0 +1,28 @@
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar,
+  "account_id" bigint,
+  "period" varchar NOT NULL CHECK ("period" IN ('daily', 'monthly')),
+  "max_amount" bigint NOT NULL CHECK ("max_amount" > 0),
+  "max_count" integer NOT NULL CHECK ("max_count" > 0),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "limit_of_user_or_account" CHECK (("username" IS NULL) <> ("account_id" IS NULL))
+);
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("username", "period");
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("account_id", "period");
+
+COMMENT ON COLUMN "transfer_limits"."username" IS 'set for a limit on all accounts of a user, per currency';
+
+COMMENT ON COLUMN "transfer_limits"."account_id" IS 'set for a limit on a single account';
+
+COMMENT ON COLUMN "transfer_limits"."period" IS 'daily or monthly, a rolling window ending now';
+
+COMMENT ON COLUMN "transfer_limits"."max_amount" IS 'total outgoing amount allowed within the period';
+
+COMMENT ON COLUMN "transfer_limits"."max_count" IS 'number of outgoing transfers allowed within the period';
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
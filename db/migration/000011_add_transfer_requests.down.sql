@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "transfer_requests";
+
+DROP TABLE IF EXISTS "account_approvers";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "approval_threshold";
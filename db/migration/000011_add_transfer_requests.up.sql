@@ -0,0 +1,47 @@
+ALTER TABLE "accounts" ADD COLUMN "approval_threshold" bigint CHECK ("approval_threshold" > 0);
+
+CREATE TABLE "account_approvers" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
+CREATE TABLE "transfer_requests" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "requested_by" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'approved', 'rejected')),
+  "decided_by" varchar,
+  "transfer_id" bigint,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "account_approvers" ("username");
+
+CREATE INDEX ON "transfer_requests" ("from_account_id", "status");
+
+COMMENT ON COLUMN "accounts"."approval_threshold" IS 'transfers above it wait for an approver, none need approval when null';
+
+COMMENT ON COLUMN "transfer_requests"."amount" IS 'must be positive';
+
+COMMENT ON COLUMN "transfer_requests"."status" IS 'pending, approved or rejected';
+
+COMMENT ON COLUMN "transfer_requests"."transfer_id" IS 'the transfer made on approval';
+
+ALTER TABLE "account_approvers" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_approvers" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("requested_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,17 @@
+ALTER TABLE "transfer_requests" DROP COLUMN IF EXISTS "metadata";
+
+ALTER TABLE "transfer_requests" DROP COLUMN IF EXISTS "reference";
+
+ALTER TABLE "transfer_requests" DROP COLUMN IF EXISTS "memo";
+
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "metadata";
+
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "reference";
+
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "memo";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "metadata";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "reference";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "memo";
@@ -0,0 +1,31 @@
+ALTER TABLE "transfers" ADD COLUMN "memo" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "reference" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+
+ALTER TABLE "entries" ADD COLUMN "memo" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "entries" ADD COLUMN "reference" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "entries" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+
+ALTER TABLE "transfer_requests" ADD COLUMN "memo" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfer_requests" ADD COLUMN "reference" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfer_requests" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN "transfers"."memo" IS 'what the transfer is for, as the sender put it';
+
+COMMENT ON COLUMN "transfers"."reference" IS 'identifier of the payment in another system, such as an invoice number';
+
+COMMENT ON COLUMN "transfers"."metadata" IS 'string values by key, copied to both entries';
+
+COMMENT ON COLUMN "entries"."memo" IS 'memo of the transfer that made the entry';
+
+COMMENT ON COLUMN "entries"."reference" IS 'reference of the transfer that made the entry';
+
+COMMENT ON COLUMN "entries"."metadata" IS 'metadata of the transfer that made the entry';
+
+COMMENT ON COLUMN "transfer_requests"."metadata" IS 'string values by key, given to the transfer on approval along with the memo and reference';
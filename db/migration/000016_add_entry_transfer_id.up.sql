@@ -0,0 +1,41 @@
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+-- entries made before this migration are matched to their transfer by account, amount and time,
+-- and the legs of a batch that look the same are paired up in the order they were made
+UPDATE "entries" SET "transfer_id" = m."transfer_id"
+FROM (
+  SELECT e."id" AS "entry_id", t."id" AS "transfer_id"
+  FROM (
+    SELECT "id", "account_id", "amount", "created_at",
+      row_number() OVER (PARTITION BY "account_id", "amount", "created_at" ORDER BY "id") AS "n"
+    FROM "entries"
+  ) e
+  JOIN (
+    SELECT "id", "from_account_id", "amount", "created_at",
+      row_number() OVER (PARTITION BY "from_account_id", "amount", "created_at" ORDER BY "id") AS "n"
+    FROM "transfers"
+  ) t ON e."account_id" = t."from_account_id" AND e."amount" = -t."amount" AND e."created_at" = t."created_at" AND e."n" = t."n"
+) m
+WHERE "entries"."id" = m."entry_id";
+
+UPDATE "entries" SET "transfer_id" = m."transfer_id"
+FROM (
+  SELECT e."id" AS "entry_id", t."id" AS "transfer_id"
+  FROM (
+    SELECT "id", "account_id", "amount", "created_at",
+      row_number() OVER (PARTITION BY "account_id", "amount", "created_at" ORDER BY "id") AS "n"
+    FROM "entries"
+  ) e
+  JOIN (
+    SELECT "id", "to_account_id", "to_amount", "created_at",
+      row_number() OVER (PARTITION BY "to_account_id", "to_amount", "created_at" ORDER BY "id") AS "n"
+    FROM "transfers"
+  ) t ON e."account_id" = t."to_account_id" AND e."amount" = t."to_amount" AND e."created_at" = t."created_at" AND e."n" = t."n"
+) m
+WHERE "entries"."id" = m."entry_id";
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'the transfer that made the entry';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,72 @@
+CREATE TABLE "account_products" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "annual_interest_rate" numeric NOT NULL DEFAULT 0 CHECK ("annual_interest_rate" >= 0),
+  "day_count_convention" varchar NOT NULL DEFAULT 'act/365' CHECK ("day_count_convention" IN ('act/365', 'act/360', '30/360')),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "accounts" ADD COLUMN "product" varchar;
+
+ALTER TABLE "accounts" ADD COLUMN "system_purpose" varchar CHECK ("system_purpose" IN ('interest'));
+
+ALTER TABLE "accounts" DROP CONSTRAINT "balance_nonnegative";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "balance_nonnegative" CHECK ("balance" >= 0 OR "system_purpose" IS NOT NULL);
+
+ALTER TABLE "accounts" DROP CONSTRAINT "held_amount_within_balance";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "held_amount_within_balance" CHECK ("held_amount" >= 0 AND ("held_amount" <= "balance" OR "system_purpose" IS NOT NULL));
+
+DROP INDEX "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_currency_key" ON "accounts" ("owner", "currency") WHERE "status" <> 'closed' AND "system_purpose" IS NULL;
+
+CREATE UNIQUE INDEX ON "accounts" ("system_purpose", "currency") WHERE "system_purpose" IS NOT NULL;
+
+-- the bank itself owns the system accounts, it has no password so nobody can log in as it
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email") VALUES ('simplebank', '', 'Simple Bank', 'system@simplebank.internal');
+
+CREATE TABLE "interest_accruals" (
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_interest_rate" numeric NOT NULL,
+  "day_count_convention" varchar NOT NULL,
+  "amount" numeric NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "accrual_date")
+);
+
+CREATE TABLE "interest_capitalizations" (
+  "account_id" bigint NOT NULL,
+  "period" date NOT NULL,
+  "amount" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "period")
+);
+
+COMMENT ON COLUMN "account_products"."annual_interest_rate" IS 'fraction of the balance earned over a year, 0.05 for 5%';
+
+COMMENT ON COLUMN "account_products"."day_count_convention" IS 'act/365, act/360 or 30/360';
+
+COMMENT ON COLUMN "accounts"."product" IS 'the product the account was opened as, its interest rate applies';
+
+COMMENT ON COLUMN "accounts"."system_purpose" IS 'set on the accounts of the bank itself, which may go negative';
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'balance at the end of the day';
+
+COMMENT ON COLUMN "interest_accruals"."amount" IS 'interest earned over the day, in fractions of a minor unit';
+
+COMMENT ON COLUMN "interest_capitalizations"."period" IS 'first day of the month the interest is paid for';
+
+COMMENT ON COLUMN "interest_capitalizations"."amount" IS 'whole minor units paid, the fractions left over are paid with the next month';
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("product") REFERENCES "account_products" ("code");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_capitalizations" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_capitalizations" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
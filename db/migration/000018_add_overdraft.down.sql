@@ -0,0 +1,45 @@
+DROP TABLE IF EXISTS "notifications";
+
+DROP TABLE IF EXISTS "overdraft_charges";
+
+-- the charged interest and fees are paid back into the accounts they were taken from so their balances still add up to their entries,
+-- the balance_nonnegative check fails the migration when an account is still overdrawn after that
+UPDATE "accounts" SET "balance" = "accounts"."balance" - "charged"."amount"
+FROM (
+  SELECT "e"."account_id", SUM("e"."amount") AS "amount" FROM "entries" "e"
+  JOIN "transfers" "t" ON "t"."id" = "e"."transfer_id"
+  WHERE "t"."to_account_id" IN (SELECT "id" FROM "accounts" WHERE "system_purpose" = 'overdraft')
+  GROUP BY "e"."account_id"
+) AS "charged"
+WHERE "accounts"."id" = "charged"."account_id" AND "accounts"."system_purpose" IS NULL;
+
+-- snapshots only save adding up entries, those that counted the charges are wrong now, so balances are worked back from the current one instead
+DELETE FROM "balance_snapshots";
+
+DELETE FROM "entries" WHERE "transfer_id" IN (SELECT "id" FROM "transfers" WHERE "to_account_id" IN (SELECT "id" FROM "accounts" WHERE "system_purpose" = 'overdraft'));
+
+DELETE FROM "transfers" WHERE "to_account_id" IN (SELECT "id" FROM "accounts" WHERE "system_purpose" = 'overdraft');
+
+DELETE FROM "entries" WHERE "account_id" IN (SELECT "id" FROM "accounts" WHERE "system_purpose" = 'overdraft');
+
+DELETE FROM "accounts" WHERE "system_purpose" = 'overdraft';
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "accounts_system_purpose_check";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_system_purpose_check" CHECK ("system_purpose" IN ('interest'));
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "held_amount_within_balance";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "held_amount_within_balance" CHECK ("held_amount" >= 0 AND ("held_amount" <= "balance" OR "system_purpose" IS NOT NULL));
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "balance_within_overdraft";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "balance_nonnegative" CHECK ("balance" >= 0 OR "system_purpose" IS NOT NULL);
+
+COMMENT ON COLUMN "accounts"."balance" IS 'must not be negative';
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "overdraft_daily_fee";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "overdraft_annual_rate";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "overdraft_limit";
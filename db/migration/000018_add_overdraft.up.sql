@@ -0,0 +1,63 @@
+ALTER TABLE "accounts" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0 CHECK ("overdraft_limit" >= 0);
+
+ALTER TABLE "accounts" ADD COLUMN "overdraft_annual_rate" numeric NOT NULL DEFAULT 0 CHECK ("overdraft_annual_rate" >= 0);
+
+ALTER TABLE "accounts" ADD COLUMN "overdraft_daily_fee" bigint NOT NULL DEFAULT 0 CHECK ("overdraft_daily_fee" >= 0);
+
+ALTER TABLE "accounts" DROP CONSTRAINT "balance_nonnegative";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "balance_within_overdraft" CHECK ("balance" + "overdraft_limit" >= 0 OR "system_purpose" IS NOT NULL);
+
+ALTER TABLE "accounts" DROP CONSTRAINT "held_amount_within_balance";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "held_amount_within_balance" CHECK ("held_amount" >= 0 AND ("held_amount" <= "balance" + "overdraft_limit" OR "system_purpose" IS NOT NULL));
+
+ALTER TABLE "accounts" DROP CONSTRAINT "accounts_system_purpose_check";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_system_purpose_check" CHECK ("system_purpose" IN ('interest', 'overdraft'));
+
+CREATE TABLE "overdraft_charges" (
+  "account_id" bigint NOT NULL,
+  "charge_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_rate" numeric NOT NULL,
+  "interest" bigint NOT NULL,
+  "fee" bigint NOT NULL,
+  "waived" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "charge_date")
+);
+
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "account_id" bigint,
+  "kind" varchar NOT NULL,
+  "message" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "notifications" ("username");
+
+COMMENT ON COLUMN "accounts"."balance" IS 'must not go below the overdraft limit, except on system accounts';
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'how far below zero the balance may go';
+
+COMMENT ON COLUMN "accounts"."overdraft_annual_rate" IS 'interest charged on the overdrawn balance over a year, 0.2 for 20%';
+
+COMMENT ON COLUMN "accounts"."overdraft_daily_fee" IS 'charged for every day the account ends overdrawn';
+
+COMMENT ON COLUMN "overdraft_charges"."balance" IS 'balance at the end of the day';
+
+COMMENT ON COLUMN "overdraft_charges"."waived" IS 'part of the interest and fee not charged because it would go over the overdraft limit';
+
+COMMENT ON COLUMN "notifications"."kind" IS 'overdraft_started or overdraft_charged';
+
+ALTER TABLE "overdraft_charges" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "overdraft_charges" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
@@ -0,0 +1,18 @@
+CREATE TABLE "account_members" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL CHECK ("role" IN ('owner', 'co_owner', 'viewer', 'payer')),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
+CREATE INDEX ON "account_members" ("username");
+
+COMMENT ON COLUMN "account_members"."role" IS 'owner, co_owner, viewer or payer';
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+INSERT INTO "account_members" ("account_id", "username", "role")
+SELECT "id", "owner", 'owner' FROM "accounts" WHERE "system_purpose" IS NULL;
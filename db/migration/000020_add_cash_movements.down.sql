@@ -0,0 +1,33 @@
+DROP TABLE IF EXISTS "cash_movements";
+
+-- the cash paid in and out and the fees are taken back from the accounts they moved so their balances still add up to their entries,
+-- the balance_within_overdraft check fails the migration when an account ends up overdrawn beyond its limit after that
+UPDATE "accounts" SET "balance" = "accounts"."balance" - "moved"."amount"
+FROM (
+  SELECT "e"."account_id", SUM("e"."amount") AS "amount" FROM "entries" "e"
+  JOIN "transfers" "t" ON "t"."id" = "e"."transfer_id"
+  WHERE "t"."from_account_id" IN (SELECT "id" FROM "accounts" WHERE "system_purpose" IN ('cash', 'suspense', 'fees'))
+    OR "t"."to_account_id" IN (SELECT "id" FROM "accounts" WHERE "system_purpose" IN ('cash', 'suspense', 'fees'))
+  GROUP BY "e"."account_id"
+) AS "moved"
+WHERE "accounts"."id" = "moved"."account_id" AND "accounts"."system_purpose" IS NULL;
+
+-- snapshots only save adding up entries, those that counted the cash movements are wrong now, so balances are worked back from the current one instead
+DELETE FROM "balance_snapshots";
+
+DELETE FROM "entries" WHERE "transfer_id" IN (
+  SELECT "id" FROM "transfers" WHERE "from_account_id" IN (SELECT "id" FROM "accounts" WHERE "system_purpose" IN ('cash', 'suspense', 'fees'))
+  OR "to_account_id" IN (SELECT "id" FROM "accounts" WHERE "system_purpose" IN ('cash', 'suspense', 'fees'))
+);
+
+DELETE FROM "transfers"
+WHERE "from_account_id" IN (SELECT "id" FROM "accounts" WHERE "system_purpose" IN ('cash', 'suspense', 'fees'))
+  OR "to_account_id" IN (SELECT "id" FROM "accounts" WHERE "system_purpose" IN ('cash', 'suspense', 'fees'));
+
+DELETE FROM "entries" WHERE "account_id" IN (SELECT "id" FROM "accounts" WHERE "system_purpose" IN ('cash', 'suspense', 'fees'));
+
+DELETE FROM "accounts" WHERE "system_purpose" IN ('cash', 'suspense', 'fees');
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "accounts_system_purpose_check";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_system_purpose_check" CHECK ("system_purpose" IN ('interest', 'overdraft'));
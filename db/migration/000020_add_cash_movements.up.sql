@@ -0,0 +1,62 @@
+ALTER TABLE "accounts" DROP CONSTRAINT "accounts_system_purpose_check";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_system_purpose_check" CHECK ("system_purpose" IN ('interest', 'overdraft', 'cash', 'suspense', 'fees'));
+
+CREATE TABLE "cash_movements" (
+  "id" bigserial PRIMARY KEY,
+  "kind" varchar NOT NULL CHECK ("kind" IN ('deposit', 'withdrawal')),
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "fee" bigint NOT NULL DEFAULT 0 CHECK ("fee" >= 0),
+  "banker" varchar NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "fee_transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "cash_movements" ("account_id");
+
+COMMENT ON COLUMN "cash_movements"."kind" IS 'deposit or withdrawal';
+
+COMMENT ON COLUMN "cash_movements"."fee" IS 'charged to the account and paid into the bank''s fees account';
+
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("banker") REFERENCES "users" ("username");
+
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("fee_transfer_id") REFERENCES "transfers" ("id");
+
+INSERT INTO "accounts" ("owner", "balance", "currency", "system_purpose")
+SELECT DISTINCT 'simplebank', 0, "currency", 'suspense' FROM "accounts" WHERE "system_purpose" IS NULL
+ON CONFLICT DO NOTHING;
+
+CREATE TEMPORARY TABLE "opening_balances" AS
+SELECT "account_id", "suspense_id", "created_at", "amount", nextval('transfers_id_seq') AS "transfer_id"
+FROM (
+  SELECT a."id" AS "account_id", s."id" AS "suspense_id", a."created_at",
+    a."balance" - COALESCE((SELECT SUM(e."amount") FROM "entries" e WHERE e."account_id" = a."id"), 0) AS "amount"
+  FROM "accounts" a
+  JOIN "accounts" s ON s."system_purpose" = 'suspense' AND s."currency" = a."currency"
+  WHERE a."system_purpose" IS NULL
+) b
+WHERE "amount" <> 0;
+
+INSERT INTO "transfers" ("id", "from_account_id", "to_account_id", "amount", "to_amount", "created_at", "memo", "reference")
+SELECT "transfer_id",
+  CASE WHEN "amount" > 0 THEN "suspense_id" ELSE "account_id" END,
+  CASE WHEN "amount" > 0 THEN "account_id" ELSE "suspense_id" END,
+  ABS("amount"), ABS("amount"), "created_at", 'opening balance', 'opening-balance'
+FROM "opening_balances";
+
+INSERT INTO "entries" ("account_id", "amount", "created_at", "memo", "reference", "transfer_id")
+SELECT "account_id", "amount", "created_at", 'opening balance', 'opening-balance', "transfer_id" FROM "opening_balances"
+UNION ALL
+SELECT "suspense_id", -"amount", "created_at", 'opening balance', 'opening-balance', "transfer_id" FROM "opening_balances";
+
+UPDATE "accounts" SET "balance" = "balance" - o."total"
+FROM (SELECT "suspense_id", SUM("amount") AS "total" FROM "opening_balances" GROUP BY "suspense_id") o
+WHERE "accounts"."id" = o."suspense_id";
+
+DROP TABLE "opening_balances";
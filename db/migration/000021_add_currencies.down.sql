@@ -0,0 +1,7 @@
+ALTER TABLE "exchange_rates" DROP CONSTRAINT IF EXISTS "exchange_rates_to_currency_fkey";
+
+ALTER TABLE "exchange_rates" DROP CONSTRAINT IF EXISTS "exchange_rates_from_currency_fkey";
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "accounts_currency_fkey";
+
+DROP TABLE IF EXISTS "currencies";
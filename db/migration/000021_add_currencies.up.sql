@@ -0,0 +1,27 @@
+CREATE TABLE "currencies" (
+  "code" varchar PRIMARY KEY CHECK ("code" ~ '^[A-Z]{3}$'),
+  "numeric_code" int NOT NULL UNIQUE CHECK ("numeric_code" BETWEEN 1 AND 999),
+  "minor_units" int NOT NULL CHECK ("minor_units" BETWEEN 0 AND 4),
+  "symbol" varchar NOT NULL DEFAULT '',
+  "enabled" boolean NOT NULL DEFAULT true,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 alphabetic code';
+
+COMMENT ON COLUMN "currencies"."numeric_code" IS 'ISO 4217 numeric code';
+
+COMMENT ON COLUMN "currencies"."minor_units" IS 'decimals of the currency, amounts are stored in its minor unit';
+
+COMMENT ON COLUMN "currencies"."enabled" IS 'only enabled currencies can be used for new accounts, transfers and rates';
+
+INSERT INTO "currencies" ("code", "numeric_code", "minor_units", "symbol") VALUES
+  ('USD', 840, 2, '$'),
+  ('EUR', 978, 2, '€'),
+  ('CAD', 124, 2, 'CA$');
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("from_currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("to_currency") REFERENCES "currencies" ("code");
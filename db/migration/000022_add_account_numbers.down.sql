@@ -0,0 +1,3 @@
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "number_required";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "number";
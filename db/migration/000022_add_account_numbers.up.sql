@@ -0,0 +1,7 @@
+ALTER TABLE "accounts" ADD COLUMN "number" varchar UNIQUE;
+
+-- accounts opened before account numbers are given random ones by the server when it starts, made with the bank codes of its config,
+-- so the constraint isn't validated against them here, only rows that are inserted or updated have to meet it
+ALTER TABLE "accounts" ADD CONSTRAINT "number_required" CHECK ("number" IS NOT NULL OR "system_purpose" IS NOT NULL) NOT VALID;
+
+COMMENT ON COLUMN "accounts"."number" IS 'public IBAN-style account number with mod-97 check digits, the bank''s own accounts have none';
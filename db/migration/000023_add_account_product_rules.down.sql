@@ -0,0 +1,37 @@
+-- a user could open several accounts in the same currency, which can't be merged back into one here,
+-- so migrating down fails until all but one of the open accounts of each user in each currency are closed
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM "accounts"
+    WHERE "status" <> 'closed' AND "system_purpose" IS NULL
+    GROUP BY "owner", "currency"
+    HAVING count(*) > 1
+  ) THEN
+    RAISE EXCEPTION 'users have more than one open account in a currency, close all but one of them before migrating down';
+  END IF;
+END
+$$;
+
+DROP INDEX IF EXISTS "accounts_owner_currency_product_idx";
+
+DROP INDEX IF EXISTS "owner_nickname_key";
+
+CREATE UNIQUE INDEX "owner_currency_key" ON "accounts" ("owner", "currency") WHERE "status" <> 'closed' AND "system_purpose" IS NULL;
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "nickname";
+
+-- accounts of the checking product go back to having none, as they were opened before products, and the seeded products are removed unless accounts still use them
+UPDATE "accounts" SET "product" = NULL WHERE "product" = 'checking' AND "system_purpose" IS NULL;
+
+DELETE FROM "account_products"
+WHERE "code" IN ('checking', 'savings', 'business')
+  AND NOT EXISTS (SELECT 1 FROM "accounts" WHERE "accounts"."product" = "account_products"."code");
+
+ALTER TABLE "account_products" DROP COLUMN IF EXISTS "overdraft_daily_fee";
+
+ALTER TABLE "account_products" DROP COLUMN IF EXISTS "overdraft_annual_rate";
+
+ALTER TABLE "account_products" DROP COLUMN IF EXISTS "overdraft_limit";
+
+ALTER TABLE "account_products" DROP COLUMN IF EXISTS "max_accounts";
@@ -0,0 +1,34 @@
+ALTER TABLE "account_products" ADD COLUMN "max_accounts" int NOT NULL DEFAULT 1 CHECK ("max_accounts" > 0);
+
+ALTER TABLE "account_products" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0 CHECK ("overdraft_limit" >= 0);
+
+ALTER TABLE "account_products" ADD COLUMN "overdraft_annual_rate" numeric NOT NULL DEFAULT 0 CHECK ("overdraft_annual_rate" >= 0);
+
+ALTER TABLE "account_products" ADD COLUMN "overdraft_daily_fee" bigint NOT NULL DEFAULT 0 CHECK ("overdraft_daily_fee" >= 0);
+
+INSERT INTO "account_products" ("code", "name") VALUES
+  ('checking', 'Checking'),
+  ('savings', 'Savings'),
+  ('business', 'Business')
+ON CONFLICT DO NOTHING;
+
+-- accounts opened without a product were checking accounts, the bank's own accounts have none
+UPDATE "accounts" SET "product" = 'checking' WHERE "product" IS NULL AND "system_purpose" IS NULL;
+
+ALTER TABLE "accounts" ADD COLUMN "nickname" varchar;
+
+DROP INDEX "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_nickname_key" ON "accounts" ("owner", "nickname") WHERE "status" <> 'closed';
+
+CREATE INDEX ON "accounts" ("owner", "currency", "product");
+
+COMMENT ON COLUMN "account_products"."max_accounts" IS 'how many open accounts of the product a user may have in each currency';
+
+COMMENT ON COLUMN "account_products"."overdraft_limit" IS 'overdraft limit accounts of the product are opened with';
+
+COMMENT ON COLUMN "account_products"."overdraft_annual_rate" IS 'overdraft interest accounts of the product are opened with';
+
+COMMENT ON COLUMN "account_products"."overdraft_daily_fee" IS 'overdraft fee accounts of the product are opened with';
+
+COMMENT ON COLUMN "accounts"."nickname" IS 'name the owner tells their accounts apart by, unique among their open accounts';
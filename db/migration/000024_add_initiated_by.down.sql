@@ -0,0 +1,3 @@
+ALTER TABLE "holds" DROP COLUMN IF EXISTS "initiated_by";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "initiated_by";
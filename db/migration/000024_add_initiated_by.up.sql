@@ -0,0 +1,24 @@
+ALTER TABLE "transfers" ADD COLUMN "initiated_by" varchar;
+
+ALTER TABLE "holds" ADD COLUMN "initiated_by" varchar;
+
+-- the user limits counted earlier transfers and holds towards the owner of the account they took money from
+UPDATE "transfers" SET "initiated_by" = "accounts"."owner"
+FROM "accounts"
+WHERE "accounts"."id" = "transfers"."from_account_id" AND "accounts"."system_purpose" IS NULL;
+
+UPDATE "holds" SET "initiated_by" = "accounts"."owner"
+FROM "accounts"
+WHERE "accounts"."id" = "holds"."from_account_id";
+
+ALTER TABLE "holds" ALTER COLUMN "initiated_by" SET NOT NULL;
+
+CREATE INDEX ON "transfers" ("initiated_by", "created_at");
+
+COMMENT ON COLUMN "transfers"."initiated_by" IS 'user who made the transfer, their transfer limits count it, null when the bank made it';
+
+COMMENT ON COLUMN "holds"."initiated_by" IS 'user who authorized the hold, their transfer limits count its capture';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
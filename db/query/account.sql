@@ -9,19 +9,51 @@ FOR NO KEY UPDATE;
 
 -- name: ListAccounts :many
 SELECT * FROM accounts
-WHERE owner = $1
+WHERE id IN (
+  SELECT account_id FROM account_members WHERE username = $1
+)
 ORDER BY id
 LIMIT $2
 OFFSET $3;
 
+-- name: GetAccountByNumber :one
+SELECT * FROM accounts
+WHERE number = sqlc.arg(number)::varchar LIMIT 1;
+
 -- name: CreateAccount :one
 INSERT INTO accounts (
-  owner, balance, currency
+  owner, balance, currency, product, number, nickname
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: CountOpenProductAccounts :one
+SELECT count(*) FROM accounts
+WHERE owner = $1 AND currency = $2 AND product = $3 AND status <> 'closed';
+
+-- name: GetSystemAccount :one
+SELECT * FROM accounts
+WHERE system_purpose = sqlc.arg(system_purpose) AND currency = sqlc.arg(currency)
+LIMIT 1;
+
+-- name: CreateSystemAccount :one
+INSERT INTO accounts (
+  owner, balance, currency, system_purpose
 ) VALUES (
-  $1, $2, $3
+  sqlc.arg(owner), 0, sqlc.arg(currency), sqlc.arg(system_purpose)
 )
+ON CONFLICT DO NOTHING
 RETURNING *;
 
+-- name: ListInterestBearingAccounts :many
+SELECT * FROM accounts
+WHERE id > sqlc.arg(after_id) AND status <> 'closed' AND product IN (
+  SELECT code FROM account_products WHERE annual_interest_rate > 0
+)
+ORDER BY id
+LIMIT sqlc.arg(page_limit);
+
 -- name: UpdateAccount :one
 UPDATE accounts
 set balance = $2
@@ -34,6 +66,49 @@ set balance = balance + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
--- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+set status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: AddAccountHeldAmount :one
+UPDATE accounts
+set held_amount = held_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateAccountApprovalThreshold :one
+UPDATE accounts
+set approval_threshold = sqlc.narg(approval_threshold)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateAccountNickname :one
+UPDATE accounts
+set nickname = sqlc.narg(nickname)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateAccountOverdraft :one
+UPDATE accounts
+set overdraft_limit = sqlc.arg(overdraft_limit), overdraft_annual_rate = sqlc.arg(overdraft_annual_rate), overdraft_daily_fee = sqlc.arg(overdraft_daily_fee)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListOverdraftAccounts :many
+SELECT * FROM accounts
+WHERE id > sqlc.arg(after_id) AND status <> 'closed' AND overdraft_limit > 0
+ORDER BY id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListUnnumberedAccounts :many
+SELECT * FROM accounts
+WHERE number IS NULL AND system_purpose IS NULL
+ORDER BY id
+LIMIT $1;
+
+-- name: SetAccountNumber :execrows
+UPDATE accounts
+SET number = sqlc.arg(number)
+WHERE id = sqlc.arg(id) AND number IS NULL;
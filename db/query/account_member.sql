@@ -0,0 +1,22 @@
+-- name: UpsertAccountMember :one
+INSERT INTO account_members (
+  account_id, username, role
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (account_id, username) DO UPDATE
+SET role = EXCLUDED.role
+RETURNING *;
+
+-- name: GetAccountMember :one
+SELECT * FROM account_members
+WHERE account_id = $1 AND username = $2 LIMIT 1;
+
+-- name: ListAccountMembers :many
+SELECT * FROM account_members
+WHERE account_id = $1
+ORDER BY created_at, username;
+
+-- name: DeleteAccountMember :execrows
+DELETE FROM account_members
+WHERE account_id = $1 AND username = $2;
@@ -0,0 +1,19 @@
+-- name: UpsertAccountProduct :one
+INSERT INTO account_products (
+  code, name, annual_interest_rate, day_count_convention, max_accounts, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (code) DO UPDATE
+SET name = EXCLUDED.name, annual_interest_rate = EXCLUDED.annual_interest_rate, day_count_convention = EXCLUDED.day_count_convention,
+  max_accounts = EXCLUDED.max_accounts, overdraft_limit = EXCLUDED.overdraft_limit,
+  overdraft_annual_rate = EXCLUDED.overdraft_annual_rate, overdraft_daily_fee = EXCLUDED.overdraft_daily_fee
+RETURNING *;
+
+-- name: GetAccountProduct :one
+SELECT * FROM account_products
+WHERE code = $1 LIMIT 1;
+
+-- name: ListAccountProducts :many
+SELECT * FROM account_products
+ORDER BY code;
@@ -0,0 +1,29 @@
+-- name: CreateBalanceSnapshots :execrows
+INSERT INTO balance_snapshots (account_id, taken_at, balance)
+SELECT a.id, sqlc.arg(taken_at)::timestamptz, a.balance - COALESCE((
+  SELECT SUM(e.amount) FROM entries e
+  WHERE e.account_id = a.id AND e.created_at >= sqlc.arg(taken_at)
+), 0)
+FROM accounts a
+WHERE a.status <> 'closed'
+ON CONFLICT (account_id, taken_at) DO NOTHING;
+
+-- name: GetLatestBalanceSnapshot :one
+SELECT * FROM balance_snapshots
+WHERE account_id = sqlc.arg(account_id) AND taken_at <= sqlc.arg(at)
+ORDER BY taken_at DESC
+LIMIT 1;
+
+-- name: GetNextBalanceSnapshot :one
+SELECT * FROM balance_snapshots
+WHERE account_id = sqlc.arg(account_id) AND taken_at > sqlc.arg(at)
+ORDER BY taken_at
+LIMIT 1;
+
+-- name: CountWritersStartedBefore :one
+SELECT count(*) FROM pg_stat_activity
+WHERE datname = current_database()
+  AND pid <> pg_backend_pid()
+  AND backend_type = 'client backend'
+  AND backend_xid IS NOT NULL
+  AND xact_start < sqlc.arg(started_before)::timestamptz;
@@ -0,0 +1,7 @@
+-- name: CreateCashMovement :one
+INSERT INTO cash_movements (
+  kind, account_id, amount, fee, banker, transfer_id, fee_transfer_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
@@ -0,0 +1,27 @@
+-- name: UpsertCurrency :one
+INSERT INTO currencies (
+  code, numeric_code, minor_units, symbol, enabled
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (code) DO UPDATE
+SET numeric_code = EXCLUDED.numeric_code, minor_units = EXCLUDED.minor_units, symbol = EXCLUDED.symbol,
+  enabled = EXCLUDED.enabled, updated_at = now()
+RETURNING *;
+
+-- name: GetCurrency :one
+SELECT * FROM currencies
+WHERE code = $1 LIMIT 1;
+
+-- name: ListCurrencies :many
+SELECT * FROM currencies
+ORDER BY code;
+
+-- name: GetCurrencyForUpdate :one
+SELECT * FROM currencies
+WHERE code = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: CountCurrencyAccounts :one
+SELECT count(*) FROM accounts
+WHERE currency = $1;
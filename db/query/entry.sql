@@ -4,15 +4,41 @@ WHERE id = $1 LIMIT 1;
 
 -- name: ListEntries :many
 SELECT * FROM entries
-WHERE account_id = $1
+WHERE account_id = sqlc.arg(account_id)
+  AND (sqlc.narg(search)::varchar IS NULL OR memo ILIKE sqlc.narg(search) OR reference ILIKE sqlc.narg(search))
 ORDER BY id
-LIMIT $2
-OFFSET $3;
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
 
 -- name: CreateEntry :one
 INSERT INTO entries (
-  account_id, amount
+  account_id, amount, memo, reference, metadata, transfer_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3, $4, $5, $6
 )
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: ListAccountEntriesBetween :many
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(created_after)
+  AND created_at < sqlc.arg(created_before)
+ORDER BY created_at, id;
+
+-- name: GetAccountEntriesTotalSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS amount
+FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(since);
+
+-- name: GetAccountEntriesTotalBetween :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS amount
+FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(created_after)
+  AND created_at < sqlc.arg(created_before);
+
+-- name: ListOrphanEntries :many
+SELECT * FROM entries
+WHERE transfer_id IS NULL
+ORDER BY id;
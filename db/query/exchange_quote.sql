@@ -0,0 +1,27 @@
+-- name: CreateExchangeQuote :one
+INSERT INTO exchange_quotes (
+  id,
+  username,
+  from_currency,
+  to_currency,
+  rate,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetExchangeQuote :one
+SELECT * FROM exchange_quotes
+WHERE id = $1 LIMIT 1;
+
+-- name: GetExchangeQuoteForUpdate :one
+SELECT * FROM exchange_quotes
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: MarkExchangeQuoteUsed :one
+UPDATE exchange_quotes
+SET is_used = true
+WHERE id = $1
+RETURNING *;
\ No newline at end of file
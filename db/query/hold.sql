@@ -0,0 +1,38 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+  from_account_id,
+  to_account_id,
+  amount,
+  expires_at,
+  initiated_by
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListExpiredHolds :many
+SELECT * FROM holds
+WHERE status = 'authorized' AND expires_at <= sqlc.arg(now)
+ORDER BY expires_at
+LIMIT sqlc.arg(page_limit);
+
+-- name: CaptureHold :one
+UPDATE holds
+SET status = 'captured', captured_amount = sqlc.arg(captured_amount), transfer_id = sqlc.arg(transfer_id)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateHoldStatus :one
+UPDATE holds
+SET status = $2
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,18 @@
+-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (
+  username, key, request_hash
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (username, key) DO NOTHING
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE username = $1 AND key = $2 LIMIT 1;
+
+-- name: UpdateIdempotencyKeyResponse :one
+UPDATE idempotency_keys
+SET response = $3
+WHERE username = $1 AND key = $2
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,50 @@
+-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals (
+  account_id, accrual_date, balance, annual_interest_rate, day_count_convention, amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (account_id, accrual_date) DO NOTHING
+RETURNING *;
+
+-- name: GetInterestAccrual :one
+SELECT * FROM interest_accruals
+WHERE account_id = $1 AND accrual_date = $2 LIMIT 1;
+
+-- name: GetLatestInterestAccrual :one
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date DESC
+LIMIT 1;
+
+-- name: ListInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetAccruedInterestBefore :one
+SELECT FLOOR(COALESCE(SUM(amount), 0))::bigint AS amount
+FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id) AND accrual_date < sqlc.arg(before);
+
+-- name: GetCapitalizedInterest :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS amount
+FROM interest_capitalizations
+WHERE account_id = $1;
+
+-- name: CreateInterestCapitalization :one
+INSERT INTO interest_capitalizations (
+  account_id, period
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (account_id, period) DO NOTHING
+RETURNING *;
+
+-- name: UpdateInterestCapitalization :one
+UPDATE interest_capitalizations
+set amount = sqlc.arg(amount), transfer_id = sqlc.narg(transfer_id)
+WHERE account_id = sqlc.arg(account_id) AND period = sqlc.arg(period)
+RETURNING *;
@@ -0,0 +1,14 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (
+  username, account_id, kind, message
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE username = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,27 @@
+-- name: CreateOverdraftCharge :one
+INSERT INTO overdraft_charges (
+  account_id, charge_date, balance, annual_rate, interest, fee, waived
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (account_id, charge_date) DO NOTHING
+RETURNING *;
+
+-- name: UpdateOverdraftChargeTransfer :one
+UPDATE overdraft_charges
+set transfer_id = sqlc.arg(transfer_id)
+WHERE account_id = sqlc.arg(account_id) AND charge_date = sqlc.arg(charge_date)
+RETURNING *;
+
+-- name: GetLatestOverdraftCharge :one
+SELECT * FROM overdraft_charges
+WHERE account_id = $1
+ORDER BY charge_date DESC
+LIMIT 1;
+
+-- name: ListOverdraftCharges :many
+SELECT * FROM overdraft_charges
+WHERE account_id = $1
+ORDER BY charge_date DESC
+LIMIT $2
+OFFSET $3;
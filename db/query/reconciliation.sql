@@ -0,0 +1,51 @@
+-- name: ListAccountBalanceMismatches :many
+SELECT a.id AS account_id, a.balance, COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id;
+
+-- name: ListAccountHeldAmountMismatches :many
+SELECT a.id AS account_id, a.held_amount, COALESCE(SUM(h.amount), 0)::bigint AS holds_total
+FROM accounts a
+LEFT JOIN holds h ON h.from_account_id = a.id AND h.status = 'authorized'
+GROUP BY a.id
+HAVING a.held_amount <> COALESCE(SUM(h.amount), 0)
+ORDER BY a.id;
+
+-- name: ListTransferEntryMismatches :many
+SELECT
+  t.id AS transfer_id,
+  t.from_account_id,
+  t.to_account_id,
+  t.amount,
+  t.to_amount,
+  COUNT(e.id) AS entry_count,
+  COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.from_account_id AND e.amount < 0), 0)::bigint AS from_entries_total,
+  COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.to_account_id AND e.amount > 0), 0)::bigint AS to_entries_total
+FROM transfers t
+LEFT JOIN entries e ON e.transfer_id = t.id
+GROUP BY t.id
+HAVING COUNT(e.id) <> 2
+  OR COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.from_account_id AND e.amount < 0), 0) <> -t.amount
+  OR COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.to_account_id AND e.amount > 0), 0) <> t.to_amount
+ORDER BY t.id;
+
+-- name: CountAccounts :one
+SELECT COUNT(*) FROM accounts;
+
+-- name: CountTransfers :one
+SELECT COUNT(*) FROM transfers;
+
+-- name: ListLedgerImbalances :many
+SELECT a.currency, SUM(e.amount)::bigint AS entries_total
+FROM entries e
+JOIN accounts a ON a.id = e.account_id
+LEFT JOIN transfers t ON t.id = e.transfer_id
+LEFT JOIN accounts fa ON fa.id = t.from_account_id
+LEFT JOIN accounts ta ON ta.id = t.to_account_id
+WHERE t.id IS NULL OR fa.currency = ta.currency
+GROUP BY a.currency
+HAVING SUM(e.amount) <> 0
+ORDER BY a.currency;
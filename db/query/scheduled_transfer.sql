@@ -0,0 +1,64 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+  owner,
+  from_account_id,
+  to_account_id,
+  amount,
+  recurrence,
+  day_of_month,
+  next_run_at,
+  next_attempt_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $7
+)
+RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetScheduledTransferForUpdate :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ClaimDueScheduledTransfer :one
+UPDATE scheduled_transfers
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id = (
+  SELECT id FROM scheduled_transfers
+  WHERE status = 'active' AND next_attempt_at <= sqlc.arg(now)
+  ORDER BY next_attempt_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ListScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateScheduledTransferAmount :one
+UPDATE scheduled_transfers
+SET amount = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateScheduledTransferSchedule :one
+UPDATE scheduled_transfers
+SET
+  status = $2,
+  next_run_at = $3,
+  next_attempt_at = $4,
+  attempts = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: CancelScheduledTransfer :one
+UPDATE scheduled_transfers
+SET status = 'cancelled'
+WHERE id = $1
+RETURNING *;
\ No newline at end of file
@@ -2,6 +2,11 @@
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: ListTransfers :many
 SELECT * FROM transfers
 WHERE from_account_id = $1 OR to_account_id = $2
@@ -9,10 +14,67 @@ ORDER BY id
 LIMIT $3
 OFFSET $4;
 
+-- name: ListUserTransfers :many
+SELECT
+  t.*,
+  fa.owner AS from_account_owner,
+  fa.currency AS from_account_currency,
+  ta.owner AS to_account_owner,
+  ta.currency AS to_account_currency
+FROM transfers t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+WHERE
+  (
+    (
+      sqlc.arg(direction)::varchar <> 'incoming'
+      AND fa.id IN (SELECT account_id FROM account_members WHERE username = sqlc.arg(username))
+      AND (sqlc.narg(account_id)::bigint IS NULL OR t.from_account_id = sqlc.narg(account_id))
+    ) OR (
+      sqlc.arg(direction)::varchar <> 'outgoing'
+      AND ta.id IN (SELECT account_id FROM account_members WHERE username = sqlc.arg(username))
+      AND (sqlc.narg(account_id)::bigint IS NULL OR t.to_account_id = sqlc.narg(account_id))
+    )
+  )
+  AND (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount))
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR t.created_at >= sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR t.created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(search)::varchar IS NULL OR t.memo ILIKE sqlc.narg(search) OR t.reference ILIKE sqlc.narg(search))
+ORDER BY t.id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
 -- name: CreateTransfer :one
 INSERT INTO transfers (
-  from_account_id, to_account_id, amount
+  from_account_id, to_account_id, amount, to_amount, exchange_rate, reversal_of, memo, reference, metadata, initiated_by
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 )
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: AddTransferReversedAmount :one
+UPDATE transfers
+SET reversed_amount = reversed_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetAccountOutgoingTotal :one
+SELECT COALESCE(SUM(t.amount), 0)::bigint AS amount, COUNT(*) AS count
+FROM transfers t
+JOIN accounts c ON c.id = t.to_account_id
+WHERE t.from_account_id = sqlc.arg(account_id)
+  AND c.system_purpose IS NULL
+  AND t.reversal_of IS NULL
+  AND t.created_at > sqlc.arg(since);
+
+-- name: GetUserOutgoingTotal :one
+SELECT COALESCE(SUM(t.amount), 0)::bigint AS amount, COUNT(*) AS count
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+JOIN accounts c ON c.id = t.to_account_id
+WHERE t.initiated_by = sqlc.arg(username)
+  AND a.currency = sqlc.arg(currency)
+  AND c.system_purpose IS NULL
+  AND t.reversal_of IS NULL
+  AND t.created_at > sqlc.arg(since);
@@ -0,0 +1,24 @@
+-- name: SetUserTransferLimit :one
+INSERT INTO transfer_limits (
+  username, period, max_amount, max_count
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (username, period) DO UPDATE
+SET max_amount = EXCLUDED.max_amount, max_count = EXCLUDED.max_count, updated_at = now()
+RETURNING *;
+
+-- name: SetAccountTransferLimit :one
+INSERT INTO transfer_limits (
+  account_id, period, max_amount, max_count
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (account_id, period) DO UPDATE
+SET max_amount = EXCLUDED.max_amount, max_count = EXCLUDED.max_count, updated_at = now()
+RETURNING *;
+
+-- name: ListTransferLimits :many
+SELECT * FROM transfer_limits
+WHERE username = sqlc.arg(username) OR account_id = sqlc.arg(account_id)
+ORDER BY id;
@@ -0,0 +1,40 @@
+-- name: CreateTransferRequest :one
+INSERT INTO transfer_requests (
+  from_account_id,
+  to_account_id,
+  amount,
+  requested_by,
+  memo,
+  reference,
+  metadata
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetTransferRequest :one
+SELECT * FROM transfer_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetTransferRequestForUpdate :one
+SELECT * FROM transfer_requests
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListUserTransferRequests :many
+SELECT r.* FROM transfer_requests r
+WHERE
+  (
+    r.from_account_id IN (SELECT account_id FROM account_members WHERE account_members.username = sqlc.arg(username))
+    OR r.from_account_id IN (SELECT account_id FROM account_approvers WHERE account_approvers.username = sqlc.arg(username))
+  )
+  AND (sqlc.narg(status)::varchar IS NULL OR r.status = sqlc.narg(status))
+ORDER BY r.id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: DecideTransferRequest :one
+UPDATE transfer_requests
+SET status = sqlc.arg(status), decided_by = sqlc.arg(decided_by), transfer_id = sqlc.narg(transfer_id), decided_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
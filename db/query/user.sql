@@ -8,4 +8,23 @@ RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM users
-WHERE username = $1 LIMIT 1;
\ No newline at end of file
+WHERE username = $1 LIMIT 1;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdateUser :one
+UPDATE users
+SET
+  full_name = COALESCE(sqlc.narg(full_name)::varchar, full_name),
+  email = COALESCE(sqlc.narg(email)::varchar, email)
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: UpdateUserPassword :one
+UPDATE users
+SET hashed_password = sqlc.arg(hashed_password), password_changed_at = sqlc.arg(password_changed_at)
+WHERE username = sqlc.arg(username)
+RETURNING *;
@@ -7,13 +7,14 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 set balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname
 `
 
 type AddAccountBalanceParams struct {
@@ -30,27 +31,102 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
 	)
 	return i, err
 }
 
+const addAccountHeldAmount = `-- name: AddAccountHeldAmount :one
+UPDATE accounts
+set held_amount = held_amount + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname
+`
+
+type AddAccountHeldAmountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddAccountHeldAmount(ctx context.Context, arg AddAccountHeldAmountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, addAccountHeldAmount, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const countOpenProductAccounts = `-- name: CountOpenProductAccounts :one
+SELECT count(*) FROM accounts
+WHERE owner = $1 AND currency = $2 AND product = $3 AND status <> 'closed'
+`
+
+type CountOpenProductAccountsParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+	Product  string `json:"product"`
+}
+
+func (q *Queries) CountOpenProductAccounts(ctx context.Context, arg CountOpenProductAccountsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOpenProductAccounts, arg.Owner, arg.Currency, arg.Product)
+	var i int64
+	err := row.Scan(&i)
+	return i, err
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
-  owner, balance, currency
+  owner, balance, currency, product, number, nickname
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6
 )
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname
 `
 
 type CreateAccountParams struct {
-	Owner    string `json:"owner"`
-	Balance  int64  `json:"balance"`
-	Currency string `json:"currency"`
+	Owner    string         `json:"owner"`
+	Balance  int64          `json:"balance"`
+	Currency string         `json:"currency"`
+	Product  sql.NullString `json:"product"`
+	Number   sql.NullString `json:"number"`
+	Nickname sql.NullString `json:"nickname"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.Product,
+		arg.Number,
+		arg.Nickname,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -58,22 +134,63 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1
+const createSystemAccount = `-- name: CreateSystemAccount :one
+INSERT INTO accounts (
+  owner, balance, currency, system_purpose
+) VALUES (
+  $1, 0, $2, $3
+)
+ON CONFLICT DO NOTHING
+RETURNING id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname
 `
 
-func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteAccount, id)
-	return err
+type CreateSystemAccountParams struct {
+	Owner         string         `json:"owner"`
+	Currency      string         `json:"currency"`
+	SystemPurpose sql.NullString `json:"systemPurpose"`
+}
+
+func (q *Queries) CreateSystemAccount(ctx context.Context, arg CreateSystemAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, createSystemAccount, arg.Owner, arg.Currency, arg.SystemPurpose)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
+	)
+	return i, err
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -86,12 +203,52 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const getAccountByNumber = `-- name: GetAccountByNumber :one
+SELECT id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname FROM accounts
+WHERE number = $1::varchar LIMIT 1
+`
+
+func (q *Queries) GetAccountByNumber(ctx context.Context, number string) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByNumber, number)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -105,26 +262,128 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const getSystemAccount = `-- name: GetSystemAccount :one
+SELECT id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname FROM accounts
+WHERE system_purpose = $1 AND currency = $2
+LIMIT 1
+`
+
+type GetSystemAccountParams struct {
+	SystemPurpose string `json:"systemPurpose"`
+	Currency      string `json:"currency"`
+}
+
+func (q *Queries) GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getSystemAccount, arg.SystemPurpose, arg.Currency)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
-WHERE owner = $1
+SELECT id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname FROM accounts
+WHERE id IN (
+  SELECT account_id FROM account_members WHERE username = $1
+)
 ORDER BY id
 LIMIT $2
 OFFSET $3
 `
 
 type ListAccountsParams struct {
-	Owner  string `json:"owner"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+	Username string `json:"username"`
+	Limit    int32  `json:"limit"`
+	Offset   int32  `json:"offset"`
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.db.QueryContext(ctx, listAccounts, arg.Owner, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listAccounts, arg.Username, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.HeldAmount,
+			&i.AvailableBalance,
+			&i.ApprovalThreshold,
+			&i.Status,
+			&i.Product,
+			&i.SystemPurpose,
+			&i.OverdraftLimit,
+			&i.OverdraftAnnualRate,
+			&i.OverdraftDailyFee,
+			&i.Number,
+			&i.Nickname,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInterestBearingAccounts = `-- name: ListInterestBearingAccounts :many
+SELECT id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname FROM accounts
+WHERE id > $1 AND status <> 'closed' AND product IN (
+  SELECT code FROM account_products WHERE annual_interest_rate > 0
+)
+ORDER BY id
+LIMIT $2
+`
+
+type ListInterestBearingAccountsParams struct {
+	AfterID   int64 `json:"afterID"`
+	PageLimit int32 `json:"pageLimit"`
+}
+
+func (q *Queries) ListInterestBearingAccounts(ctx context.Context, arg ListInterestBearingAccountsParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listInterestBearingAccounts, arg.AfterID, arg.PageLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +397,17 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.HeldAmount,
+			&i.AvailableBalance,
+			&i.ApprovalThreshold,
+			&i.Status,
+			&i.Product,
+			&i.SystemPurpose,
+			&i.OverdraftLimit,
+			&i.OverdraftAnnualRate,
+			&i.OverdraftDailyFee,
+			&i.Number,
+			&i.Nickname,
 		); err != nil {
 			return nil, err
 		}
@@ -152,11 +422,129 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 	return items, nil
 }
 
+const listOverdraftAccounts = `-- name: ListOverdraftAccounts :many
+SELECT id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname FROM accounts
+WHERE id > $1 AND status <> 'closed' AND overdraft_limit > 0
+ORDER BY id
+LIMIT $2
+`
+
+type ListOverdraftAccountsParams struct {
+	AfterID   int64 `json:"afterID"`
+	PageLimit int32 `json:"pageLimit"`
+}
+
+func (q *Queries) ListOverdraftAccounts(ctx context.Context, arg ListOverdraftAccountsParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listOverdraftAccounts, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.HeldAmount,
+			&i.AvailableBalance,
+			&i.ApprovalThreshold,
+			&i.Status,
+			&i.Product,
+			&i.SystemPurpose,
+			&i.OverdraftLimit,
+			&i.OverdraftAnnualRate,
+			&i.OverdraftDailyFee,
+			&i.Number,
+			&i.Nickname,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnnumberedAccounts = `-- name: ListUnnumberedAccounts :many
+SELECT id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname FROM accounts
+WHERE number IS NULL AND system_purpose IS NULL
+ORDER BY id
+LIMIT $1
+`
+
+func (q *Queries) ListUnnumberedAccounts(ctx context.Context, limit int32) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listUnnumberedAccounts, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.HeldAmount,
+			&i.AvailableBalance,
+			&i.ApprovalThreshold,
+			&i.Status,
+			&i.Product,
+			&i.SystemPurpose,
+			&i.OverdraftLimit,
+			&i.OverdraftAnnualRate,
+			&i.OverdraftDailyFee,
+			&i.Number,
+			&i.Nickname,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setAccountNumber = `-- name: SetAccountNumber :execrows
+UPDATE accounts
+SET number = $1
+WHERE id = $2 AND number IS NULL
+`
+
+type SetAccountNumberParams struct {
+	Number sql.NullString `json:"number"`
+	ID     int64          `json:"id"`
+}
+
+func (q *Queries) SetAccountNumber(ctx context.Context, arg SetAccountNumberParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setAccountNumber, arg.Number, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 set balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname
 `
 
 type UpdateAccountParams struct {
@@ -173,6 +561,168 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const updateAccountApprovalThreshold = `-- name: UpdateAccountApprovalThreshold :one
+UPDATE accounts
+set approval_threshold = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname
+`
+
+type UpdateAccountApprovalThresholdParams struct {
+	ApprovalThreshold sql.NullInt64 `json:"approvalThreshold"`
+	ID                int64         `json:"id"`
+}
+
+func (q *Queries) UpdateAccountApprovalThreshold(ctx context.Context, arg UpdateAccountApprovalThresholdParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountApprovalThreshold, arg.ApprovalThreshold, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const updateAccountNickname = `-- name: UpdateAccountNickname :one
+UPDATE accounts
+set nickname = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname
+`
+
+type UpdateAccountNicknameParams struct {
+	Nickname sql.NullString `json:"nickname"`
+	ID       int64          `json:"id"`
+}
+
+func (q *Queries) UpdateAccountNickname(ctx context.Context, arg UpdateAccountNicknameParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountNickname, arg.Nickname, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const updateAccountOverdraft = `-- name: UpdateAccountOverdraft :one
+UPDATE accounts
+set overdraft_limit = $1, overdraft_annual_rate = $2, overdraft_daily_fee = $3
+WHERE id = $4
+RETURNING id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname
+`
+
+type UpdateAccountOverdraftParams struct {
+	OverdraftLimit      int64  `json:"overdraftLimit"`
+	OverdraftAnnualRate string `json:"overdraftAnnualRate"`
+	OverdraftDailyFee   int64  `json:"overdraftDailyFee"`
+	ID                  int64  `json:"id"`
+}
+
+func (q *Queries) UpdateAccountOverdraft(ctx context.Context, arg UpdateAccountOverdraftParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraft,
+		arg.OverdraftLimit,
+		arg.OverdraftAnnualRate,
+		arg.OverdraftDailyFee,
+		arg.ID,
+	)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+set status = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, held_amount, available_balance, approval_threshold, status, product, system_purpose, overdraft_limit, overdraft_annual_rate, overdraft_daily_fee, number, nickname
+`
+
+type UpdateAccountStatusParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.Status, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.ApprovalThreshold,
+		&i.Status,
+		&i.Product,
+		&i.SystemPurpose,
+		&i.OverdraftLimit,
+		&i.OverdraftAnnualRate,
+		&i.OverdraftDailyFee,
+		&i.Number,
+		&i.Nickname,
 	)
 	return i, err
 }
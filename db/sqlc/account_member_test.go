@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAccountTxAddsOwner(t *testing.T) {
+	account := createRandomAccount(t)
+
+	member, err := testQueries.GetAccountMember(context.Background(), GetAccountMemberParams{
+		AccountID: account.ID,
+		Username: account.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, MemberOwner, member.Role)
+	require.NotZero(t, member.CreatedAt)
+}
+
+func TestUpsertAccountMember(t *testing.T) {
+	account := createRandomAccount(t)
+	user := createRandomUser(t)
+
+	arg := UpsertAccountMemberParams{
+		AccountID: account.ID,
+		Username: user.Username,
+		Role: MemberViewer,
+	}
+	member, err := testQueries.UpsertAccountMember(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.AccountID, member.AccountID)
+	require.Equal(t, arg.Username, member.Username)
+	require.Equal(t, MemberViewer, member.Role)
+
+	// adding the user again changes the role
+	arg.Role = MemberPayer
+	member, err = testQueries.UpsertAccountMember(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, MemberPayer, member.Role)
+
+	members, err := testQueries.ListAccountMembers(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	require.Equal(t, account.Owner, members[0].Username)
+	require.Equal(t, user.Username, members[1].Username)
+
+	arg.Role = "admin"
+	_, err = testQueries.UpsertAccountMember(context.Background(), arg)
+	require.Error(t, err)
+}
+
+func TestSharedAccountsAreListed(t *testing.T) {
+	account := createRandomAccount(t)
+	user := createRandomUser(t)
+
+	_, err := testQueries.UpsertAccountMember(context.Background(), UpsertAccountMemberParams{
+		AccountID: account.ID,
+		Username: user.Username,
+		Role: MemberCoOwner,
+	})
+	require.NoError(t, err)
+
+	accounts, err := testQueries.ListAccounts(context.Background(), ListAccountsParams{
+		Username: user.Username,
+		Limit: 5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, account.ID, accounts[0].ID)
+}
+
+func TestDeleteAccountMember(t *testing.T) {
+	account := createRandomAccount(t)
+	user := createRandomUser(t)
+
+	arg := DeleteAccountMemberParams{
+		AccountID: account.ID,
+		Username: user.Username,
+	}
+	count, err := testQueries.DeleteAccountMember(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	_, err = testQueries.UpsertAccountMember(context.Background(), UpsertAccountMemberParams{
+		AccountID: account.ID,
+		Username: user.Username,
+		Role: MemberViewer,
+	})
+	require.NoError(t, err)
+
+	count, err = testQueries.DeleteAccountMember(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	_, err = testQueries.GetAccountMember(context.Background(), GetAccountMemberParams{
+		AccountID: account.ID,
+		Username: user.Username,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
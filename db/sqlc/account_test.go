@@ -16,21 +16,38 @@ func createRandomAccount(t *testing.T) Account {
 		Owner: user.Username,
 		Balance: util.RandomMoney(),
 		Currency: util.RandomCurrency(),
+		Number: sql.NullString{String: util.RandomAccountNumber(), Valid: true},
 	}
 
-	account, err := testQueries.CreateAccount(context.Background(), arg)
+	account, err := NewStore(testDB).CreateAccountTx(context.Background(), arg)
 	require.NoError(t, err)
 	require.NotEmpty(t, account)
 
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, arg.Number, account.Number)
+	require.Zero(t, account.HeldAmount)
+	require.Equal(t, arg.Balance, account.AvailableBalance)
+	require.Equal(t, AccountActive, account.Status)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
 	return account
 }
 
+func createRandomAccountWithBalance(t *testing.T, balance int64) Account {
+	account := createRandomAccount(t)
+
+	account, err := testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID: account.ID,
+		Balance: balance,
+	})
+	require.NoError(t, err)
+	require.Equal(t, balance, account.Balance)
+	return account
+}
+
 func TestCreateAccount(t *testing.T) {
 	createRandomAccount(t)
 }
@@ -48,6 +65,25 @@ func TestGetAccount(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
+func TestGetAccountByNumber(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2, err := testQueries.GetAccountByNumber(context.Background(), account1.Number.String)
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, account2.ID)
+	require.Equal(t, account1.Number, account2.Number)
+
+	_, err = testQueries.GetAccountByNumber(context.Background(), util.RandomAccountNumber())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// two accounts can't have the same number
+	_, err = testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner: createRandomUser(t).Username,
+		Currency: util.RandomCurrency(),
+		Number: account1.Number,
+	})
+	require.Error(t, err)
+}
+
 func TestUpdateAccount(t *testing.T) {
 	account1 := createRandomAccount(t)
 
@@ -67,16 +103,21 @@ func TestUpdateAccount(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
-func TestDeleteAccount(t *testing.T) {
+func TestUpdateAccountStatus(t *testing.T) {
 	account1 := createRandomAccount(t)
-	err := testQueries.DeleteAccount(context.Background(), account1.ID)
-
+	account2, err := testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID: account1.ID,
+		Status: AccountFrozen,
+	})
 	require.NoError(t, err)
+	require.Equal(t, AccountFrozen, account2.Status)
+	require.Equal(t, account1.Balance, account2.Balance)
 
-	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
+	_, err = testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID: account1.ID,
+		Status: "deleted",
+	})
 	require.Error(t, err)
-	require.EqualError(t, err, sql.ErrNoRows.Error())
-	require.Empty(t, account2)
 }
 
 func TestListAccounts(t *testing.T) {
@@ -87,7 +128,7 @@ func TestListAccounts(t *testing.T) {
 	}
 
 	arg := ListAccountsParams{
-		Owner: lastAccount.Owner,
+		Username: lastAccount.Owner,
 		Limit: int32(limit),
 		Offset: 0,
 	}
@@ -100,4 +141,106 @@ func TestListAccounts(t *testing.T) {
 		require.NotEmpty(t, account)
 		require.Equal(t, lastAccount.Owner, account.Owner)
 	}
-}
\ No newline at end of file
+}
+
+func TestUpdateAccountNegativeBalance(t *testing.T) {
+	account1 := createRandomAccount(t)
+
+	_, err := testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID: account1.ID,
+		Balance: -1,
+	})
+	require.Error(t, err)
+	require.True(t, isConstraintViolation(err, checkViolation, balanceWithinOverdraftConstraint))
+}
+
+func TestCreateAccountTxProductRules(t *testing.T) {
+	store := NewStore(testDB)
+	product, err := testQueries.UpsertAccountProduct(context.Background(), UpsertAccountProductParams{
+		Code: util.RandomOwner(),
+		Name: util.RandomOwner(),
+		AnnualInterestRate: "0",
+		DayCountConvention: util.DayCountActual365,
+		MaxAccounts: 2,
+		OverdraftLimit: 5000,
+		OverdraftAnnualRate: "0.2",
+		OverdraftDailyFee: 100,
+	})
+	require.NoError(t, err)
+
+	user := createRandomUser(t)
+	arg := CreateAccountParams{
+		Owner: user.Username,
+		Currency: util.USD,
+		Product: sql.NullString{String: product.Code, Valid: true},
+	}
+
+	var accounts []Account
+	for i := 0; i < 2; i++ {
+		arg.Number = sql.NullString{String: util.RandomAccountNumber(), Valid: true}
+		account, err := store.CreateAccountTx(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, product.Code, account.Product.String)
+		require.Equal(t, product.OverdraftLimit, account.OverdraftLimit)
+		require.Equal(t, product.OverdraftDailyFee, account.OverdraftDailyFee)
+		accounts = append(accounts, account)
+	}
+
+	arg.Number = sql.NullString{String: util.RandomAccountNumber(), Valid: true}
+	_, err = store.CreateAccountTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrProductAccountLimit)
+
+	// the limit is per currency
+	arg.Currency = util.EUR
+	_, err = store.CreateAccountTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// closed accounts don't count
+	_, err = testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID: accounts[0].ID,
+		Status: AccountClosed,
+	})
+	require.NoError(t, err)
+
+	arg.Currency = util.USD
+	arg.Number = sql.NullString{String: util.RandomAccountNumber(), Valid: true}
+	_, err = store.CreateAccountTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	arg.Product = sql.NullString{String: util.RandomOwner(), Valid: true}
+	arg.Number = sql.NullString{String: util.RandomAccountNumber(), Valid: true}
+	_, err = store.CreateAccountTx(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUpdateAccountNickname(t *testing.T) {
+	account1 := createRandomAccount(t)
+
+	nickname := sql.NullString{String: util.RandomOwner(), Valid: true}
+	account2, err := testQueries.UpdateAccountNickname(context.Background(), UpdateAccountNicknameParams{
+		ID: account1.ID,
+		Nickname: nickname,
+	})
+	require.NoError(t, err)
+	require.Equal(t, nickname, account2.Nickname)
+
+	// the owner's other open accounts can't have the same nickname
+	account3, err := NewStore(testDB).CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner: account1.Owner,
+		Currency: account1.Currency,
+		Number: sql.NullString{String: util.RandomAccountNumber(), Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.UpdateAccountNickname(context.Background(), UpdateAccountNicknameParams{
+		ID: account3.ID,
+		Nickname: nickname,
+	})
+	require.True(t, isConstraintViolation(err, "unique_violation", "owner_nickname_key"))
+
+	account2, err = testQueries.UpdateAccountNickname(context.Background(), UpdateAccountNicknameParams{
+		ID: account1.ID,
+	})
+	require.NoError(t, err)
+	require.False(t, account2.Nickname.Valid)
+}
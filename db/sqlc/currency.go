@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+
+	"github.com/nokkvi/simplebank/util"
+)
+
+// Registered returns the currency as the util currency registry holds it
+func (currency Currency) Registered() util.Currency {
+	return util.Currency{
+		Code: currency.Code,
+		NumericCode: currency.NumericCode,
+		MinorUnits: currency.MinorUnits,
+		Symbol: currency.Symbol,
+		Enabled: currency.Enabled,
+	}
+}
+
+// LoadCurrencyRegistry replaces the currencies in the util currency registry with the ones in the database
+func LoadCurrencyRegistry(ctx context.Context, q Querier) error {
+	currencies, err := q.ListCurrencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	registered := make([]util.Currency, len(currencies))
+	for i, currency := range currencies {
+		registered[i] = currency.Registered()
+	}
+
+	util.SetCurrencies(registered)
+	return nil
+}
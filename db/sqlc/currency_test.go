@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCurrencies(t *testing.T) {
+	currencies, err := testQueries.ListCurrencies(context.Background())
+	require.NoError(t, err)
+
+	codes := make([]string, len(currencies))
+	for i, currency := range currencies {
+		codes[i] = currency.Code
+	}
+	require.Subset(t, codes, []string{util.CAD, util.EUR, util.USD})
+}
+
+func TestUpsertCurrency(t *testing.T) {
+	arg := UpsertCurrencyParams{
+		Code: "KWD",
+		NumericCode: 414,
+		MinorUnits: 3,
+		Symbol: "KD",
+		Enabled: false,
+	}
+	currency, err := testQueries.UpsertCurrency(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, "KWD", currency.Code)
+	require.Equal(t, int32(3), currency.MinorUnits)
+	require.False(t, currency.Enabled)
+
+	arg.Enabled = true
+	currency, err = testQueries.UpsertCurrency(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, currency.Enabled)
+
+	currency, err = testQueries.GetCurrency(context.Background(), "KWD")
+	require.NoError(t, err)
+	require.Equal(t, "KD", currency.Symbol)
+	require.Equal(t, util.Currency{Code: "KWD", NumericCode: 414, MinorUnits: 3, Symbol: "KD", Enabled: true}, currency.Registered())
+
+	// minor units past what ISO 4217 uses are rejected
+	arg.MinorUnits = 5
+	_, err = testQueries.UpsertCurrency(context.Background(), arg)
+	require.Error(t, err)
+}
+
+func TestUpsertCurrencyTxInUse(t *testing.T) {
+	store := NewStore(testDB)
+
+	arg := UpsertCurrencyParams{
+		Code: "BHD",
+		NumericCode: 48,
+		MinorUnits: 3,
+		Symbol: "BD",
+		Enabled: true,
+	}
+	_, err := store.UpsertCurrencyTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// nobody holds an account in it yet, so its minor units can still change
+	arg.MinorUnits = 2
+	currency, err := store.UpsertCurrencyTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), currency.MinorUnits)
+
+	user := createRandomUser(t)
+	_, err = store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner: user.Username,
+		Currency: arg.Code,
+		Number: sql.NullString{String: util.RandomAccountNumber(), Valid: true},
+	})
+	require.NoError(t, err)
+
+	arg.MinorUnits = 3
+	_, err = store.UpsertCurrencyTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrCurrencyInUse)
+
+	// everything else about it can still change
+	arg.MinorUnits = 2
+	arg.Symbol = "BHD"
+	currency, err = store.UpsertCurrencyTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, "BHD", currency.Symbol)
+}
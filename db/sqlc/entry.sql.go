@@ -7,36 +7,94 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
-  account_id, amount
+  account_id, amount, memo, reference, metadata, transfer_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3, $4, $5, $6
 )
-RETURNING id, account_id, amount, created_at
+RETURNING id, account_id, amount, created_at, memo, reference, metadata, transfer_id
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"accountID"`
-	Amount    int64 `json:"amount"`
+	AccountID  int64           `json:"accountID"`
+	Amount     int64           `json:"amount"`
+	Memo       string          `json:"memo"`
+	Reference  string          `json:"reference"`
+	Metadata   json.RawMessage `json:"metadata"`
+	TransferID sql.NullInt64   `json:"transferID"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.Memo,
+		arg.Reference,
+		arg.Metadata,
+		arg.TransferID,
+	)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.Reference,
+		&i.Metadata,
+		&i.TransferID,
 	)
 	return i, err
 }
 
+const getAccountEntriesTotalBetween = `-- name: GetAccountEntriesTotalBetween :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS amount
+FROM entries
+WHERE account_id = $1
+  AND created_at >= $2
+  AND created_at < $3
+`
+
+type GetAccountEntriesTotalBetweenParams struct {
+	AccountID     int64     `json:"accountID"`
+	CreatedAfter  time.Time `json:"createdAfter"`
+	CreatedBefore time.Time `json:"createdBefore"`
+}
+
+func (q *Queries) GetAccountEntriesTotalBetween(ctx context.Context, arg GetAccountEntriesTotalBetweenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getAccountEntriesTotalBetween, arg.AccountID, arg.CreatedAfter, arg.CreatedBefore)
+	var i int64
+	err := row.Scan(&i)
+	return i, err
+}
+
+const getAccountEntriesTotalSince = `-- name: GetAccountEntriesTotalSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS amount
+FROM entries
+WHERE account_id = $1
+  AND created_at >= $2
+`
+
+type GetAccountEntriesTotalSinceParams struct {
+	AccountID int64     `json:"accountID"`
+	Since     time.Time `json:"since"`
+}
+
+func (q *Queries) GetAccountEntriesTotalSince(ctx context.Context, arg GetAccountEntriesTotalSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getAccountEntriesTotalSince, arg.AccountID, arg.Since)
+	var i int64
+	err := row.Scan(&i)
+	return i, err
+}
+
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, memo, reference, metadata, transfer_id FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -48,26 +106,121 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.Reference,
+		&i.Metadata,
+		&i.TransferID,
 	)
 	return i, err
 }
 
+const listAccountEntriesBetween = `-- name: ListAccountEntriesBetween :many
+SELECT id, account_id, amount, created_at, memo, reference, metadata, transfer_id FROM entries
+WHERE account_id = $1
+  AND created_at >= $2
+  AND created_at < $3
+ORDER BY created_at, id
+`
+
+type ListAccountEntriesBetweenParams struct {
+	AccountID     int64     `json:"accountID"`
+	CreatedAfter  time.Time `json:"createdAfter"`
+	CreatedBefore time.Time `json:"createdBefore"`
+}
+
+func (q *Queries) ListAccountEntriesBetween(ctx context.Context, arg ListAccountEntriesBetweenParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountEntriesBetween, arg.AccountID, arg.CreatedAfter, arg.CreatedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Memo,
+			&i.Reference,
+			&i.Metadata,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, memo, reference, metadata, transfer_id FROM entries
 WHERE account_id = $1
+  AND ($2::varchar IS NULL OR memo ILIKE $2 OR reference ILIKE $2)
 ORDER BY id
-LIMIT $2
-OFFSET $3
+LIMIT $3
+OFFSET $4
 `
 
 type ListEntriesParams struct {
-	AccountID int64 `json:"accountID"`
-	Limit     int32 `json:"limit"`
-	Offset    int32 `json:"offset"`
+	AccountID  int64          `json:"accountID"`
+	Search     sql.NullString `json:"search"`
+	PageLimit  int32          `json:"pageLimit"`
+	PageOffset int32          `json:"pageOffset"`
 }
 
 func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
-	rows, err := q.db.QueryContext(ctx, listEntries, arg.AccountID, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listEntries,
+		arg.AccountID,
+		arg.Search,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Memo,
+			&i.Reference,
+			&i.Metadata,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrphanEntries = `-- name: ListOrphanEntries :many
+SELECT id, account_id, amount, created_at, memo, reference, metadata, transfer_id FROM entries
+WHERE transfer_id IS NULL
+ORDER BY id
+`
+
+func (q *Queries) ListOrphanEntries(ctx context.Context) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listOrphanEntries)
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +233,10 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Memo,
+			&i.Reference,
+			&i.Metadata,
+			&i.TransferID,
 		); err != nil {
 			return nil, err
 		}
@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +16,9 @@ func createRandomEntry(t *testing.T, account Account) Entry {
 	arg := CreateEntryParams{
 		AccountID: account.ID,
 		Amount: util.RandomMoney(),
+		Memo: util.RandomString(12),
+		Reference: util.RandomString(8),
+		Metadata: emptyMetadata,
 	}
 
 	entry, err := testQueries.CreateEntry(context.Background(), arg)
@@ -22,6 +27,8 @@ func createRandomEntry(t *testing.T, account Account) Entry {
 
 	require.Equal(t, arg.AccountID, entry.AccountID)
 	require.Equal(t, arg.Amount, entry.Amount)
+	require.Equal(t, arg.Memo, entry.Memo)
+	require.Equal(t, arg.Reference, entry.Reference)
 
 	require.NotZero(t, entry.ID)
 	require.NotZero(t, entry.CreatedAt)
@@ -55,8 +62,8 @@ func TestListEntries(t *testing.T) {
 
 	arg := ListEntriesParams{
 		AccountID: acc.ID,
-		Limit: int32(limit),
-		Offset: 5,
+		PageLimit: int32(limit),
+		PageOffset: 5,
 	}
 
 	entries, err := testQueries.ListEntries(context.Background(), arg)
@@ -66,4 +73,22 @@ func TestListEntries(t *testing.T) {
 	for _, entry := range entries {
 		require.NotEmpty(t, entry)
 	}
-}
\ No newline at end of file
+}
+
+func TestListEntriesSearch(t *testing.T) {
+	acc := createRandomAccount(t)
+	entry := createRandomEntry(t, acc)
+	createRandomEntry(t, acc)
+
+	for _, search := range []string{entry.Memo, strings.ToUpper(entry.Memo[2:6]), entry.Reference} {
+		entries, err := testQueries.ListEntries(context.Background(), ListEntriesParams{
+			AccountID: acc.ID,
+			Search: sql.NullString{String: "%" + search + "%", Valid: true},
+			PageLimit: 5,
+			PageOffset: 0,
+		})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, entry.ID, entries[0].ID)
+	}
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+const (
+	checkViolation = "check_violation"
+
+	balanceWithinOverdraftConstraint  = "balance_within_overdraft"
+	heldAmountWithinBalanceConstraint = "held_amount_within_balance"
+)
+
+// ErrInsufficientFunds is returned when a transaction would overdraw an account
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// ErrIdempotencyKeyMismatch is returned when an idempotency key is reused for a different request
+var ErrIdempotencyKeyMismatch = errors.New("idempotency key was already used for a different request")
+
+// ErrQuoteUsed is returned when an exchange quote has already been used for a transfer
+var ErrQuoteUsed = errors.New("exchange quote has already been used")
+
+// ErrQuoteExpired is returned when an exchange quote is used after it expired
+var ErrQuoteExpired = errors.New("exchange quote has expired")
+
+// ErrAmountTooSmall is returned when a converted amount rounds down to nothing
+var ErrAmountTooSmall = errors.New("amount is too small to convert")
+
+// isConstraintViolation reports whether err is a postgres error of the given kind raised by the named constraint
+func isConstraintViolation(err error, code string, constraint string) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	return pqErr.Code.Name() == code && pqErr.Constraint == constraint
+}
+
+// ErrRunAlreadyRecorded is returned when the outcome of a scheduled transfer's occurrence was already recorded
+var ErrRunAlreadyRecorded = errors.New("scheduled transfer run was already recorded")
+
+// ErrReversalExceedsTransfer is returned when a reversal would take back more than what is left of the original transfer
+var ErrReversalExceedsTransfer = errors.New("reversal exceeds the amount left to reverse")
+
+// ErrReverseReversal is returned when a transfer that is itself a reversal is reversed
+var ErrReverseReversal = errors.New("a reversal can't be reversed")
+
+// ErrHoldNotAuthorized is returned when a hold that was already captured, voided or expired is captured or released
+var ErrHoldNotAuthorized = errors.New("hold is no longer authorized")
+
+// ErrHoldExpired is returned when a hold is captured after it expired
+var ErrHoldExpired = errors.New("hold has expired")
+
+// ErrCaptureExceedsHold is returned when more is captured than the hold's amount
+var ErrCaptureExceedsHold = errors.New("capture exceeds the held amount")
+
+// ErrTransferLimitExceeded is returned when a transfer would go over a daily or monthly limit of the account or its owner
+var ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+
+// ErrApprovalRequired is returned when money that has to wait for one of the account's approvers would move without one
+var ErrApprovalRequired = errors.New("amount exceeds the account's approval threshold")
+
+// ErrTransferRequestNotPending is returned when a transfer request that was already approved or rejected is decided on again
+var ErrTransferRequestNotPending = errors.New("transfer request was already decided")
+
+// ErrAccountFrozen is returned when money would be moved into or out of a frozen account
+var ErrAccountFrozen = errors.New("account is frozen")
+
+// ErrAccountClosed is returned when money would be moved into or out of a closed account
+var ErrAccountClosed = errors.New("account is closed")
+
+// ErrAccountStatusTransition is returned when an account can't move from its current status to the requested one
+var ErrAccountStatusTransition = errors.New("account can't move to that status")
+
+// ErrAccountBalanceNotZero is returned when an account that still holds money is closed
+var ErrAccountBalanceNotZero = errors.New("account balance must be zero to close it")
+
+// ErrNoInterest is returned when interest is accrued on an account whose product pays none
+var ErrNoInterest = errors.New("account doesn't earn interest")
+
+// ErrInterestAlreadyCapitalized is returned when the interest of a month was already paid to an account
+var ErrInterestAlreadyCapitalized = errors.New("interest was already capitalized for the period")
+
+// ErrOverdraftInUse is returned when an overdraft limit is lowered below what the account already uses of it
+var ErrOverdraftInUse = errors.New("account uses more of its overdraft than the new limit")
+
+// ErrOverdraftAlreadyCharged is returned when an account's overdraft was already charged for a day
+var ErrOverdraftAlreadyCharged = errors.New("overdraft was already charged for the day")
+
+// ErrSystemAccount is returned when cash is deposited into or withdrawn from one of the bank's own accounts
+var ErrSystemAccount = errors.New("cash can't be deposited into or withdrawn from the bank's own accounts")
+
+// ErrProductAccountLimit is returned when a user opens more accounts of a product in a currency than the product allows
+var ErrProductAccountLimit = errors.New("too many open accounts of the product in the currency")
+
+// ErrCurrencyInUse is returned when the minor units of a currency that accounts are held in are changed
+var ErrCurrencyInUse = errors.New("minor units of a currency accounts are held in can't change")
+
+// ErrSnapshotTooEarly is returned when balances are snapshotted while a transaction that started before the snapshot time is still running
+var ErrSnapshotTooEarly = errors.New("transactions that started before the snapshot time are still running")
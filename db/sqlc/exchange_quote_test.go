@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomExchangeQuote(t *testing.T, username string, fromCurrency string, toCurrency string, duration time.Duration) ExchangeQuote {
+	arg := CreateExchangeQuoteParams{
+		ID: uuid.New(),
+		Username: username,
+		FromCurrency: fromCurrency,
+		ToCurrency: toCurrency,
+		Rate: "0.92",
+		ExpiresAt: time.Now().Add(duration),
+	}
+
+	quote, err := testQueries.CreateExchangeQuote(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, quote)
+
+	require.Equal(t, arg.ID, quote.ID)
+	require.Equal(t, arg.Username, quote.Username)
+	require.Equal(t, arg.FromCurrency, quote.FromCurrency)
+	require.Equal(t, arg.ToCurrency, quote.ToCurrency)
+	require.Equal(t, arg.Rate, quote.Rate)
+	require.False(t, quote.IsUsed)
+	require.WithinDuration(t, arg.ExpiresAt, quote.ExpiresAt, time.Second)
+	require.NotZero(t, quote.CreatedAt)
+	return quote
+}
+
+func TestCreateExchangeQuote(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomExchangeQuote(t, user.Username, util.USD, util.EUR, time.Minute)
+}
+
+func TestGetExchangeQuote(t *testing.T) {
+	user := createRandomUser(t)
+	quote1 := createRandomExchangeQuote(t, user.Username, util.USD, util.EUR, time.Minute)
+
+	quote2, err := testQueries.GetExchangeQuote(context.Background(), quote1.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, quote2)
+
+	require.Equal(t, quote1.ID, quote2.ID)
+	require.Equal(t, quote1.Username, quote2.Username)
+	require.Equal(t, quote1.Rate, quote2.Rate)
+	require.Equal(t, quote1.IsUsed, quote2.IsUsed)
+	require.WithinDuration(t, quote1.ExpiresAt, quote2.ExpiresAt, time.Second)
+}
+
+func TestMarkExchangeQuoteUsed(t *testing.T) {
+	user := createRandomUser(t)
+	quote1 := createRandomExchangeQuote(t, user.Username, util.USD, util.EUR, time.Minute)
+
+	quote2, err := testQueries.MarkExchangeQuoteUsed(context.Background(), quote1.ID)
+	require.NoError(t, err)
+	require.Equal(t, quote1.ID, quote2.ID)
+	require.True(t, quote2.IsUsed)
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func setExchangeRate(t *testing.T, fromCurrency string, toCurrency string, rate string) ExchangeRate {
+	arg := UpsertExchangeRateParams{
+		FromCurrency: fromCurrency,
+		ToCurrency: toCurrency,
+		Rate: rate,
+	}
+
+	exchangeRate, err := testQueries.UpsertExchangeRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, exchangeRate)
+
+	require.Equal(t, arg.FromCurrency, exchangeRate.FromCurrency)
+	require.Equal(t, arg.ToCurrency, exchangeRate.ToCurrency)
+	require.Equal(t, arg.Rate, exchangeRate.Rate)
+	require.NotZero(t, exchangeRate.UpdatedAt)
+	return exchangeRate
+}
+
+func TestUpsertExchangeRate(t *testing.T) {
+	rate1 := setExchangeRate(t, util.USD, util.CAD, "1.37")
+	rate2 := setExchangeRate(t, util.USD, util.CAD, "1.38")
+
+	require.Equal(t, rate1.FromCurrency, rate2.FromCurrency)
+	require.Equal(t, rate1.ToCurrency, rate2.ToCurrency)
+	require.False(t, rate2.UpdatedAt.Before(rate1.UpdatedAt))
+}
+
+func TestGetExchangeRate(t *testing.T) {
+	rate1 := setExchangeRate(t, util.CAD, util.USD, "0.73")
+
+	rate2, err := testQueries.GetExchangeRate(context.Background(), GetExchangeRateParams{
+		FromCurrency: util.CAD,
+		ToCurrency: util.USD,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, rate2)
+
+	require.Equal(t, rate1.FromCurrency, rate2.FromCurrency)
+	require.Equal(t, rate1.ToCurrency, rate2.ToCurrency)
+	require.Equal(t, rate1.Rate, rate2.Rate)
+	require.WithinDuration(t, rate1.UpdatedAt, rate2.UpdatedAt, time.Second)
+}
+
+func TestListExchangeRates(t *testing.T) {
+	setExchangeRate(t, util.EUR, util.CAD, "1.48")
+	setExchangeRate(t, util.CAD, util.EUR, "0.68")
+
+	rates, err := testQueries.ListExchangeRates(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(rates), 2)
+
+	for _, rate := range rates {
+		require.NotEmpty(t, rate)
+		require.NotEqual(t, rate.FromCurrency, rate.ToCurrency)
+	}
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomHold(t *testing.T, expiresAt time.Time) Hold {
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	arg := CreateHoldParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 100,
+		ExpiresAt: expiresAt,
+		InitiatedBy: account1.Owner,
+	}
+
+	hold, err := testQueries.CreateHold(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, hold)
+
+	require.Equal(t, arg.FromAccountID, hold.FromAccountID)
+	require.Equal(t, arg.ToAccountID, hold.ToAccountID)
+	require.Equal(t, arg.Amount, hold.Amount)
+	require.Equal(t, arg.InitiatedBy, hold.InitiatedBy)
+	require.Equal(t, HoldAuthorized, hold.Status)
+	require.Zero(t, hold.CapturedAmount)
+	require.False(t, hold.TransferID.Valid)
+	require.WithinDuration(t, arg.ExpiresAt, hold.ExpiresAt, time.Second)
+
+	require.NotZero(t, hold.ID)
+	require.NotZero(t, hold.CreatedAt)
+	return hold
+}
+
+func TestCreateHold(t *testing.T) {
+	createRandomHold(t, time.Now().Add(time.Hour))
+}
+
+func TestGetHold(t *testing.T) {
+	hold1 := createRandomHold(t, time.Now().Add(time.Hour))
+
+	hold2, err := testQueries.GetHold(context.Background(), hold1.ID)
+	require.NoError(t, err)
+	require.Equal(t, hold1.ID, hold2.ID)
+	require.Equal(t, hold1.Amount, hold2.Amount)
+	require.Equal(t, hold1.Status, hold2.Status)
+	require.WithinDuration(t, hold1.ExpiresAt, hold2.ExpiresAt, time.Second)
+}
+
+func TestListExpiredHolds(t *testing.T) {
+	expired := createRandomHold(t, time.Now().Add(-time.Minute))
+	pending := createRandomHold(t, time.Now().Add(time.Hour))
+
+	holds, err := testQueries.ListExpiredHolds(context.Background(), ListExpiredHoldsParams{
+		Now: time.Now(),
+		PageLimit: 1000,
+	})
+	require.NoError(t, err)
+
+	var ids []int64
+	for _, hold := range holds {
+		require.Equal(t, HoldAuthorized, hold.Status)
+		require.False(t, hold.ExpiresAt.After(time.Now()))
+		ids = append(ids, hold.ID)
+	}
+	require.Contains(t, ids, expired.ID)
+	require.NotContains(t, ids, pending.ID)
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomIdempotencyKey(t *testing.T, user User) IdempotencyKey {
+	arg := CreateIdempotencyKeyParams{
+		Username: user.Username,
+		Key: util.RandomString(16),
+		RequestHash: util.RandomString(64),
+	}
+
+	key, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, key)
+
+	require.Equal(t, arg.Username, key.Username)
+	require.Equal(t, arg.Key, key.Key)
+	require.Equal(t, arg.RequestHash, key.RequestHash)
+	require.JSONEq(t, "{}", string(key.Response))
+	require.NotZero(t, key.CreatedAt)
+	return key
+}
+
+func TestCreateIdempotencyKey(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomIdempotencyKey(t, user)
+}
+
+func TestCreateIdempotencyKeyConflict(t *testing.T) {
+	user := createRandomUser(t)
+	key1 := createRandomIdempotencyKey(t, user)
+
+	key2, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Username: key1.Username,
+		Key: key1.Key,
+		RequestHash: util.RandomString(64),
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+	require.Empty(t, key2)
+}
+
+func TestGetIdempotencyKey(t *testing.T) {
+	user := createRandomUser(t)
+	key1 := createRandomIdempotencyKey(t, user)
+
+	key2, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: key1.Username,
+		Key: key1.Key,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, key2)
+
+	require.Equal(t, key1.Username, key2.Username)
+	require.Equal(t, key1.Key, key2.Key)
+	require.Equal(t, key1.RequestHash, key2.RequestHash)
+	require.WithinDuration(t, key1.CreatedAt, key2.CreatedAt, time.Second)
+}
+
+func TestUpdateIdempotencyKeyResponse(t *testing.T) {
+	user := createRandomUser(t)
+	key1 := createRandomIdempotencyKey(t, user)
+
+	arg := UpdateIdempotencyKeyResponseParams{
+		Username: key1.Username,
+		Key: key1.Key,
+		Response: json.RawMessage(`{"transfer":{"id":1}}`),
+	}
+
+	key2, err := testQueries.UpdateIdempotencyKeyResponse(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, key2)
+
+	require.Equal(t, key1.Username, key2.Username)
+	require.Equal(t, key1.Key, key2.Key)
+	require.Equal(t, key1.RequestHash, key2.RequestHash)
+	require.JSONEq(t, string(arg.Response), string(key2.Response))
+}
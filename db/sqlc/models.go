@@ -5,17 +5,120 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type AccountApprover struct {
+	AccountID int64     `json:"accountID"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type AccountMember struct {
+	AccountID int64  `json:"accountID"`
+	Username  string `json:"username"`
+	// owner, co_owner, viewer or payer
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type AccountProduct struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// fraction of the balance earned over a year, 0.05 for 5%
+	AnnualInterestRate string `json:"annualInterestRate"`
+	// act/365, act/360 or 30/360
+	DayCountConvention string    `json:"dayCountConvention"`
+	CreatedAt          time.Time `json:"createdAt"`
+	// how many open accounts of the product a user may have in each currency
+	MaxAccounts int32 `json:"maxAccounts"`
+	// overdraft limit accounts of the product are opened with
+	OverdraftLimit int64 `json:"overdraftLimit"`
+	// overdraft interest accounts of the product are opened with
+	OverdraftAnnualRate string `json:"overdraftAnnualRate"`
+	// overdraft fee accounts of the product are opened with
+	OverdraftDailyFee int64 `json:"overdraftDailyFee"`
+}
+
+type AccountStatusChange struct {
+	ID         int64  `json:"id"`
+	AccountID  int64  `json:"accountID"`
+	FromStatus string `json:"fromStatus"`
+	ToStatus   string `json:"toStatus"`
+	// the user who changed the status
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 type Account struct {
-	ID        int64     `json:"id"`
-	Owner     string    `json:"owner"`
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	// must not go below the overdraft limit, except on system accounts
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"createdAt"`
+	// sum of the authorized holds on the account
+	HeldAmount int64 `json:"heldAmount"`
+	// balance that is not held
+	AvailableBalance int64 `json:"availableBalance"`
+	// transfers above it wait for an approver, none need approval when null
+	ApprovalThreshold sql.NullInt64 `json:"approvalThreshold"`
+	// active, frozen or closed
+	Status string `json:"status"`
+	// the product the account was opened as, its interest rate applies
+	Product sql.NullString `json:"product"`
+	// set on the accounts of the bank itself, which may go negative
+	SystemPurpose sql.NullString `json:"systemPurpose"`
+	// how far below zero the balance may go
+	OverdraftLimit int64 `json:"overdraftLimit"`
+	// interest charged on the overdrawn balance over a year, 0.2 for 20%
+	OverdraftAnnualRate string `json:"overdraftAnnualRate"`
+	// charged for every day the account ends overdrawn
+	OverdraftDailyFee int64 `json:"overdraftDailyFee"`
+	// public IBAN-style account number with mod-97 check digits, the bank's own accounts have none
+	Number sql.NullString `json:"number"`
+	// name the owner tells their accounts apart by, unique among their open accounts
+	Nickname sql.NullString `json:"nickname"`
+}
+
+type BalanceSnapshot struct {
+	AccountID int64     `json:"accountID"`
+	TakenAt   time.Time `json:"takenAt"`
+	// balance of the account after every entry made before taken_at
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CashMovement struct {
+	ID int64 `json:"id"`
+	// deposit or withdrawal
+	Kind      string `json:"kind"`
+	AccountID int64  `json:"accountID"`
+	Amount    int64  `json:"amount"`
+	// charged to the account and paid into the bank's fees account
+	Fee           int64         `json:"fee"`
+	Banker        string        `json:"banker"`
+	TransferID    int64         `json:"transferID"`
+	FeeTransferID sql.NullInt64 `json:"feeTransferID"`
+	CreatedAt     time.Time     `json:"createdAt"`
+}
+
+type Currency struct {
+	// ISO 4217 alphabetic code
+	Code string `json:"code"`
+	// ISO 4217 numeric code
+	NumericCode int32 `json:"numericCode"`
+	// decimals of the currency, amounts are stored in its minor unit
+	MinorUnits int32  `json:"minorUnits"`
+	Symbol     string `json:"symbol"`
+	// only enabled currencies can be used for new accounts, transfers and rates
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 type Entry struct {
@@ -24,6 +127,138 @@ type Entry struct {
 	// can be negative or positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"createdAt"`
+	// memo of the transfer that made the entry
+	Memo string `json:"memo"`
+	// reference of the transfer that made the entry
+	Reference string `json:"reference"`
+	// metadata of the transfer that made the entry
+	Metadata json.RawMessage `json:"metadata"`
+	// the transfer that made the entry
+	TransferID sql.NullInt64 `json:"transferID"`
+}
+
+type ExchangeQuote struct {
+	ID           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	FromCurrency string    `json:"fromCurrency"`
+	ToCurrency   string    `json:"toCurrency"`
+	Rate         string    `json:"rate"`
+	IsUsed       bool      `json:"isUsed"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type ExchangeRate struct {
+	FromCurrency string `json:"fromCurrency"`
+	ToCurrency   string `json:"toCurrency"`
+	// amount of to_currency one unit of from_currency buys
+	Rate      string    `json:"rate"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Hold struct {
+	ID            int64 `json:"id"`
+	FromAccountID int64 `json:"fromAccountID"`
+	ToAccountID   int64 `json:"toAccountID"`
+	// must be positive
+	Amount int64 `json:"amount"`
+	// authorized, captured, voided or expired
+	Status string `json:"status"`
+	// part of amount that was transferred on capture
+	CapturedAmount int64         `json:"capturedAmount"`
+	TransferID     sql.NullInt64 `json:"transferID"`
+	ExpiresAt      time.Time     `json:"expiresAt"`
+	CreatedAt      time.Time     `json:"createdAt"`
+	// user who authorized the hold, their transfer limits count its capture
+	InitiatedBy string `json:"initiatedBy"`
+}
+
+type IdempotencyKey struct {
+	Username    string `json:"username"`
+	Key         string `json:"key"`
+	RequestHash string `json:"requestHash"`
+	// serialized result of the first request made with the key
+	Response  json.RawMessage `json:"response"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+type InterestAccrual struct {
+	AccountID   int64     `json:"accountID"`
+	AccrualDate time.Time `json:"accrualDate"`
+	// balance at the end of the day
+	Balance            int64  `json:"balance"`
+	AnnualInterestRate string `json:"annualInterestRate"`
+	DayCountConvention string `json:"dayCountConvention"`
+	// interest earned over the day, in fractions of a minor unit
+	Amount    string    `json:"amount"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type InterestCapitalization struct {
+	AccountID int64 `json:"accountID"`
+	// first day of the month the interest is paid for
+	Period time.Time `json:"period"`
+	// whole minor units paid, the fractions left over are paid with the next month
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transferID"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+type Notification struct {
+	ID        int64         `json:"id"`
+	Username  string        `json:"username"`
+	AccountID sql.NullInt64 `json:"accountID"`
+	// overdraft_started or overdraft_charged
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type OverdraftCharge struct {
+	AccountID  int64     `json:"accountID"`
+	ChargeDate time.Time `json:"chargeDate"`
+	// balance at the end of the day
+	Balance    int64  `json:"balance"`
+	AnnualRate string `json:"annualRate"`
+	Interest   int64  `json:"interest"`
+	Fee        int64  `json:"fee"`
+	// part of the interest and fee not charged because it would go over the overdraft limit
+	Waived     int64         `json:"waived"`
+	TransferID sql.NullInt64 `json:"transferID"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64     `json:"id"`
+	ScheduledTransferID int64     `json:"scheduledTransferID"`
+	ScheduledFor        time.Time `json:"scheduledFor"`
+	// succeeded or failed
+	Status     string        `json:"status"`
+	TransferID sql.NullInt64 `json:"transferID"`
+	Error      string        `json:"error"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64  `json:"id"`
+	Owner         string `json:"owner"`
+	FromAccountID int64  `json:"fromAccountID"`
+	ToAccountID   int64  `json:"toAccountID"`
+	// must be positive
+	Amount int64 `json:"amount"`
+	// once, weekly, monthly or last_business_day
+	Recurrence string `json:"recurrence"`
+	// day a monthly transfer runs on, clamped to the end of shorter months
+	DayOfMonth int32 `json:"dayOfMonth"`
+	// active, completed, failed or cancelled
+	Status string `json:"status"`
+	// the occurrence that is due next
+	NextRunAt time.Time `json:"nextRunAt"`
+	// when the next occurrence is tried, later than next_run_at after a failed attempt
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+	// failed attempts of the next occurrence
+	Attempts  int32     `json:"attempts"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type Session struct {
@@ -37,13 +272,64 @@ type Session struct {
 	CreatedAt    time.Time `json:"createdAt"`
 }
 
-type Transfer struct {
+type TransferLimit struct {
+	ID int64 `json:"id"`
+	// set for a limit on all accounts of a user, per currency
+	Username sql.NullString `json:"username"`
+	// set for a limit on a single account
+	AccountID sql.NullInt64 `json:"accountID"`
+	// daily or monthly, a rolling window ending now
+	Period string `json:"period"`
+	// total outgoing amount allowed within the period
+	MaxAmount int64 `json:"maxAmount"`
+	// number of outgoing transfers allowed within the period
+	MaxCount  int32     `json:"maxCount"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type TransferRequest struct {
 	ID            int64 `json:"id"`
 	FromAccountID int64 `json:"fromAccountID"`
 	ToAccountID   int64 `json:"toAccountID"`
 	// must be positive
+	Amount      int64  `json:"amount"`
+	RequestedBy string `json:"requestedBy"`
+	// pending, approved or rejected
+	Status    string         `json:"status"`
+	DecidedBy sql.NullString `json:"decidedBy"`
+	// the transfer made on approval
+	TransferID sql.NullInt64 `json:"transferID"`
+	DecidedAt  sql.NullTime  `json:"decidedAt"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	Memo       string        `json:"memo"`
+	Reference  string        `json:"reference"`
+	// string values by key, given to the transfer on approval along with the memo and reference
+	Metadata json.RawMessage `json:"metadata"`
+}
+
+type Transfer struct {
+	ID            int64 `json:"id"`
+	FromAccountID int64 `json:"fromAccountID"`
+	ToAccountID   int64 `json:"toAccountID"`
+	// must be positive, in the from account currency
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"createdAt"`
+	// credited to the to account, in its currency
+	ToAmount int64 `json:"toAmount"`
+	// a reversal records the rate of the transfer it reverses
+	ExchangeRate string `json:"exchangeRate"`
+	// the transfer this one reverses
+	ReversalOf sql.NullInt64 `json:"reversalOf"`
+	// how much of amount was reversed so far
+	ReversedAmount int64 `json:"reversedAmount"`
+	// what the transfer is for, as the sender put it
+	Memo string `json:"memo"`
+	// identifier of the payment in another system, such as an invoice number
+	Reference string `json:"reference"`
+	// string values by key, copied to both entries
+	Metadata json.RawMessage `json:"metadata"`
+	// user who made the transfer, their transfer limits count it, null when the bank made it
+	InitiatedBy sql.NullString `json:"initiatedBy"`
 }
 
 type User struct {
@@ -53,4 +339,6 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"passwordChangedAt"`
 	CreatedAt         time.Time `json:"createdAt"`
+	// depositor or banker
+	Role string `json:"role"`
 }
@@ -6,28 +6,131 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	AddAccountHeldAmount(ctx context.Context, arg AddAccountHeldAmountParams) (Account, error)
+	AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfer, error)
+	BlockUserSessions(ctx context.Context, username string) (int64, error)
+	CancelScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	CaptureHold(ctx context.Context, arg CaptureHoldParams) (Hold, error)
+	ClaimDueScheduledTransfer(ctx context.Context, arg ClaimDueScheduledTransferParams) (ScheduledTransfer, error)
+	CountAccounts(ctx context.Context) (int64, error)
+	CountCurrencyAccounts(ctx context.Context, currency string) (int64, error)
+	CountOpenProductAccounts(ctx context.Context, arg CountOpenProductAccountsParams) (int64, error)
+	CountTransfers(ctx context.Context) (int64, error)
+	CountWritersStartedBefore(ctx context.Context, startedBefore time.Time) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountApprover(ctx context.Context, arg CreateAccountApproverParams) (AccountApprover, error)
+	CreateAccountStatusChange(ctx context.Context, arg CreateAccountStatusChangeParams) (AccountStatusChange, error)
+	CreateBalanceSnapshots(ctx context.Context, takenAt time.Time) (int64, error)
+	CreateCashMovement(ctx context.Context, arg CreateCashMovementParams) (CashMovement, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateExchangeQuote(ctx context.Context, arg CreateExchangeQuoteParams) (ExchangeQuote, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccrual, error)
+	CreateInterestCapitalization(ctx context.Context, arg CreateInterestCapitalizationParams) (InterestCapitalization, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreateOverdraftCharge(ctx context.Context, arg CreateOverdraftChargeParams) (OverdraftCharge, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateSystemAccount(ctx context.Context, arg CreateSystemAccountParams) (Account, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferRequest(ctx context.Context, arg CreateTransferRequestParams) (TransferRequest, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteAccount(ctx context.Context, id int64) error
+	DecideTransferRequest(ctx context.Context, arg DecideTransferRequestParams) (TransferRequest, error)
+	DeleteAccountApprover(ctx context.Context, arg DeleteAccountApproverParams) error
+	DeleteAccountMember(ctx context.Context, arg DeleteAccountMemberParams) (int64, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountApprover(ctx context.Context, arg GetAccountApproverParams) (AccountApprover, error)
+	GetAccountByNumber(ctx context.Context, number string) (Account, error)
+	GetAccountEntriesTotalBetween(ctx context.Context, arg GetAccountEntriesTotalBetweenParams) (int64, error)
+	GetAccountEntriesTotalSince(ctx context.Context, arg GetAccountEntriesTotalSinceParams) (int64, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountMember(ctx context.Context, arg GetAccountMemberParams) (AccountMember, error)
+	GetAccountOutgoingTotal(ctx context.Context, arg GetAccountOutgoingTotalParams) (GetAccountOutgoingTotalRow, error)
+	GetAccountProduct(ctx context.Context, code string) (AccountProduct, error)
+	GetAccruedInterestBefore(ctx context.Context, arg GetAccruedInterestBeforeParams) (int64, error)
+	GetCapitalizedInterest(ctx context.Context, accountID int64) (int64, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
+	GetCurrencyForUpdate(ctx context.Context, code string) (Currency, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetExchangeQuote(ctx context.Context, id uuid.UUID) (ExchangeQuote, error)
+	GetExchangeQuoteForUpdate(ctx context.Context, id uuid.UUID) (ExchangeQuote, error)
+	GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (ExchangeRate, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetInterestAccrual(ctx context.Context, arg GetInterestAccrualParams) (InterestAccrual, error)
+	GetLatestBalanceSnapshot(ctx context.Context, arg GetLatestBalanceSnapshotParams) (BalanceSnapshot, error)
+	GetLatestInterestAccrual(ctx context.Context, accountID int64) (InterestAccrual, error)
+	GetLatestOverdraftCharge(ctx context.Context, accountID int64) (OverdraftCharge, error)
+	GetNextBalanceSnapshot(ctx context.Context, arg GetNextBalanceSnapshotParams) (BalanceSnapshot, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (Account, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	GetTransferRequest(ctx context.Context, id int64) (TransferRequest, error)
+	GetTransferRequestForUpdate(ctx context.Context, id int64) (TransferRequest, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserForUpdate(ctx context.Context, username string) (User, error)
+	GetUserOutgoingTotal(ctx context.Context, arg GetUserOutgoingTotalParams) (GetUserOutgoingTotalRow, error)
+	ListAccountApprovers(ctx context.Context, accountID int64) ([]AccountApprover, error)
+	ListAccountBalanceMismatches(ctx context.Context) ([]ListAccountBalanceMismatchesRow, error)
+	ListAccountEntriesBetween(ctx context.Context, arg ListAccountEntriesBetweenParams) ([]Entry, error)
+	ListAccountHeldAmountMismatches(ctx context.Context) ([]ListAccountHeldAmountMismatchesRow, error)
+	ListAccountMembers(ctx context.Context, accountID int64) ([]AccountMember, error)
+	ListAccountProducts(ctx context.Context) ([]AccountProduct, error)
+	ListAccountStatusChanges(ctx context.Context, accountID int64) ([]AccountStatusChange, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListExchangeRates(ctx context.Context) ([]ExchangeRate, error)
+	ListExpiredHolds(ctx context.Context, arg ListExpiredHoldsParams) ([]Hold, error)
+	ListInterestAccruals(ctx context.Context, arg ListInterestAccrualsParams) ([]InterestAccrual, error)
+	ListInterestBearingAccounts(ctx context.Context, arg ListInterestBearingAccountsParams) ([]Account, error)
+	ListLedgerImbalances(ctx context.Context) ([]ListLedgerImbalancesRow, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListOrphanEntries(ctx context.Context) ([]Entry, error)
+	ListOverdraftAccounts(ctx context.Context, arg ListOverdraftAccountsParams) ([]Account, error)
+	ListOverdraftCharges(ctx context.Context, arg ListOverdraftChargesParams) ([]OverdraftCharge, error)
+	ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error)
+	ListScheduledTransfers(ctx context.Context, arg ListScheduledTransfersParams) ([]ScheduledTransfer, error)
+	ListTransferEntryMismatches(ctx context.Context) ([]ListTransferEntryMismatchesRow, error)
+	ListTransferLimits(ctx context.Context, arg ListTransferLimitsParams) ([]TransferLimit, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	ListUnnumberedAccounts(ctx context.Context, limit int32) ([]Account, error)
+	ListUserTransferRequests(ctx context.Context, arg ListUserTransferRequestsParams) ([]TransferRequest, error)
+	ListUserTransfers(ctx context.Context, arg ListUserTransfersParams) ([]ListUserTransfersRow, error)
+	MarkExchangeQuoteUsed(ctx context.Context, id uuid.UUID) (ExchangeQuote, error)
+	SetAccountNumber(ctx context.Context, arg SetAccountNumberParams) (int64, error)
+	SetAccountTransferLimit(ctx context.Context, arg SetAccountTransferLimitParams) (TransferLimit, error)
+	SetUserTransferLimit(ctx context.Context, arg SetUserTransferLimitParams) (TransferLimit, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountApprovalThreshold(ctx context.Context, arg UpdateAccountApprovalThresholdParams) (Account, error)
+	UpdateAccountNickname(ctx context.Context, arg UpdateAccountNicknameParams) (Account, error)
+	UpdateAccountOverdraft(ctx context.Context, arg UpdateAccountOverdraftParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateHoldStatus(ctx context.Context, arg UpdateHoldStatusParams) (Hold, error)
+	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) (IdempotencyKey, error)
+	UpdateInterestCapitalization(ctx context.Context, arg UpdateInterestCapitalizationParams) (InterestCapitalization, error)
+	UpdateOverdraftChargeTransfer(ctx context.Context, arg UpdateOverdraftChargeTransferParams) (OverdraftCharge, error)
+	UpdateScheduledTransferAmount(ctx context.Context, arg UpdateScheduledTransferAmountParams) (ScheduledTransfer, error)
+	UpdateScheduledTransferSchedule(ctx context.Context, arg UpdateScheduledTransferScheduleParams) (ScheduledTransfer, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpsertAccountMember(ctx context.Context, arg UpsertAccountMemberParams) (AccountMember, error)
+	UpsertAccountProduct(ctx context.Context, arg UpsertAccountProductParams) (AccountProduct, error)
+	UpsertCurrency(ctx context.Context, arg UpsertCurrencyParams) (Currency, error)
+	UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRate, error)
 }
 
 var _ Querier = (*Queries)(nil)
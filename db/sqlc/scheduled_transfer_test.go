@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomScheduledTransfer(t *testing.T, nextRunAt time.Time) ScheduledTransfer {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	arg := CreateScheduledTransferParams{
+		Owner: account1.Owner,
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: util.RandomMoney() + 1,
+		Recurrence: util.RecurrenceMonthly,
+		DayOfMonth: int32(nextRunAt.Day()),
+		NextRunAt: nextRunAt,
+	}
+
+	scheduled, err := testQueries.CreateScheduledTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, scheduled)
+
+	require.Equal(t, arg.Owner, scheduled.Owner)
+	require.Equal(t, arg.FromAccountID, scheduled.FromAccountID)
+	require.Equal(t, arg.ToAccountID, scheduled.ToAccountID)
+	require.Equal(t, arg.Amount, scheduled.Amount)
+	require.Equal(t, arg.Recurrence, scheduled.Recurrence)
+	require.Equal(t, arg.DayOfMonth, scheduled.DayOfMonth)
+	require.Equal(t, ScheduledTransferActive, scheduled.Status)
+	require.WithinDuration(t, arg.NextRunAt, scheduled.NextRunAt, time.Second)
+	require.WithinDuration(t, arg.NextRunAt, scheduled.NextAttemptAt, time.Second)
+	require.Zero(t, scheduled.Attempts)
+	require.NotZero(t, scheduled.CreatedAt)
+	return scheduled
+}
+
+func TestCreateScheduledTransfer(t *testing.T) {
+	createRandomScheduledTransfer(t, time.Now().Add(time.Hour))
+}
+
+func TestGetScheduledTransfer(t *testing.T) {
+	scheduled1 := createRandomScheduledTransfer(t, time.Now().Add(time.Hour))
+
+	scheduled2, err := testQueries.GetScheduledTransfer(context.Background(), scheduled1.ID)
+	require.NoError(t, err)
+	require.Equal(t, scheduled1, scheduled2)
+}
+
+func TestClaimDueScheduledTransfer(t *testing.T) {
+	createRandomScheduledTransfer(t, time.Now().Add(-time.Minute))
+
+	now := time.Now()
+	arg := ClaimDueScheduledTransferParams{
+		LeaseUntil: now.Add(time.Minute),
+		Now: now,
+	}
+
+	scheduled, err := testQueries.ClaimDueScheduledTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferActive, scheduled.Status)
+	require.False(t, scheduled.NextRunAt.After(now))
+	require.WithinDuration(t, arg.LeaseUntil, scheduled.NextAttemptAt, time.Second)
+}
+
+func TestListScheduledTransfers(t *testing.T) {
+	var lastScheduled ScheduledTransfer
+	for i := 0; i < 3; i++ {
+		lastScheduled = createRandomScheduledTransfer(t, time.Now().Add(time.Hour))
+	}
+
+	arg := ListScheduledTransfersParams{
+		Owner: lastScheduled.Owner,
+		Limit: 5,
+		Offset: 0,
+	}
+
+	scheduledTransfers, err := testQueries.ListScheduledTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, scheduledTransfers)
+
+	for _, scheduled := range scheduledTransfers {
+		require.Equal(t, lastScheduled.Owner, scheduled.Owner)
+	}
+}
+
+func TestUpdateScheduledTransferAmount(t *testing.T) {
+	scheduled1 := createRandomScheduledTransfer(t, time.Now().Add(time.Hour))
+
+	arg := UpdateScheduledTransferAmountParams{
+		ID: scheduled1.ID,
+		Amount: scheduled1.Amount + 1,
+	}
+
+	scheduled2, err := testQueries.UpdateScheduledTransferAmount(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Amount, scheduled2.Amount)
+}
+
+func TestCancelScheduledTransfer(t *testing.T) {
+	scheduled1 := createRandomScheduledTransfer(t, time.Now().Add(time.Hour))
+
+	scheduled2, err := testQueries.CancelScheduledTransfer(context.Background(), scheduled1.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferCancelled, scheduled2.Status)
+}
@@ -3,13 +3,42 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"time"
 )
 
 // Store provides all functions to execute db queries and transactions
 type Store interface {
 	Querier
+	CreateAccountTx(ctx context.Context, arg CreateAccountParams) (Account, error)
+	AssignAccountNumbers(ctx context.Context, arg AssignAccountNumbersParams) (int64, error)
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	ExchangeTransferTx(ctx context.Context, arg ExchangeTransferTxParams) (TransferTxResult, error)
+	BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	RecordScheduledTransferRunTx(ctx context.Context, arg RecordScheduledTransferRunTxParams) (RecordScheduledTransferRunTxResult, error)
+	AuthorizeHoldTx(ctx context.Context, arg AuthorizeHoldTxParams) (HoldTxResult, error)
+	CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error)
+	ReleaseHoldTx(ctx context.Context, arg ReleaseHoldTxParams) (HoldTxResult, error)
+	TransferAllowancesTx(ctx context.Context, arg TransferAllowancesTxParams) ([]TransferAllowance, error)
+	RequestTransferTx(ctx context.Context, arg RequestTransferTxParams) (TransferRequest, error)
+	ApproveTransferRequestTx(ctx context.Context, arg ApproveTransferRequestTxParams) (ApproveTransferRequestTxResult, error)
+	ChangeAccountStatusTx(ctx context.Context, arg ChangeAccountStatusTxParams) (ChangeAccountStatusTxResult, error)
+	StatementTx(ctx context.Context, arg StatementTxParams) (Statement, error)
+	BalanceAtTx(ctx context.Context, arg BalanceAtTxParams) (BalanceAt, error)
+	CreateBalanceSnapshotsTx(ctx context.Context, takenAt time.Time) (int64, error)
+	ReconcileTx(ctx context.Context) (ReconciliationReport, error)
+	AccrueInterestTx(ctx context.Context, arg AccrueInterestTxParams) (InterestAccrual, error)
+	SetOverdraftTx(ctx context.Context, arg SetOverdraftTxParams) (Account, error)
+	ChargeOverdraftTx(ctx context.Context, arg ChargeOverdraftTxParams) (ChargeOverdraftTxResult, error)
+	CapitalizeInterestTx(ctx context.Context, arg CapitalizeInterestTxParams) (CapitalizeInterestTxResult, error)
+	DepositTx(ctx context.Context, arg CashTxParams) (CashTxResult, error)
+	WithdrawTx(ctx context.Context, arg CashTxParams) (CashTxResult, error)
+	ChangePasswordTx(ctx context.Context, arg ChangePasswordTxParams) (ChangePasswordTxResult, error)
+	UpsertCurrencyTx(ctx context.Context, arg UpsertCurrencyParams) (Currency, error)
+	UpsertExchangeRatesTx(ctx context.Context, arg UpsertExchangeRatesTxParams) ([]ExchangeRate, error)
 }
 
 // SQLStore provides all functions to execute SQL queries and transactions
@@ -26,9 +55,35 @@ func NewStore(db *sql.DB) Store {
 	}
 }
 
-// execTx executes a function within a database transaction
+// execTx executes a function within a database transaction at the default isolation level
 func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, nil)
+	return store.execTxWithOptions(ctx, nil, fn)
+}
+
+// execTxWithOptions executes a function within a database transaction started with the given options
+// A transaction that fails with a deadlock or a serialization failure is run again, with a growing delay between attempts,
+// until it succeeds, it ran maxTxAttempts times or ctx would be done before the next attempt
+// fn may therefore run more than once and must not depend on anything a previous run left behind
+func (store *SQLStore) execTxWithOptions(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	for attempt := 1; ; attempt++ {
+		err := store.runTx(ctx, opts, fn)
+
+		condition, retryable := retryableCondition(err)
+		if !retryable {
+			return err
+		}
+
+		if attempt == maxTxAttempts || !waitForRetry(ctx, attempt) {
+			txRetriesExhausted.Add(1)
+			return err
+		}
+		txRetries.Add(condition, 1)
+	}
+}
+
+// runTx runs a function within a single database transaction, committing it if the function succeeds
+func (store *SQLStore) runTx(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, opts)
 	if err != nil {
 		return err
 	}
@@ -37,7 +92,7 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	err = fn(q)
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("tx er: %v, rb err: %v", err, rbErr)
+			return fmt.Errorf("tx er: %w, rb err: %v", err, rbErr)
 		}
 		return err
 	}
@@ -45,11 +100,51 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	return tx.Commit()
 }
 
+// sameCurrencyRate is the exchange rate recorded on transfers between accounts of the same currency
+const sameCurrencyRate = "1"
+
 // TransferTxParams contains the input parameters of the transfer transaction
 type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID int64 `json:"to_account_id"`
 	Amount int64     `json:"amount"`
+	TransferDetails
+	// InitiatedBy is the user making the transfer, its user limits are theirs
+	InitiatedBy string `json:"initiated_by"`
+	// IdempotencyKey makes retries of the same request replay the original result when set
+	IdempotencyKey *IdempotencyKeyParams `json:"-"`
+	// DefaultLimits are the bank-wide transfer limits by period, for users and accounts without limits of their own
+	DefaultLimits map[string]DefaultTransferLimit `json:"-"`
+	// CheckApprovalThreshold makes the transfer fail with ErrApprovalRequired if it is above the from account's approval threshold,
+	// for transfers that were allowed through without approval when they were set up
+	CheckApprovalThreshold bool `json:"-"`
+}
+
+// TransferDetails describe what a transfer is for, they are recorded on the transfer and on both its entries
+type TransferDetails struct {
+	Memo string `json:"memo"`
+	Reference string `json:"reference"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// metadataJSON encodes the metadata for the metadata columns, an empty object when there is none
+func (details TransferDetails) metadataJSON() json.RawMessage {
+	if len(details.Metadata) == 0 {
+		return emptyMetadata
+	}
+
+	data, _ := json.Marshal(details.Metadata)
+	return data
+}
+
+// emptyMetadata is the metadata of transfers and entries made without any
+var emptyMetadata = json.RawMessage("{}")
+
+// IdempotencyKeyParams identifies a client supplied idempotency key and the request it is used for
+type IdempotencyKeyParams struct {
+	Username string
+	Key string
+	RequestHash string
 }
 
 // TransferTxResult is the result of the transfer transaction
@@ -63,47 +158,44 @@ type TransferTxResult struct {
 
 // TransferTx performs a money transfer from one account to another
 // It creates a transfer record, adds entries and updates accounts balance within a database transaction
+// It returns ErrInsufficientFunds if the from account's available balance and overdraft don't cover the amount,
+// an error wrapping ErrTransferLimitExceeded if the transfer would go over a limit of the from account or its owner,
+// ErrApprovalRequired if the approval threshold is checked and the transfer is above it,
+// and an error wrapping ErrAccountFrozen or ErrAccountClosed if either account isn't active
+// If an idempotency key is given and was already used, the original result is returned without moving any money
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
 	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
-
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID: arg.ToAccountID,
-			Amount: arg.Amount,
-		})
-
-		if err != nil {
-			return err
+		if arg.IdempotencyKey != nil {
+			replayed, err := claimIdempotencyKey(ctx, q, *arg.IdempotencyKey, &result)
+			if err != nil || replayed {
+				return err
+			}
 		}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount: -arg.Amount,
-		})
-
+		err := lockAndCheckLimits(ctx, q, arg)
 		if err != nil {
 			return err
 		}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
+		result, err = transferMoney(ctx, q, CreateTransferParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID: arg.ToAccountID,
 			Amount: arg.Amount,
+			ToAmount: arg.Amount,
+			ExchangeRate: sameCurrencyRate,
+			Memo: arg.Memo,
+			Reference: arg.Reference,
+			Metadata: arg.metadataJSON(),
+			InitiatedBy: initiatedBy(arg.InitiatedBy),
 		})
-
 		if err != nil {
 			return err
 		}
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
-		}
 
-		if err != nil {
-			return err
+		if arg.IdempotencyKey != nil {
+			return saveIdempotencyKeyResponse(ctx, q, *arg.IdempotencyKey, result)
 		}
 
 		return nil
@@ -112,6 +204,85 @@ func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (Tr
 	return result, err
 }
 
+// initiatedBy records the user who made a transfer, none when the bank made it
+func initiatedBy(username string) sql.NullString {
+	return sql.NullString{String: username, Valid: username != ""}
+}
+
+// transferMoney moves money between two accounts within the caller's transaction
+// The from account is debited arg.Amount and the to account is credited arg.ToAmount
+// Both entries get the memo, reference and metadata of the transfer
+// Both accounts must be active, money doesn't move into or out of frozen and closed accounts
+// The bank's own system accounts may go negative, every other from account needs the amount within its funds
+// The owner of a from account that goes into its overdraft is notified
+func transferMoney(ctx context.Context, q *Queries, arg CreateTransferParams) (result TransferTxResult, err error) {
+	fromAccount, toAccount, err := lockAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+	if err != nil {
+		return
+	}
+
+	err = checkAccountsActive(fromAccount, toAccount)
+	if err != nil {
+		return
+	}
+
+	if arg.Metadata == nil {
+		arg.Metadata = emptyMetadata
+	}
+
+	if !fromAccount.SystemPurpose.Valid && availableFunds(fromAccount) < arg.Amount {
+		err = ErrInsufficientFunds
+		return
+	}
+
+	result.Transfer, err = q.CreateTransfer(ctx, arg)
+	if err != nil {
+		return
+	}
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.FromAccountID,
+		Amount: -arg.Amount,
+		Memo: arg.Memo,
+		Reference: arg.Reference,
+		Metadata: arg.Metadata,
+		TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.ToAccountID,
+		Amount: arg.ToAmount,
+		Memo: arg.Memo,
+		Reference: arg.Reference,
+		Metadata: arg.Metadata,
+		TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return
+	}
+
+	if arg.FromAccountID < arg.ToAccountID {
+		result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.ToAmount)
+	} else {
+		result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.ToAmount, arg.FromAccountID, -arg.Amount)
+	}
+
+	if isConstraintViolation(err, checkViolation, balanceWithinOverdraftConstraint) || isConstraintViolation(err, checkViolation, heldAmountWithinBalanceConstraint) {
+		err = ErrInsufficientFunds
+	}
+	if err != nil {
+		return
+	}
+
+	if fromAccount.Balance >= 0 && result.FromAccount.Balance < 0 && !fromAccount.SystemPurpose.Valid {
+		err = notifyOverdraftStarted(ctx, q, result.FromAccount)
+	}
+	return
+}
+
 func addMoney(ctx context.Context, q *Queries, accountID1 int64, amount1 int64, accountID2 int64, amount2 int64) (account1 Account, account2 Account, err error) {
 	account1, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
 		ID: accountID1,
@@ -126,4 +297,102 @@ func addMoney(ctx context.Context, q *Queries, accountID1 int64, amount1 int64,
 		Amount: amount2,
 	})
 	return
+}
+
+// lockAndCheckLimits locks the user making a transfer and both its accounts, then checks the transfer limits
+// and, if asked to, the from account's approval threshold
+func lockAndCheckLimits(ctx context.Context, q *Queries, arg TransferTxParams) error {
+	err := lockUsers(ctx, q, arg.InitiatedBy)
+	if err != nil {
+		return err
+	}
+
+	fromAccount, _, err := lockAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+	if err != nil {
+		return err
+	}
+
+	if arg.CheckApprovalThreshold && NeedsApproval(fromAccount, arg.Amount) {
+		return ErrApprovalRequired
+	}
+
+	return checkTransferLimits(ctx, q, fromAccount, arg.InitiatedBy, arg.Amount, arg.DefaultLimits)
+}
+
+// lockAccounts locks both accounts of a transfer for update and returns them
+func lockAccounts(ctx context.Context, q *Queries, fromAccountID int64, toAccountID int64) (fromAccount Account, toAccount Account, err error) {
+	accounts, err := lockAccountIDs(ctx, q, fromAccountID, toAccountID)
+	if err != nil {
+		return
+	}
+
+	return accounts[fromAccountID], accounts[toAccountID], nil
+}
+
+// lockAccountIDs locks the given accounts for update and returns them by ID
+// The rows are always locked in ascending ID order, the same order addMoney updates them in, to avoid deadlocks
+func lockAccountIDs(ctx context.Context, q *Queries, accountIDs ...int64) (map[int64]Account, error) {
+	ids := make([]int64, len(accountIDs))
+	copy(ids, accountIDs)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	accounts := make(map[int64]Account, len(ids))
+	for _, id := range ids {
+		if _, ok := accounts[id]; ok {
+			continue
+		}
+
+		account, err := q.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		accounts[id] = account
+	}
+
+	return accounts, nil
+}
+
+// claimIdempotencyKey records the key for the current request, or loads the result of the request that already used it
+// A concurrent request with the same key blocks on the key's primary key until the first one commits or rolls back
+func claimIdempotencyKey(ctx context.Context, q *Queries, arg IdempotencyKeyParams, result interface{}) (replayed bool, err error) {
+	_, err = q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+		Username: arg.Username,
+		Key: arg.Key,
+		RequestHash: arg.RequestHash,
+	})
+	if err == nil {
+		return false, nil
+	}
+	if err != sql.ErrNoRows {
+		return false, err
+	}
+
+	key, err := q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		Username: arg.Username,
+		Key: arg.Key,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if key.RequestHash != arg.RequestHash {
+		return false, ErrIdempotencyKeyMismatch
+	}
+
+	return true, json.Unmarshal(key.Response, result)
+}
+
+// saveIdempotencyKeyResponse stores the result of the request that claimed the key so retries can replay it
+func saveIdempotencyKeyResponse(ctx context.Context, q *Queries, arg IdempotencyKeyParams, result interface{}) error {
+	response, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.UpdateIdempotencyKeyResponse(ctx, UpdateIdempotencyKeyResponseParams{
+		Username: arg.Username,
+		Key: arg.Key,
+		Response: response,
+	})
+	return err
 }
\ No newline at end of file
@@ -2,16 +2,18 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
 
+	"github.com/nokkvi/simplebank/util"
 	"github.com/stretchr/testify/require"
 )
 
 func TestTransferTx(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := createRandomAccount(t)
+	account1 := createRandomAccountWithBalance(t, util.RandomInt(100, 1000))
 	account2 := createRandomAccount(t)
 	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
@@ -115,8 +117,8 @@ func TestTransferTx(t *testing.T) {
 func TestTransferTxDeadlock(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account1 := createRandomAccountWithBalance(t, util.RandomInt(100, 1000))
+	account2 := createRandomAccountWithBalance(t, util.RandomInt(100, 1000))
 	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
 	// run n concurrent transfer transactions
@@ -161,4 +163,182 @@ func TestTransferTxDeadlock(t *testing.T) {
 	fmt.Println(">> after:", updatedAccount1.Balance, updatedAccount2.Balance)
 	require.Equal(t, account1.Balance, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
-}
\ No newline at end of file
+}
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 50)
+	account2 := createRandomAccount(t)
+
+	// run n concurrent transfer transactions, only the first five can be covered
+	n := 10
+	amount := int64(10)
+
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID: account2.ID,
+				Amount: amount,
+			})
+
+			errs <- err
+		}()
+	}
+
+	// check results
+	failed := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err != nil {
+			require.ErrorIs(t, err, ErrInsufficientFunds)
+			failed++
+		}
+	}
+	require.Equal(t, 5, failed)
+
+	// check the final updated balances
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+
+	require.Zero(t, updatedAccount1.Balance)
+	require.Equal(t, account2.Balance + account1.Balance, updatedAccount2.Balance)
+}
+
+func TestTransferTxApprovalThreshold(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	_, err := testQueries.UpdateAccountApprovalThreshold(context.Background(), UpdateAccountApprovalThresholdParams{
+		ID: account1.ID,
+		ApprovalThreshold: sql.NullInt64{Int64: 500, Valid: true},
+	})
+	require.NoError(t, err)
+
+	arg := TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 501,
+		CheckApprovalThreshold: true,
+	}
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrApprovalRequired)
+
+	arg.Amount = 500
+	result, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(500), result.Transfer.Amount)
+}
+
+func TestTransferTxIdempotent(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, util.RandomInt(100, 1000))
+	account2 := createRandomAccount(t)
+
+	// run n concurrent retries of the same transfer
+	n := 5
+	amount := int64(10)
+	key := IdempotencyKeyParams{
+		Username: account1.Owner,
+		Key: util.RandomString(16),
+		RequestHash: util.RandomString(64),
+	}
+
+	errs := make(chan error)
+	results := make(chan TransferTxResult)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID: account2.ID,
+				Amount: amount,
+				IdempotencyKey: &key,
+			})
+
+			errs <- err
+			results <- result
+		}()
+	}
+
+	// every retry gets the result of the single transfer that was made
+	var transferID int64
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+
+		result := <-results
+		require.NotZero(t, result.Transfer.ID)
+		if transferID == 0 {
+			transferID = result.Transfer.ID
+		}
+		require.Equal(t, transferID, result.Transfer.ID)
+	}
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance - amount, updatedAccount1.Balance)
+
+	// reusing the key for a different request is rejected
+	key.RequestHash = util.RandomString(64)
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 2 * amount,
+		IdempotencyKey: &key,
+	})
+	require.ErrorIs(t, err, ErrIdempotencyKeyMismatch)
+}
+
+func TestTransferTxDetails(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	details := TransferDetails{
+		Memo: "rent for " + util.RandomString(6),
+		Reference: util.RandomString(10),
+		Metadata: map[string]string{"invoice": util.RandomString(8)},
+	}
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 10,
+		TransferDetails: details,
+	})
+	require.NoError(t, err)
+
+	// the transfer and both entries carry the details
+	metadata := `{"invoice":"` + details.Metadata["invoice"] + `"}`
+	require.Equal(t, details.Memo, result.Transfer.Memo)
+	require.Equal(t, details.Reference, result.Transfer.Reference)
+	require.JSONEq(t, metadata, string(result.Transfer.Metadata))
+
+	for _, entry := range []Entry{result.FromEntry, result.ToEntry} {
+		require.Equal(t, details.Memo, entry.Memo)
+		require.Equal(t, details.Reference, entry.Reference)
+		require.JSONEq(t, metadata, string(entry.Metadata))
+		require.Equal(t, result.Transfer.ID, entry.TransferID.Int64)
+	}
+
+	// a transfer without any has empty details
+	result, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, result.Transfer.Memo)
+	require.JSONEq(t, `{}`, string(result.FromEntry.Metadata))
+}
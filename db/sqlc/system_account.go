@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SystemUsername is the user that owns the bank's own accounts, nobody can log in as it
+const SystemUsername = "simplebank"
+
+// Constants for the purposes of the bank's own accounts, there is one account per purpose and currency
+const (
+	// SystemInterest is the purpose of the accounts interest is paid from
+	SystemInterest = "interest"
+	// SystemOverdraft is the purpose of the accounts overdraft interest and fees are paid into
+	SystemOverdraft = "overdraft"
+	// SystemCash is the purpose of the accounts that stand for the cash deposited and withdrawn at the bank
+	SystemCash = "cash"
+	// SystemSuspense is the purpose of the accounts that balance money whose origin isn't recorded, such as opening balances
+	SystemSuspense = "suspense"
+	// SystemFees is the purpose of the accounts the fees on deposits and withdrawals are paid into
+	SystemFees = "fees"
+)
+
+// systemAccount returns the bank's account for the purpose and currency, opening it the first time it is needed
+func systemAccount(ctx context.Context, q *Queries, purpose string, currency string) (Account, error) {
+	arg := GetSystemAccountParams{
+		SystemPurpose: purpose,
+		Currency: currency,
+	}
+
+	account, err := q.GetSystemAccount(ctx, arg)
+	if err != sql.ErrNoRows {
+		return account, err
+	}
+
+	// a concurrent transaction may open it first, then it is read back once that one commits
+	account, err = q.CreateSystemAccount(ctx, CreateSystemAccountParams{
+		Owner: SystemUsername,
+		Currency: currency,
+		SystemPurpose: sql.NullString{String: purpose, Valid: true},
+	})
+	if err == sql.ErrNoRows {
+		return q.GetSystemAccount(ctx, arg)
+	}
+	return account, err
+}
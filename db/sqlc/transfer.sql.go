@@ -7,25 +7,79 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
 )
 
+const addTransferReversedAmount = `-- name: AddTransferReversedAmount :one
+UPDATE transfers
+SET reversed_amount = reversed_amount + $1
+WHERE id = $2
+RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount, memo, reference, metadata, initiated_by
+`
+
+type AddTransferReversedAmountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, addTransferReversedAmount, arg.Amount, arg.ID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+		&i.Memo,
+		&i.Reference,
+		&i.Metadata,
+		&i.InitiatedBy,
+	)
+	return i, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
-  from_account_id, to_account_id, amount
+  from_account_id, to_account_id, amount, to_amount, exchange_rate, reversal_of, memo, reference, metadata, initiated_by
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 )
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount, memo, reference, metadata, initiated_by
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"fromAccountID"`
-	ToAccountID   int64 `json:"toAccountID"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64           `json:"fromAccountID"`
+	ToAccountID   int64           `json:"toAccountID"`
+	Amount        int64           `json:"amount"`
+	ToAmount      int64           `json:"toAmount"`
+	ExchangeRate  string          `json:"exchangeRate"`
+	ReversalOf    sql.NullInt64   `json:"reversalOf"`
+	Memo          string          `json:"memo"`
+	Reference     string          `json:"reference"`
+	Metadata      json.RawMessage `json:"metadata"`
+	InitiatedBy   sql.NullString  `json:"initiatedBy"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.ReversalOf,
+		arg.Memo,
+		arg.Reference,
+		arg.Metadata,
+		arg.InitiatedBy,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -33,12 +87,50 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+		&i.Memo,
+		&i.Reference,
+		&i.Metadata,
+		&i.InitiatedBy,
+	)
+	return i, err
+}
+
+const getAccountOutgoingTotal = `-- name: GetAccountOutgoingTotal :one
+SELECT COALESCE(SUM(t.amount), 0)::bigint AS amount, COUNT(*) AS count
+FROM transfers t
+JOIN accounts c ON c.id = t.to_account_id
+WHERE t.from_account_id = $1
+  AND c.system_purpose IS NULL
+  AND t.reversal_of IS NULL
+  AND t.created_at > $2
+`
+
+type GetAccountOutgoingTotalParams struct {
+	AccountID int64     `json:"accountID"`
+	Since     time.Time `json:"since"`
+}
+
+type GetAccountOutgoingTotalRow struct {
+	Amount int64 `json:"amount"`
+	Count  int64 `json:"count"`
+}
+
+func (q *Queries) GetAccountOutgoingTotal(ctx context.Context, arg GetAccountOutgoingTotalParams) (GetAccountOutgoingTotalRow, error) {
+	row := q.db.QueryRowContext(ctx, getAccountOutgoingTotal, arg.AccountID, arg.Since)
+	var i GetAccountOutgoingTotalRow
+	err := row.Scan(
+		&i.Amount,
+		&i.Count,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount, memo, reference, metadata, initiated_by FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -51,12 +143,80 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+		&i.Memo,
+		&i.Reference,
+		&i.Metadata,
+		&i.InitiatedBy,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount, memo, reference, metadata, initiated_by FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+		&i.Memo,
+		&i.Reference,
+		&i.Metadata,
+		&i.InitiatedBy,
+	)
+	return i, err
+}
+
+const getUserOutgoingTotal = `-- name: GetUserOutgoingTotal :one
+SELECT COALESCE(SUM(t.amount), 0)::bigint AS amount, COUNT(*) AS count
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+JOIN accounts c ON c.id = t.to_account_id
+WHERE t.initiated_by = $1
+  AND a.currency = $2
+  AND c.system_purpose IS NULL
+  AND t.reversal_of IS NULL
+  AND t.created_at > $3
+`
+
+type GetUserOutgoingTotalParams struct {
+	Username string    `json:"username"`
+	Currency string    `json:"currency"`
+	Since    time.Time `json:"since"`
+}
+
+type GetUserOutgoingTotalRow struct {
+	Amount int64 `json:"amount"`
+	Count  int64 `json:"count"`
+}
+
+func (q *Queries) GetUserOutgoingTotal(ctx context.Context, arg GetUserOutgoingTotalParams) (GetUserOutgoingTotalRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserOutgoingTotal, arg.Username, arg.Currency, arg.Since)
+	var i GetUserOutgoingTotalRow
+	err := row.Scan(
+		&i.Amount,
+		&i.Count,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, reversal_of, reversed_amount, memo, reference, metadata, initiated_by FROM transfers
 WHERE from_account_id = $1 OR to_account_id = $2
 ORDER BY id
 LIMIT $3
@@ -90,6 +250,131 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.ReversalOf,
+			&i.ReversedAmount,
+			&i.Memo,
+			&i.Reference,
+			&i.Metadata,
+			&i.InitiatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserTransfers = `-- name: ListUserTransfers :many
+SELECT
+  t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.to_amount, t.exchange_rate, t.reversal_of, t.reversed_amount, t.memo, t.reference, t.metadata, t.initiated_by,
+  fa.owner AS from_account_owner,
+  fa.currency AS from_account_currency,
+  ta.owner AS to_account_owner,
+  ta.currency AS to_account_currency
+FROM transfers t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+WHERE
+  (
+    (
+      $1::varchar <> 'incoming'
+      AND fa.id IN (SELECT account_id FROM account_members WHERE username = $2)
+      AND ($3::bigint IS NULL OR t.from_account_id = $3)
+    ) OR (
+      $1::varchar <> 'outgoing'
+      AND ta.id IN (SELECT account_id FROM account_members WHERE username = $2)
+      AND ($3::bigint IS NULL OR t.to_account_id = $3)
+    )
+  )
+  AND ($4::bigint IS NULL OR t.amount >= $4)
+  AND ($5::bigint IS NULL OR t.amount <= $5)
+  AND ($6::timestamptz IS NULL OR t.created_at >= $6)
+  AND ($7::timestamptz IS NULL OR t.created_at < $7)
+  AND ($8::varchar IS NULL OR t.memo ILIKE $8 OR t.reference ILIKE $8)
+ORDER BY t.id DESC
+LIMIT $9
+OFFSET $10
+`
+
+type ListUserTransfersParams struct {
+	Direction     string         `json:"direction"`
+	Username      string         `json:"username"`
+	AccountID     sql.NullInt64  `json:"accountID"`
+	MinAmount     sql.NullInt64  `json:"minAmount"`
+	MaxAmount     sql.NullInt64  `json:"maxAmount"`
+	CreatedAfter  sql.NullTime   `json:"createdAfter"`
+	CreatedBefore sql.NullTime   `json:"createdBefore"`
+	Search        sql.NullString `json:"search"`
+	PageLimit     int32          `json:"pageLimit"`
+	PageOffset    int32          `json:"pageOffset"`
+}
+
+type ListUserTransfersRow struct {
+	ID                  int64           `json:"id"`
+	FromAccountID       int64           `json:"fromAccountID"`
+	ToAccountID         int64           `json:"toAccountID"`
+	Amount              int64           `json:"amount"`
+	CreatedAt           time.Time       `json:"createdAt"`
+	ToAmount            int64           `json:"toAmount"`
+	ExchangeRate        string          `json:"exchangeRate"`
+	ReversalOf          sql.NullInt64   `json:"reversalOf"`
+	ReversedAmount      int64           `json:"reversedAmount"`
+	Memo                string          `json:"memo"`
+	Reference           string          `json:"reference"`
+	Metadata            json.RawMessage `json:"metadata"`
+	InitiatedBy         sql.NullString  `json:"initiatedBy"`
+	FromAccountOwner    string          `json:"fromAccountOwner"`
+	FromAccountCurrency string          `json:"fromAccountCurrency"`
+	ToAccountOwner      string          `json:"toAccountOwner"`
+	ToAccountCurrency   string          `json:"toAccountCurrency"`
+}
+
+func (q *Queries) ListUserTransfers(ctx context.Context, arg ListUserTransfersParams) ([]ListUserTransfersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserTransfers,
+		arg.Direction,
+		arg.Username,
+		arg.AccountID,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Search,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserTransfersRow{}
+	for rows.Next() {
+		var i ListUserTransfersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.ReversalOf,
+			&i.ReversedAmount,
+			&i.Memo,
+			&i.Reference,
+			&i.Metadata,
+			&i.InitiatedBy,
+			&i.FromAccountOwner,
+			&i.FromAccountCurrency,
+			&i.ToAccountOwner,
+			&i.ToAccountCurrency,
 		); err != nil {
 			return nil, err
 		}
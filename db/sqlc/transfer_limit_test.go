@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetUserTransferLimit(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := SetUserTransferLimitParams{
+		Username: sql.NullString{String: user.Username, Valid: true},
+		Period: LimitDaily,
+		MaxAmount: 500,
+		MaxCount: 5,
+	}
+
+	limit1, err := testQueries.SetUserTransferLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, limit1.Username)
+	require.False(t, limit1.AccountID.Valid)
+	require.Equal(t, arg.Period, limit1.Period)
+	require.Equal(t, arg.MaxAmount, limit1.MaxAmount)
+	require.Equal(t, arg.MaxCount, limit1.MaxCount)
+
+	// setting the limit of the same period again replaces it
+	arg.MaxAmount = 800
+	limit2, err := testQueries.SetUserTransferLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, limit1.ID, limit2.ID)
+	require.Equal(t, int64(800), limit2.MaxAmount)
+}
+
+func TestListTransferLimits(t *testing.T) {
+	account := createRandomAccount(t)
+	other := createRandomAccount(t)
+
+	userLimit, err := testQueries.SetUserTransferLimit(context.Background(), SetUserTransferLimitParams{
+		Username: sql.NullString{String: account.Owner, Valid: true},
+		Period: LimitMonthly,
+		MaxAmount: 5000,
+		MaxCount: 50,
+	})
+	require.NoError(t, err)
+
+	accountLimit, err := testQueries.SetAccountTransferLimit(context.Background(), SetAccountTransferLimitParams{
+		AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+		Period: LimitDaily,
+		MaxAmount: 500,
+		MaxCount: 5,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.SetAccountTransferLimit(context.Background(), SetAccountTransferLimitParams{
+		AccountID: sql.NullInt64{Int64: other.ID, Valid: true},
+		Period: LimitDaily,
+		MaxAmount: 500,
+		MaxCount: 5,
+	})
+	require.NoError(t, err)
+
+	limits, err := testQueries.ListTransferLimits(context.Background(), ListTransferLimitsParams{
+		Username: account.Owner,
+		AccountID: account.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, limits, 2)
+	require.Equal(t, userLimit.ID, limits[0].ID)
+	require.Equal(t, accountLimit.ID, limits[1].ID)
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTransferRequest(t *testing.T, fromAccount Account, toAccount Account, amount int64) TransferRequest {
+	arg := CreateTransferRequestParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID: toAccount.ID,
+		Amount: amount,
+		RequestedBy: fromAccount.Owner,
+		Metadata: emptyMetadata,
+	}
+
+	request, err := testQueries.CreateTransferRequest(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, request)
+
+	require.Equal(t, arg.FromAccountID, request.FromAccountID)
+	require.Equal(t, arg.ToAccountID, request.ToAccountID)
+	require.Equal(t, arg.Amount, request.Amount)
+	require.Equal(t, arg.RequestedBy, request.RequestedBy)
+	require.Equal(t, TransferRequestPending, request.Status)
+	require.False(t, request.DecidedBy.Valid)
+	require.False(t, request.TransferID.Valid)
+
+	require.NotZero(t, request.ID)
+	require.NotZero(t, request.CreatedAt)
+	return request
+}
+
+func addRandomAccountApprover(t *testing.T, account Account) User {
+	user := createRandomUser(t)
+
+	approver, err := testQueries.CreateAccountApprover(context.Background(), CreateAccountApproverParams{
+		AccountID: account.ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, approver.AccountID)
+	require.Equal(t, user.Username, approver.Username)
+	return user
+}
+
+func TestCreateTransferRequest(t *testing.T) {
+	createRandomTransferRequest(t, createRandomAccount(t), createRandomAccount(t), 100)
+}
+
+func TestAccountApprovers(t *testing.T) {
+	account := createRandomAccount(t)
+	approver1 := addRandomAccountApprover(t, account)
+	approver2 := addRandomAccountApprover(t, account)
+
+	approvers, err := testQueries.ListAccountApprovers(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Len(t, approvers, 2)
+
+	_, err = testQueries.GetAccountApprover(context.Background(), GetAccountApproverParams{
+		AccountID: account.ID,
+		Username: approver1.Username,
+	})
+	require.NoError(t, err)
+
+	err = testQueries.DeleteAccountApprover(context.Background(), DeleteAccountApproverParams{
+		AccountID: account.ID,
+		Username: approver1.Username,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.GetAccountApprover(context.Background(), GetAccountApproverParams{
+		AccountID: account.ID,
+		Username: approver1.Username,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	approvers, err = testQueries.ListAccountApprovers(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Len(t, approvers, 1)
+	require.Equal(t, approver2.Username, approvers[0].Username)
+}
+
+func TestListUserTransferRequests(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	approver := addRandomAccountApprover(t, account1)
+	coOwner := createRandomUser(t)
+	_, err := testQueries.UpsertAccountMember(context.Background(), UpsertAccountMemberParams{
+		AccountID: account1.ID,
+		Username: coOwner.Username,
+		Role: MemberCoOwner,
+	})
+	require.NoError(t, err)
+
+	request1 := createRandomTransferRequest(t, account1, account2, 100)
+	request2 := createRandomTransferRequest(t, account1, account2, 200)
+
+	_, err = testQueries.DecideTransferRequest(context.Background(), DecideTransferRequestParams{
+		ID: request1.ID,
+		Status: TransferRequestRejected,
+		DecidedBy: sql.NullString{String: approver.Username, Valid: true},
+	})
+	require.NoError(t, err)
+
+	// the members and the approver all see the requests, most recent first
+	for _, username := range []string{account1.Owner, coOwner.Username, approver.Username} {
+		requests, err := testQueries.ListUserTransferRequests(context.Background(), ListUserTransferRequestsParams{
+			Username: username,
+			PageLimit: 5,
+			PageOffset: 0,
+		})
+		require.NoError(t, err)
+		require.Len(t, requests, 2)
+		require.Equal(t, request2.ID, requests[0].ID)
+		require.Equal(t, request1.ID, requests[1].ID)
+	}
+
+	requests, err := testQueries.ListUserTransferRequests(context.Background(), ListUserTransferRequestsParams{
+		Username: approver.Username,
+		Status: sql.NullString{String: TransferRequestPending, Valid: true},
+		PageLimit: 5,
+		PageOffset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.Equal(t, request2.ID, requests[0].ID)
+
+	// the recipient isn't involved in approving them
+	requests, err = testQueries.ListUserTransferRequests(context.Background(), ListUserTransferRequestsParams{
+		Username: account2.Owner,
+		PageLimit: 5,
+		PageOffset: 0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, requests)
+}
+
+func TestDecideTransferRequestOnce(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	approver := addRandomAccountApprover(t, account1)
+	request := createRandomTransferRequest(t, account1, account2, 100)
+
+	arg := DecideTransferRequestParams{
+		ID: request.ID,
+		Status: TransferRequestRejected,
+		DecidedBy: sql.NullString{String: approver.Username, Valid: true},
+	}
+
+	rejected, err := testQueries.DecideTransferRequest(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestRejected, rejected.Status)
+	require.Equal(t, approver.Username, rejected.DecidedBy.String)
+	require.True(t, rejected.DecidedAt.Valid)
+
+	_, err = testQueries.DecideTransferRequest(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -10,10 +12,16 @@ import (
 )
 
 func createRandomTransfer(t *testing.T, fromAccount Account, toAccount Account) Transfer {
+	amount := util.RandomMoney()
 	arg := CreateTransferParams{
 		FromAccountID: fromAccount.ID,
 		ToAccountID: toAccount.ID,
-		Amount: util.RandomMoney(),
+		Amount: amount,
+		ToAmount: amount,
+		ExchangeRate: sameCurrencyRate,
+		Memo: util.RandomString(12),
+		Reference: util.RandomString(8),
+		Metadata: json.RawMessage(`{"invoice":"` + util.RandomString(6) + `"}`),
 	}
 
 	transfer, err := testQueries.CreateTransfer(context.Background(), arg)
@@ -23,6 +31,11 @@ func createRandomTransfer(t *testing.T, fromAccount Account, toAccount Account)
 	require.Equal(t, arg.FromAccountID, transfer.FromAccountID)
 	require.Equal(t, arg.ToAccountID, transfer.ToAccountID)
 	require.Equal(t, arg.Amount, transfer.Amount)
+	require.Equal(t, arg.ToAmount, transfer.ToAmount)
+	require.Equal(t, arg.ExchangeRate, transfer.ExchangeRate)
+	require.Equal(t, arg.Memo, transfer.Memo)
+	require.Equal(t, arg.Reference, transfer.Reference)
+	require.JSONEq(t, string(arg.Metadata), string(transfer.Metadata))
 
 	require.NotZero(t, transfer.ID)
 	require.NotZero(t, transfer.CreatedAt)
@@ -73,4 +86,76 @@ func TestListTransfers(t *testing.T) {
 	for _, transfer := range transfers {
 		require.NotEmpty(t, transfer)
 	}
-}
\ No newline at end of file
+}
+
+func TestListUserTransfers(t *testing.T) {
+	acc1 := createRandomAccount(t)
+	acc2 := createRandomAccount(t)
+
+	outgoing := createRandomTransfer(t, acc1, acc2)
+	incoming := createRandomTransfer(t, acc2, acc1)
+
+	arg := ListUserTransfersParams{
+		Username: acc1.Owner,
+		PageLimit: 5,
+		PageOffset: 0,
+	}
+
+	transfers, err := testQueries.ListUserTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 2)
+
+	// most recent first, with both sides' owner and currency
+	require.Equal(t, incoming.ID, transfers[0].ID)
+	require.Equal(t, acc2.Owner, transfers[0].FromAccountOwner)
+	require.Equal(t, acc2.Currency, transfers[0].FromAccountCurrency)
+	require.Equal(t, acc1.Owner, transfers[0].ToAccountOwner)
+	require.Equal(t, acc1.Currency, transfers[0].ToAccountCurrency)
+	require.Equal(t, outgoing.ID, transfers[1].ID)
+
+	arg.Direction = "outgoing"
+	transfers, err = testQueries.ListUserTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, outgoing.ID, transfers[0].ID)
+
+	arg.Direction = "incoming"
+	transfers, err = testQueries.ListUserTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, incoming.ID, transfers[0].ID)
+
+	arg.Direction = ""
+	arg.MinAmount = sql.NullInt64{Int64: incoming.Amount, Valid: true}
+	arg.MaxAmount = sql.NullInt64{Int64: incoming.Amount, Valid: true}
+	transfers, err = testQueries.ListUserTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	for _, transfer := range transfers {
+		require.Equal(t, incoming.Amount, transfer.Amount)
+	}
+
+	arg.MinAmount = sql.NullInt64{}
+	arg.MaxAmount = sql.NullInt64{}
+	arg.Search = sql.NullString{String: "%" + outgoing.Reference + "%", Valid: true}
+	transfers, err = testQueries.ListUserTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, outgoing.ID, transfers[0].ID)
+	require.Equal(t, outgoing.Memo, transfers[0].Memo)
+
+	arg.Search = sql.NullString{}
+	arg.CreatedAfter = sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}
+	transfers, err = testQueries.ListUserTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, transfers)
+
+	// the other user doesn't see them through an account of the first
+	transfers, err = testQueries.ListUserTransfers(context.Background(), ListUserTransfersParams{
+		Username: acc2.Owner,
+		AccountID: sql.NullInt64{Int64: acc1.ID, Valid: true},
+		PageLimit: 5,
+		PageOffset: 0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, transfers)
+}
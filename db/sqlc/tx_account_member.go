@@ -0,0 +1,81 @@
+package db
+
+import "context"
+
+// Constants for all roles a user can have on an account
+const (
+	MemberOwner   = "owner"
+	MemberCoOwner = "co_owner"
+	MemberViewer  = "viewer"
+	MemberPayer   = "payer"
+)
+
+// MemberMayPay reports whether a member with the role is allowed to move money out of the account
+func MemberMayPay(role string) bool {
+	return role == MemberOwner || role == MemberCoOwner || role == MemberPayer
+}
+
+// DefaultAccountProduct is the product accounts are opened as when the user doesn't choose one
+const DefaultAccountProduct = "checking"
+
+// CreateAccountTx opens an account and makes the user who opened it its owner member
+// An account opened as a product gets the product's overdraft, and it returns ErrProductAccountLimit
+// if the user already has as many open accounts of the product in the currency as it allows
+func (store *SQLStore) CreateAccountTx(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	var result Account
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var product AccountProduct
+		if arg.Product.Valid {
+			// locking the owner keeps two accounts opened at once from both fitting under the product's limit
+			_, err := q.GetUserForUpdate(ctx, arg.Owner)
+			if err != nil {
+				return err
+			}
+
+			product, err = q.GetAccountProduct(ctx, arg.Product.String)
+			if err != nil {
+				return err
+			}
+
+			count, err := q.CountOpenProductAccounts(ctx, CountOpenProductAccountsParams{
+				Owner: arg.Owner,
+				Currency: arg.Currency,
+				Product: product.Code,
+			})
+			if err != nil {
+				return err
+			}
+			if count >= int64(product.MaxAccounts) {
+				return ErrProductAccountLimit
+			}
+		}
+
+		var err error
+		result, err = q.CreateAccount(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if product.OverdraftLimit > 0 {
+			result, err = q.UpdateAccountOverdraft(ctx, UpdateAccountOverdraftParams{
+				ID: result.ID,
+				OverdraftLimit: product.OverdraftLimit,
+				OverdraftAnnualRate: product.OverdraftAnnualRate,
+				OverdraftDailyFee: product.OverdraftDailyFee,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = q.UpsertAccountMember(ctx, UpsertAccountMemberParams{
+			AccountID: result.ID,
+			Username: result.Owner,
+			Role: MemberOwner,
+		})
+		return err
+	})
+
+	return result, err
+}
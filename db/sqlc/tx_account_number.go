@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nokkvi/simplebank/util"
+)
+
+const (
+	uniqueViolation = "unique_violation"
+
+	accountNumberConstraint = "accounts_number_key"
+)
+
+// accountNumberAttempts is how many random account numbers are tried for an account before giving up
+const accountNumberAttempts = 3
+
+// unnumberedAccountsBatch is how many accounts without a number are listed at a time
+const unnumberedAccountsBatch = 100
+
+// AssignAccountNumbersParams contains the input parameters of assigning account numbers
+type AssignAccountNumbersParams struct {
+	CountryCode string `json:"country_code"`
+	BankCode string `json:"bank_code"`
+}
+
+// AssignAccountNumbers gives a random account number to every account that was opened before account numbers
+// and returns how many accounts it numbered
+// Each account is numbered on its own, and a number that already belongs to another account is replaced by another random one
+func (store *SQLStore) AssignAccountNumbers(ctx context.Context, arg AssignAccountNumbersParams) (int64, error) {
+	if err := util.ValidateBankCode(arg.CountryCode, arg.BankCode); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for {
+		accounts, err := store.ListUnnumberedAccounts(ctx, unnumberedAccountsBatch)
+		if err != nil {
+			return count, err
+		}
+
+		for _, account := range accounts {
+			numbered, err := store.assignAccountNumber(ctx, account.ID, arg)
+			if err != nil {
+				return count, err
+			}
+			if numbered {
+				count++
+			}
+		}
+
+		if len(accounts) < unnumberedAccountsBatch {
+			return count, nil
+		}
+	}
+}
+
+// assignAccountNumber gives the account a random account number and reports whether it did,
+// it doesn't when the account was numbered in the meantime, such as by another server starting up
+func (store *SQLStore) assignAccountNumber(ctx context.Context, accountID int64, arg AssignAccountNumbersParams) (bool, error) {
+	var err error
+	for attempt := 0; attempt < accountNumberAttempts; attempt++ {
+		var number string
+		number, err = util.NewAccountNumber(arg.CountryCode, arg.BankCode)
+		if err != nil {
+			return false, err
+		}
+
+		var rows int64
+		rows, err = store.SetAccountNumber(ctx, SetAccountNumberParams{
+			ID: accountID,
+			Number: sql.NullString{String: number, Valid: true},
+		})
+		if !isConstraintViolation(err, uniqueViolation, accountNumberConstraint) {
+			return rows > 0, err
+		}
+	}
+
+	return false, err
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignAccountNumbers(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+
+	_, err := store.AssignAccountNumbers(context.Background(), AssignAccountNumbersParams{
+		CountryCode: "XB",
+		BankCode: "TEST",
+	})
+	require.NoError(t, err)
+
+	accounts, err := testQueries.ListUnnumberedAccounts(context.Background(), 1)
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+
+	// an account that has a number keeps it
+	rows, err := testQueries.SetAccountNumber(context.Background(), SetAccountNumberParams{
+		ID: account.ID,
+		Number: sql.NullString{String: util.RandomAccountNumber(), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	_, err = store.AssignAccountNumbers(context.Background(), AssignAccountNumbersParams{
+		CountryCode: "xb",
+		BankCode: "TEST",
+	})
+	require.Error(t, err)
+}
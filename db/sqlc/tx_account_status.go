@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// Constants for all statuses of an account
+const (
+	AccountActive = "active"
+	AccountFrozen = "frozen"
+	AccountClosed = "closed"
+)
+
+// accountStatusTransitions lists the statuses an account can move to from each status
+// A closed account stays closed, its history is kept but no money moves through it again
+var accountStatusTransitions = map[string][]string{
+	AccountActive: {AccountFrozen, AccountClosed},
+	AccountFrozen: {AccountActive, AccountClosed},
+}
+
+// ChangeAccountStatusTxParams contains the input parameters of the change account status transaction
+type ChangeAccountStatusTxParams struct {
+	AccountID int64 `json:"account_id"`
+	Status string `json:"status"`
+	Actor string `json:"actor"`
+	Reason string `json:"reason"`
+}
+
+// ChangeAccountStatusTxResult is the result of the change account status transaction
+type ChangeAccountStatusTxResult struct {
+	Account Account `json:"account"`
+	StatusChange AccountStatusChange `json:"status_change"`
+}
+
+// ChangeAccountStatusTx moves an account to another status and records who did it and why
+// It returns ErrAccountStatusTransition if the account can't move from its current status to the new one,
+// and ErrAccountBalanceNotZero if an account is closed while it still holds money
+func (store *SQLStore) ChangeAccountStatusTx(ctx context.Context, arg ChangeAccountStatusTxParams) (ChangeAccountStatusTxResult, error) {
+	var result ChangeAccountStatusTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if !canChangeAccountStatus(account.Status, arg.Status) {
+			return fmt.Errorf("%w: from %s to %s", ErrAccountStatusTransition, account.Status, arg.Status)
+		}
+
+		if arg.Status == AccountClosed && account.Balance != 0 {
+			return ErrAccountBalanceNotZero
+		}
+
+		result.Account, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID: arg.AccountID,
+			Status: arg.Status,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.StatusChange, err = q.CreateAccountStatusChange(ctx, CreateAccountStatusChangeParams{
+			AccountID: arg.AccountID,
+			FromStatus: account.Status,
+			ToStatus: arg.Status,
+			Actor: arg.Actor,
+			Reason: arg.Reason,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+func canChangeAccountStatus(from string, to string) bool {
+	for _, status := range accountStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAccountsActive returns ErrAccountFrozen or ErrAccountClosed for the first of the accounts that isn't active
+// Money can't be moved into or out of such an account, the caller must hold the locks of the accounts
+func checkAccountsActive(accounts ...Account) error {
+	for _, account := range accounts {
+		switch account.Status {
+		case AccountFrozen:
+			return fmt.Errorf("%w: account %d", ErrAccountFrozen, account.ID)
+		case AccountClosed:
+			return fmt.Errorf("%w: account %d", ErrAccountClosed, account.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeAccountStatusTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+	banker := createRandomUser(t)
+
+	result, err := store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account1.ID,
+		Status: AccountFrozen,
+		Actor: banker.Username,
+		Reason: "suspected fraud",
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountFrozen, result.Account.Status)
+	require.Equal(t, AccountActive, result.StatusChange.FromStatus)
+	require.Equal(t, AccountFrozen, result.StatusChange.ToStatus)
+	require.Equal(t, banker.Username, result.StatusChange.Actor)
+	require.Equal(t, "suspected fraud", result.StatusChange.Reason)
+
+	// money moves neither out of nor into a frozen account
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 10,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID: account1.ID,
+		Amount: 10,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// a closed account keeps its history, so it must be emptied first
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account1.ID,
+		Status: AccountClosed,
+		Actor: account1.Owner,
+		Reason: "moving banks",
+	})
+	require.ErrorIs(t, err, ErrAccountBalanceNotZero)
+
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account1.ID,
+		Status: AccountActive,
+		Actor: banker.Username,
+		Reason: "cleared",
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 1000,
+	})
+	require.NoError(t, err)
+
+	result, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account1.ID,
+		Status: AccountClosed,
+		Actor: account1.Owner,
+		Reason: "moving banks",
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountClosed, result.Account.Status)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID: account1.ID,
+		Amount: 10,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account1.ID,
+		Status: AccountActive,
+		Actor: banker.Username,
+		Reason: "reopen",
+	})
+	require.ErrorIs(t, err, ErrAccountStatusTransition)
+
+	entries, err := testQueries.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: account1.ID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	changes, err := testQueries.ListAccountStatusChanges(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, AccountFrozen, changes[0].ToStatus)
+	require.Equal(t, AccountActive, changes[1].ToStatus)
+	require.Equal(t, AccountClosed, changes[2].ToStatus)
+
+	// the currency of a closed account is free for a new one
+	_, err = testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner: account1.Owner,
+		Currency: account1.Currency,
+		Number: sql.NullString{String: util.RandomAccountNumber(), Valid: true},
+	})
+	require.NoError(t, err)
+}
+
+func TestAuthorizeHoldTxFrozenAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	_, err := store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account1.ID,
+		Status: AccountFrozen,
+		Actor: account1.Owner,
+		Reason: "lost card",
+	})
+	require.NoError(t, err)
+
+	_, err = store.AuthorizeHoldTx(context.Background(), AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 10,
+		ExpiresAt: time.Now().Add(time.Hour),
+		InitiatedBy: account1.Owner,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+}
+
+func TestCanChangeAccountStatus(t *testing.T) {
+	require.True(t, canChangeAccountStatus(AccountActive, AccountFrozen))
+	require.True(t, canChangeAccountStatus(AccountFrozen, AccountActive))
+	require.True(t, canChangeAccountStatus(AccountActive, AccountClosed))
+	require.True(t, canChangeAccountStatus(AccountFrozen, AccountClosed))
+
+	require.False(t, canChangeAccountStatus(AccountActive, AccountActive))
+	require.False(t, canChangeAccountStatus(AccountClosed, AccountActive))
+	require.False(t, canChangeAccountStatus(AccountClosed, AccountFrozen))
+	require.False(t, canChangeAccountStatus(AccountActive, "deleted"))
+}
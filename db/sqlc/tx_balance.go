@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// BalanceAtTxParams contains the input parameters of the balance at transaction
+type BalanceAtTxParams struct {
+	AccountID int64 `json:"account_id"`
+	At time.Time `json:"at"`
+}
+
+// BalanceAt is the balance an account had at a point in time
+type BalanceAt struct {
+	AccountID int64 `json:"account_id"`
+	Currency string `json:"currency"`
+	At time.Time `json:"at"`
+	Balance int64 `json:"balance"`
+}
+
+// BalanceAtTx returns the balance of an account after every entry made before arg.At
+// It starts from the closest balance snapshot, so only the entries between the snapshot and arg.At are added up
+func (store *SQLStore) BalanceAtTx(ctx context.Context, arg BalanceAtTxParams) (BalanceAt, error) {
+	var result BalanceAt
+
+	// the snapshots, the entries and the current balance are read from the same snapshot
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := store.execTxWithOptions(ctx, opts, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		balance, err := balanceAt(ctx, q, account, arg.At)
+		if err != nil {
+			return err
+		}
+
+		result = BalanceAt{
+			AccountID: account.ID,
+			Currency: account.Currency,
+			At: arg.At,
+			Balance: balance,
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// CreateBalanceSnapshotsTx snapshots the balance of every open account at takenAt and returns how many snapshots it took
+// Entries are dated when their transaction starts, so one that commits after the snapshot could still be dated before it.
+// It fails with ErrSnapshotTooEarly while a transaction that started before takenAt and has written to the database is still running,
+// so a snapshot only covers transactions that have all committed, and new entries are never held back while it runs.
+// Transactions that make entries take their row locks first, which gives them a transaction id right after they start.
+// Seeing the transactions of other database roles needs the pg_read_all_stats role, without it they go unnoticed
+func (store *SQLStore) CreateBalanceSnapshotsTx(ctx context.Context, takenAt time.Time) (int64, error) {
+	var count int64
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// the balances are read by the next statement, after every transaction found to have finished here has committed
+		running, err := q.CountWritersStartedBefore(ctx, takenAt)
+		if err != nil {
+			return err
+		}
+		if running > 0 {
+			return ErrSnapshotTooEarly
+		}
+
+		count, err = q.CreateBalanceSnapshots(ctx, takenAt)
+		return err
+	})
+
+	return count, err
+}
+
+// balanceAt works out the balance of the account after every entry made before at
+// It adds the entries made since the latest snapshot taken at or before at, takes those made until the next snapshot off that one,
+// or takes the entries made since at off the current balance when there is no snapshot to start from
+func balanceAt(ctx context.Context, q *Queries, account Account, at time.Time) (int64, error) {
+	snapshot, err := q.GetLatestBalanceSnapshot(ctx, GetLatestBalanceSnapshotParams{
+		AccountID: account.ID,
+		At: at,
+	})
+	if err == nil {
+		total, err := q.GetAccountEntriesTotalBetween(ctx, GetAccountEntriesTotalBetweenParams{
+			AccountID: account.ID,
+			CreatedAfter: snapshot.TakenAt,
+			CreatedBefore: at,
+		})
+		return snapshot.Balance + total, err
+	}
+	if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	snapshot, err = q.GetNextBalanceSnapshot(ctx, GetNextBalanceSnapshotParams{
+		AccountID: account.ID,
+		At: at,
+	})
+	if err == nil {
+		total, err := q.GetAccountEntriesTotalBetween(ctx, GetAccountEntriesTotalBetweenParams{
+			AccountID: account.ID,
+			CreatedAfter: at,
+			CreatedBefore: snapshot.TakenAt,
+		})
+		return snapshot.Balance - total, err
+	}
+	if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	total, err := q.GetAccountEntriesTotalSince(ctx, GetAccountEntriesTotalSinceParams{
+		AccountID: account.ID,
+		Since: at,
+	})
+	return account.Balance - total, err
+}
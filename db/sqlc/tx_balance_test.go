@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceAtTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 100,
+	})
+	require.NoError(t, err)
+
+	between := time.Now()
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID: account1.ID,
+		Amount: 30,
+	})
+	require.NoError(t, err)
+
+	balanceAt := func(at time.Time) int64 {
+		balance, err := store.BalanceAtTx(context.Background(), BalanceAtTxParams{
+			AccountID: account1.ID,
+			At: at,
+		})
+		require.NoError(t, err)
+		require.Equal(t, account1.ID, balance.AccountID)
+		require.Equal(t, account1.Currency, balance.Currency)
+		return balance.Balance
+	}
+
+	// without snapshots the balance is worked back from the current one
+	require.Equal(t, int64(1000), balanceAt(between.Add(-time.Hour)))
+	require.Equal(t, int64(900), balanceAt(between))
+	require.Equal(t, int64(930), balanceAt(time.Now().Add(time.Second)))
+
+	count, err := testQueries.CreateBalanceSnapshots(context.Background(), between)
+	require.NoError(t, err)
+	require.NotZero(t, count)
+
+	snapshot, err := testQueries.GetLatestBalanceSnapshot(context.Background(), GetLatestBalanceSnapshotParams{
+		AccountID: account1.ID,
+		At: between,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(900), snapshot.Balance)
+
+	// a snapshot that was already taken is kept
+	count, err = testQueries.CreateBalanceSnapshots(context.Background(), between)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	// with a snapshot the balance is worked out from it, before or after it
+	require.Equal(t, int64(1000), balanceAt(between.Add(-time.Hour)))
+	require.Equal(t, int64(900), balanceAt(between))
+	require.Equal(t, int64(930), balanceAt(time.Now().Add(time.Second)))
+}
+
+func TestCreateBalanceSnapshotsTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccountWithBalance(t, 1000)
+
+	// a transaction makes an entry that is dated when it started, before the snapshot, but commits after the snapshot is first tried
+	tx, err := testDB.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+
+	entry, err := New(tx).CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: account.ID,
+		Amount: 50,
+	})
+	require.NoError(t, err)
+	_, err = New(tx).AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID: account.ID,
+		Amount: 50,
+	})
+	require.NoError(t, err)
+
+	takenAt := entry.CreatedAt.Add(time.Millisecond)
+
+	_, err = store.CreateBalanceSnapshotsTx(context.Background(), takenAt)
+	require.ErrorIs(t, err, ErrSnapshotTooEarly)
+
+	require.NoError(t, tx.Commit())
+
+	_, err = store.CreateBalanceSnapshotsTx(context.Background(), takenAt)
+	require.NoError(t, err)
+
+	snapshot, err := testQueries.GetLatestBalanceSnapshot(context.Background(), GetLatestBalanceSnapshotParams{
+		AccountID: account.ID,
+		At: takenAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, takenAt.Unix(), snapshot.TakenAt.Unix())
+	require.Equal(t, int64(1050), snapshot.Balance)
+
+	balance, err := store.BalanceAtTx(context.Background(), BalanceAtTxParams{
+		AccountID: account.ID,
+		At: takenAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1050), balance.Balance)
+}
+
+func TestCreateBalanceSnapshotsTxReadOnly(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+
+	// a transaction that only reads makes no entries, so the snapshot doesn't wait for it
+	tx, err := testDB.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	_, err = New(tx).GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	takenAt := time.Now()
+
+	count, err := store.CreateBalanceSnapshotsTx(context.Background(), takenAt)
+	require.NoError(t, err)
+	require.NotZero(t, count)
+
+	require.NoError(t, tx.Rollback())
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// BatchTransferLeg is one transfer of a batch
+type BatchTransferLeg struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID int64 `json:"to_account_id"`
+	Amount int64 `json:"amount"`
+	TransferDetails
+}
+
+// BatchTransferTxParams contains the input parameters of the batch transfer transaction
+type BatchTransferTxParams struct {
+	Legs []BatchTransferLeg `json:"legs"`
+	// InitiatedBy is the user making the transfers, its user limits are theirs
+	InitiatedBy string `json:"initiated_by"`
+	// IdempotencyKey makes retries of the same request replay the original result when set
+	IdempotencyKey *IdempotencyKeyParams `json:"-"`
+	// DefaultLimits are the bank-wide transfer limits by period, for users and accounts without limits of their own
+	DefaultLimits map[string]DefaultTransferLimit `json:"-"`
+}
+
+// BatchTransferTxResult is the result of the batch transfer transaction, with one transfer result per leg
+type BatchTransferTxResult struct {
+	Transfers []TransferTxResult `json:"transfers"`
+}
+
+// BatchLegError is returned when one leg of a batch transfer fails, which fails the whole batch
+type BatchLegError struct {
+	Leg int
+	Err error
+}
+
+func (e *BatchLegError) Error() string {
+	return fmt.Sprintf("transfer %d: %v", e.Leg, e.Err)
+}
+
+func (e *BatchLegError) Unwrap() error {
+	return e.Err
+}
+
+// BatchTransferTx performs all transfers of a batch within a single database transaction, or none of them
+// Every account of the batch is locked up front in ascending ID order, so batches sharing accounts can't deadlock
+// Each leg counts towards the transfer limits of its from account and owner, like a transfer of its own
+// The legs run in order and each one sees the balances left by the ones before it
+// A failing leg is returned as a *BatchLegError wrapping the cause, such as ErrInsufficientFunds
+func (store *SQLStore) BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error) {
+	var result BatchTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if arg.IdempotencyKey != nil {
+			replayed, err := claimIdempotencyKey(ctx, q, *arg.IdempotencyKey, &result)
+			if err != nil || replayed {
+				return err
+			}
+		}
+
+		accountIDs := make([]int64, 0, 2*len(arg.Legs))
+		for _, leg := range arg.Legs {
+			accountIDs = append(accountIDs, leg.FromAccountID, leg.ToAccountID)
+		}
+
+		err := lockUsers(ctx, q, arg.InitiatedBy)
+		if err != nil {
+			return err
+		}
+
+		accounts, err := lockAccountIDs(ctx, q, accountIDs...)
+		if err != nil {
+			return err
+		}
+
+		result.Transfers = make([]TransferTxResult, len(arg.Legs))
+		for i, leg := range arg.Legs {
+			// the totals include the legs before this one, they were made within the same transaction
+			err = checkTransferLimits(ctx, q, accounts[leg.FromAccountID], arg.InitiatedBy, leg.Amount, arg.DefaultLimits)
+			if err != nil {
+				return &BatchLegError{Leg: i, Err: err}
+			}
+
+			result.Transfers[i], err = transferMoney(ctx, q, CreateTransferParams{
+				FromAccountID: leg.FromAccountID,
+				ToAccountID: leg.ToAccountID,
+				Amount: leg.Amount,
+				ToAmount: leg.Amount,
+				ExchangeRate: sameCurrencyRate,
+				Memo: leg.Memo,
+				Reference: leg.Reference,
+				Metadata: leg.metadataJSON(),
+				InitiatedBy: initiatedBy(arg.InitiatedBy),
+			})
+			if err != nil {
+				return &BatchLegError{Leg: i, Err: err}
+			}
+		}
+
+		if arg.IdempotencyKey != nil {
+			return saveIdempotencyKeyResponse(ctx, q, *arg.IdempotencyKey, result)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+
+	arg := BatchTransferTxParams{
+		Legs: []BatchTransferLeg{
+			{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 100},
+			{FromAccountID: account1.ID, ToAccountID: account3.ID, Amount: 200},
+			{FromAccountID: account2.ID, ToAccountID: account3.ID, Amount: 50},
+		},
+	}
+
+	result, err := store.BatchTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, result.Transfers, len(arg.Legs))
+
+	for i, leg := range arg.Legs {
+		transfer := result.Transfers[i].Transfer
+		require.NotZero(t, transfer.ID)
+		require.Equal(t, leg.FromAccountID, transfer.FromAccountID)
+		require.Equal(t, leg.ToAccountID, transfer.ToAccountID)
+		require.Equal(t, leg.Amount, transfer.Amount)
+
+		require.Equal(t, -leg.Amount, result.Transfers[i].FromEntry.Amount)
+		require.Equal(t, leg.Amount, result.Transfers[i].ToEntry.Amount)
+	}
+
+	// each leg sees the balances left by the ones before it
+	require.Equal(t, int64(1000-100), result.Transfers[0].FromAccount.Balance)
+	require.Equal(t, int64(1000-100-200), result.Transfers[1].FromAccount.Balance)
+	require.Equal(t, account2.Balance+100-50, result.Transfers[2].FromAccount.Balance)
+	require.Equal(t, account3.Balance+200+50, result.Transfers[2].ToAccount.Balance)
+}
+
+func TestBatchTransferTxFailingLeg(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+
+	arg := BatchTransferTxParams{
+		Legs: []BatchTransferLeg{
+			{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 60},
+			{FromAccountID: account1.ID, ToAccountID: account3.ID, Amount: 60},
+		},
+	}
+
+	_, err := store.BatchTransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	var legErr *BatchLegError
+	require.True(t, errors.As(err, &legErr))
+	require.Equal(t, 1, legErr.Leg)
+
+	// nothing of the batch was applied
+	for _, account := range []Account{account1, account2, account3} {
+		updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+		require.NoError(t, err)
+		require.Equal(t, account.Balance, updatedAccount.Balance)
+	}
+}
+
+func TestBatchTransferTxDeadlock(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccountWithBalance(t, 1000)
+	account3 := createRandomAccountWithBalance(t, 1000)
+
+	// batches that touch the same accounts in opposite orders
+	n := 10
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		arg := BatchTransferTxParams{
+			Legs: []BatchTransferLeg{
+				{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10},
+				{FromAccountID: account2.ID, ToAccountID: account3.ID, Amount: 10},
+				{FromAccountID: account3.ID, ToAccountID: account1.ID, Amount: 10},
+			},
+		}
+		if i%2 == 1 {
+			arg.Legs = []BatchTransferLeg{
+				{FromAccountID: account3.ID, ToAccountID: account2.ID, Amount: 10},
+				{FromAccountID: account2.ID, ToAccountID: account1.ID, Amount: 10},
+				{FromAccountID: account1.ID, ToAccountID: account3.ID, Amount: 10},
+			}
+		}
+
+		go func() {
+			_, err := store.BatchTransferTx(context.Background(), arg)
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+	}
+
+	// every batch moves money in a circle, so balances end where they started
+	for _, account := range []Account{account1, account2, account3} {
+		updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+		require.NoError(t, err)
+		require.Equal(t, account.Balance, updatedAccount.Balance)
+	}
+}
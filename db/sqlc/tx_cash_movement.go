@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/nokkvi/simplebank/util"
+)
+
+// Constants for the kinds of cash movements
+const (
+	CashDeposit    = "deposit"
+	CashWithdrawal = "withdrawal"
+)
+
+// CashTxParams contains the input parameters of the deposit and withdrawal transactions
+type CashTxParams struct {
+	AccountID int64 `json:"account_id"`
+	Amount int64 `json:"amount"`
+	// Fee is charged to the account on top of the deposit or withdrawal and paid into the bank's fees account
+	Fee int64 `json:"fee"`
+	// Banker is the user who took in or paid out the cash
+	Banker string `json:"banker"`
+	TransferDetails
+	// IdempotencyKey makes retries of the same request replay the original result when set
+	IdempotencyKey *IdempotencyKeyParams `json:"-"`
+}
+
+// CashTxResult is the result of the deposit and withdrawal transactions
+type CashTxResult struct {
+	Movement CashMovement `json:"movement"`
+	Transfer TransferTxResult `json:"transfer"`
+	// FeeTransfer is nil when there was no fee
+	FeeTransfer *TransferTxResult `json:"fee_transfer"`
+}
+
+// DepositTx pays cash taken in at the bank into an account, from the bank's cash account in the account's currency
+// It returns ErrSystemAccount for the bank's own accounts, an error wrapping ErrAccountFrozen or ErrAccountClosed
+// if the account isn't active, and ErrInsufficientFunds if the account can't pay the fee
+func (store *SQLStore) DepositTx(ctx context.Context, arg CashTxParams) (CashTxResult, error) {
+	return store.cashTx(ctx, CashDeposit, arg)
+}
+
+// WithdrawTx pays cash out of an account into the bank's cash account in the account's currency
+// It returns ErrSystemAccount for the bank's own accounts, an error wrapping ErrAccountFrozen or ErrAccountClosed
+// if the account isn't active, and ErrInsufficientFunds if the account's funds don't cover the amount and the fee
+func (store *SQLStore) WithdrawTx(ctx context.Context, arg CashTxParams) (CashTxResult, error) {
+	return store.cashTx(ctx, CashWithdrawal, arg)
+}
+
+// cashTx moves money between an account and the bank's cash account, so every deposit and withdrawal is balanced
+// by an entry on the bank's side, then charges the fee and records the movement
+func (store *SQLStore) cashTx(ctx context.Context, kind string, arg CashTxParams) (CashTxResult, error) {
+	var result CashTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if arg.IdempotencyKey != nil {
+			replayed, err := claimIdempotencyKey(ctx, q, *arg.IdempotencyKey, &result)
+			if err != nil || replayed {
+				return err
+			}
+		}
+
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if account.SystemPurpose.Valid {
+			return ErrSystemAccount
+		}
+
+		cashAccount, err := systemAccount(ctx, q, SystemCash, account.Currency)
+		if err != nil {
+			return err
+		}
+
+		transfer := CreateTransferParams{
+			FromAccountID: cashAccount.ID,
+			ToAccountID: account.ID,
+			Amount: arg.Amount,
+			ToAmount: arg.Amount,
+			ExchangeRate: sameCurrencyRate,
+			Memo: arg.Memo,
+			Reference: arg.Reference,
+			Metadata: arg.metadataJSON(),
+			InitiatedBy: initiatedBy(arg.Banker),
+		}
+		if kind == CashWithdrawal {
+			transfer.FromAccountID, transfer.ToAccountID = account.ID, cashAccount.ID
+		}
+
+		result.Transfer, err = transferMoney(ctx, q, transfer)
+		if err != nil {
+			return err
+		}
+
+		movement := CreateCashMovementParams{
+			Kind: kind,
+			AccountID: account.ID,
+			Amount: arg.Amount,
+			Fee: arg.Fee,
+			Banker: arg.Banker,
+			TransferID: result.Transfer.Transfer.ID,
+		}
+
+		if arg.Fee > 0 {
+			feesAccount, err := systemAccount(ctx, q, SystemFees, account.Currency)
+			if err != nil {
+				return err
+			}
+
+			feeTransfer, err := transferMoney(ctx, q, CreateTransferParams{
+				FromAccountID: account.ID,
+				ToAccountID: feesAccount.ID,
+				Amount: arg.Fee,
+				ToAmount: arg.Fee,
+				ExchangeRate: sameCurrencyRate,
+				Memo: fmt.Sprintf("Fee for %s of %s", kind, util.NewMoney(arg.Amount, account.Currency)),
+				Reference: arg.Reference,
+			})
+			if err != nil {
+				return err
+			}
+			result.FeeTransfer = &feeTransfer
+			movement.FeeTransferID = sql.NullInt64{Int64: feeTransfer.Transfer.ID, Valid: true}
+		}
+
+		result.Movement, err = q.CreateCashMovement(ctx, movement)
+		if err != nil {
+			return err
+		}
+
+		if arg.IdempotencyKey != nil {
+			return saveIdempotencyKeyResponse(ctx, q, *arg.IdempotencyKey, result)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccountWithBalance(t, 0)
+	banker := createRandomUser(t)
+
+	result, err := store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount: 500,
+		Fee: 2,
+		Banker: banker.Username,
+		TransferDetails: TransferDetails{Memo: "cash at the counter"},
+	})
+	require.NoError(t, err)
+
+	// the cash comes out of the bank's cash account and the fee goes into its fees account
+	require.Equal(t, SystemCash, result.Transfer.FromAccount.SystemPurpose.String)
+	require.Equal(t, account.ID, result.Transfer.ToAccount.ID)
+	require.Equal(t, "cash at the counter", result.Transfer.Transfer.Memo)
+	require.NotNil(t, result.FeeTransfer)
+	require.Equal(t, SystemFees, result.FeeTransfer.ToAccount.SystemPurpose.String)
+	require.Equal(t, int64(498), result.FeeTransfer.FromAccount.Balance)
+
+	require.Equal(t, CashDeposit, result.Movement.Kind)
+	require.Equal(t, account.ID, result.Movement.AccountID)
+	require.Equal(t, int64(500), result.Movement.Amount)
+	require.Equal(t, int64(2), result.Movement.Fee)
+	require.Equal(t, banker.Username, result.Movement.Banker)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Movement.TransferID)
+	require.Equal(t, result.FeeTransfer.Transfer.ID, result.Movement.FeeTransferID.Int64)
+
+	// every entry is balanced by one on the bank's side
+	var total int64
+	for _, entry := range []Entry{result.Transfer.FromEntry, result.Transfer.ToEntry, result.FeeTransfer.FromEntry, result.FeeTransfer.ToEntry} {
+		total += entry.Amount
+	}
+	require.Zero(t, total)
+
+	// a deposit without a fee makes a single transfer
+	result, err = store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount: 100,
+		Banker: banker.Username,
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.FeeTransfer)
+	require.False(t, result.Movement.FeeTransferID.Valid)
+	require.Equal(t, int64(598), result.Transfer.ToAccount.Balance)
+
+	// cash can't be paid into the bank's own accounts
+	_, err = store.DepositTx(context.Background(), CashTxParams{
+		AccountID: result.Transfer.FromAccount.ID,
+		Amount: 100,
+		Banker: banker.Username,
+	})
+	require.ErrorIs(t, err, ErrSystemAccount)
+}
+
+func TestWithdrawTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccountWithBalance(t, 0)
+	banker := createRandomUser(t)
+
+	_, err := store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount: 100,
+		Banker: banker.Username,
+	})
+	require.NoError(t, err)
+
+	result, err := store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount: 60,
+		Fee: 1,
+		Banker: banker.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, CashWithdrawal, result.Movement.Kind)
+	require.Equal(t, SystemCash, result.Transfer.ToAccount.SystemPurpose.String)
+	require.Equal(t, int64(39), result.FeeTransfer.FromAccount.Balance)
+
+	// the amount and the fee must both be within the account's funds
+	_, err = store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount: 39,
+		Fee: 1,
+		Banker: banker.Username,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	account, err = testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(39), account.Balance)
+
+	// a retry with the same idempotency key pays out only once
+	key := &IdempotencyKeyParams{Username: banker.Username, Key: util.RandomString(12), RequestHash: "withdrawal"}
+	arg := CashTxParams{
+		AccountID: account.ID,
+		Amount: 10,
+		Banker: banker.Username,
+		IdempotencyKey: key,
+	}
+	result1, err := store.WithdrawTx(context.Background(), arg)
+	require.NoError(t, err)
+	result2, err := store.WithdrawTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, result1.Movement.ID, result2.Movement.ID)
+
+	account, err = testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(29), account.Balance)
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// ChangePasswordTxParams contains the input parameters of the change password transaction
+type ChangePasswordTxParams struct {
+	Username string `json:"username"`
+	HashedPassword string `json:"-"`
+	// ChangedAt is taken from the same clock as the access tokens' issue times, which are compared with it
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// ChangePasswordTxResult is the result of the change password transaction
+type ChangePasswordTxResult struct {
+	User User `json:"user"`
+	// BlockedSessions is how many sessions were signed out and can no longer renew their access tokens
+	BlockedSessions int64 `json:"blocked_sessions"`
+}
+
+// ChangePasswordTx sets a user's password and blocks every session they had, all at once
+// so that nobody who knew the old password keeps a session it opened
+func (store *SQLStore) ChangePasswordTx(ctx context.Context, arg ChangePasswordTxParams) (ChangePasswordTxResult, error) {
+	var result ChangePasswordTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.User, err = q.UpdateUserPassword(ctx, UpdateUserPasswordParams{
+			Username: arg.Username,
+			HashedPassword: arg.HashedPassword,
+			PasswordChangedAt: arg.ChangedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.BlockedSessions, err = q.BlockUserSessions(ctx, arg.Username)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// UpsertCurrencyTx adds a currency or changes it
+// It returns ErrCurrencyInUse if the minor units of a currency accounts are held in would change,
+// as their balances and every amount moved in them are stored in the minor unit
+func (store *SQLStore) UpsertCurrencyTx(ctx context.Context, arg UpsertCurrencyParams) (Currency, error) {
+	var result Currency
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// the lock keeps accounts from being opened in the currency until its minor units are changed
+		current, err := q.GetCurrencyForUpdate(ctx, arg.Code)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		if err == nil && current.MinorUnits != arg.MinorUnits {
+			accounts, err := q.CountCurrencyAccounts(ctx, arg.Code)
+			if err != nil {
+				return err
+			}
+			if accounts > 0 {
+				return ErrCurrencyInUse
+			}
+		}
+
+		result, err = q.UpsertCurrency(ctx, arg)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,30 @@
+package db
+
+import (
+	"context"
+)
+
+// UpsertExchangeRatesTxParams contains the input parameters of the upsert exchange rates transaction
+type UpsertExchangeRatesTxParams struct {
+	Rates []UpsertExchangeRateParams `json:"rates"`
+}
+
+// UpsertExchangeRatesTx adds or changes several exchange rates at once
+// Either every rate is stored or none is, so quotes are never made from a mix of old and new rates
+func (store *SQLStore) UpsertExchangeRatesTx(ctx context.Context, arg UpsertExchangeRatesTxParams) ([]ExchangeRate, error) {
+	var result []ExchangeRate
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = make([]ExchangeRate, 0, len(arg.Rates))
+		for _, rate := range arg.Rates {
+			updated, err := q.UpsertExchangeRate(ctx, rate)
+			if err != nil {
+				return err
+			}
+			result = append(result, updated)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertExchangeRatesTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	arg := UpsertExchangeRatesTxParams{
+		Rates: []UpsertExchangeRateParams{
+			{FromCurrency: util.EUR, ToCurrency: util.CAD, Rate: "1.47"},
+			{FromCurrency: util.CAD, ToCurrency: util.EUR, Rate: "0.68"},
+		},
+	}
+	rates, err := store.UpsertExchangeRatesTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	for i, rate := range rates {
+		require.Equal(t, arg.Rates[i].FromCurrency, rate.FromCurrency)
+		require.Equal(t, arg.Rates[i].ToCurrency, rate.ToCurrency)
+		require.Equal(t, arg.Rates[i].Rate, rate.Rate)
+	}
+
+	// a rate that can't be stored keeps the others from being stored too
+	arg.Rates[0].Rate = "1.48"
+	arg.Rates[1].Rate = "0"
+	_, err = store.UpsertExchangeRatesTx(context.Background(), arg)
+	require.Error(t, err)
+
+	rate, err := testQueries.GetExchangeRate(context.Background(), GetExchangeRateParams{
+		FromCurrency: util.EUR,
+		ToCurrency: util.CAD,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "1.47", rate.Rate)
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nokkvi/simplebank/util"
+)
+
+// ExchangeTransferTxParams contains the input parameters of the cross-currency transfer transaction
+type ExchangeTransferTxParams struct {
+	TransferTxParams
+	QuoteID uuid.UUID `json:"quote_id"`
+}
+
+// ExchangeTransferTx transfers money between accounts of different currencies at the rate locked in by a quote
+// The from account is debited the amount in its currency and the to account is credited the converted amount in its own,
+// both amounts and the rate are recorded on the transfer, and the quote can't be used again
+// The transfer limits of the from account and its owner apply to the amount in the from account's currency
+func (store *SQLStore) ExchangeTransferTx(ctx context.Context, arg ExchangeTransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if arg.IdempotencyKey != nil {
+			replayed, err := claimIdempotencyKey(ctx, q, *arg.IdempotencyKey, &result)
+			if err != nil || replayed {
+				return err
+			}
+		}
+
+		quote, err := q.GetExchangeQuoteForUpdate(ctx, arg.QuoteID)
+		if err != nil {
+			return err
+		}
+
+		if quote.IsUsed {
+			return ErrQuoteUsed
+		}
+
+		if time.Now().After(quote.ExpiresAt) {
+			return ErrQuoteExpired
+		}
+
+		toAmount, err := util.ConvertAmount(arg.Amount, quote.FromCurrency, quote.ToCurrency, quote.Rate)
+		if err != nil {
+			return err
+		}
+
+		if toAmount <= 0 {
+			return ErrAmountTooSmall
+		}
+
+		_, err = q.MarkExchangeQuoteUsed(ctx, quote.ID)
+		if err != nil {
+			return err
+		}
+
+		err = lockAndCheckLimits(ctx, q, arg.TransferTxParams)
+		if err != nil {
+			return err
+		}
+
+		result, err = transferMoney(ctx, q, CreateTransferParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID: arg.ToAccountID,
+			Amount: arg.Amount,
+			ToAmount: toAmount,
+			ExchangeRate: quote.Rate,
+			Memo: arg.Memo,
+			Reference: arg.Reference,
+			Metadata: arg.metadataJSON(),
+			InitiatedBy: initiatedBy(arg.InitiatedBy),
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.IdempotencyKey != nil {
+			return saveIdempotencyKeyResponse(ctx, q, *arg.IdempotencyKey, result)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAccountWithCurrency(t *testing.T, currency string, balance int64) Account {
+	user := createRandomUser(t)
+
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner: user.Username,
+		Balance: balance,
+		Currency: currency,
+		Number: sql.NullString{String: util.RandomAccountNumber(), Valid: true},
+	})
+	require.NoError(t, err)
+	return account
+}
+
+func TestExchangeTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithCurrency(t, util.USD, 1000)
+	account2 := createRandomAccountWithCurrency(t, util.EUR, 0)
+	quote := createRandomExchangeQuote(t, account1.Owner, util.USD, util.EUR, time.Minute)
+
+	arg := ExchangeTransferTxParams{
+		TransferTxParams: TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID: account2.ID,
+			Amount: 100,
+		},
+		QuoteID: quote.ID,
+	}
+
+	result, err := store.ExchangeTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// the rate and both amounts are recorded on the transfer
+	require.Equal(t, int64(100), result.Transfer.Amount)
+	require.Equal(t, int64(92), result.Transfer.ToAmount)
+	require.Equal(t, quote.Rate, result.Transfer.ExchangeRate)
+
+	require.Equal(t, int64(-100), result.FromEntry.Amount)
+	require.Equal(t, int64(92), result.ToEntry.Amount)
+
+	require.Equal(t, int64(900), result.FromAccount.Balance)
+	require.Equal(t, int64(92), result.ToAccount.Balance)
+
+	// a quote can only be used once
+	_, err = store.ExchangeTransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrQuoteUsed)
+}
+
+func TestExchangeTransferTxExpiredQuote(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithCurrency(t, util.USD, 1000)
+	account2 := createRandomAccountWithCurrency(t, util.EUR, 0)
+	quote := createRandomExchangeQuote(t, account1.Owner, util.USD, util.EUR, -time.Minute)
+
+	_, err := store.ExchangeTransferTx(context.Background(), ExchangeTransferTxParams{
+		TransferTxParams: TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID: account2.ID,
+			Amount: 100,
+		},
+		QuoteID: quote.ID,
+	})
+	require.ErrorIs(t, err, ErrQuoteExpired)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+}
@@ -0,0 +1,256 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Constants for all statuses of a hold
+const (
+	HoldAuthorized = "authorized"
+	HoldCaptured   = "captured"
+	HoldVoided     = "voided"
+	HoldExpired    = "expired"
+)
+
+// AuthorizeHoldTxParams contains the input parameters of the authorize hold transaction
+type AuthorizeHoldTxParams struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID int64 `json:"to_account_id"`
+	Amount int64 `json:"amount"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// InitiatedBy is the user authorizing the hold, its capture counts towards their user limits
+	InitiatedBy string `json:"initiated_by"`
+	// DefaultLimits are the bank-wide transfer limits by period, for users and accounts without limits of their own
+	DefaultLimits map[string]DefaultTransferLimit `json:"-"`
+}
+
+// HoldTxResult is the result of the authorize and release hold transactions
+type HoldTxResult struct {
+	Hold Hold `json:"hold"`
+	Account Account `json:"account"`
+}
+
+// AuthorizeHoldTx reserves money of an account for a later transfer to another account
+// The money stays in the account but no longer counts towards its available balance until the hold is captured or released
+// The amount has to fit within the transfer limits of the account and of the user authorizing it, as it would if it were transferred now
+// It returns ErrInsufficientFunds if the account's available balance and overdraft don't cover the amount,
+// ErrApprovalRequired if the amount is above the account's approval threshold,
+// an error wrapping ErrTransferLimitExceeded if the amount would go over a transfer limit,
+// and an error wrapping ErrAccountFrozen or ErrAccountClosed if the account isn't active
+func (store *SQLStore) AuthorizeHoldTx(ctx context.Context, arg AuthorizeHoldTxParams) (HoldTxResult, error) {
+	var result HoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := lockAndCheckLimits(ctx, q, TransferTxParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID: arg.ToAccountID,
+			Amount: arg.Amount,
+			InitiatedBy: arg.InitiatedBy,
+			DefaultLimits: arg.DefaultLimits,
+		})
+		if err != nil {
+			return err
+		}
+
+		account, err := q.GetAccountForUpdate(ctx, arg.FromAccountID)
+		if err != nil {
+			return err
+		}
+
+		err = checkAccountsActive(account)
+		if err != nil {
+			return err
+		}
+
+		if NeedsApproval(account, arg.Amount) {
+			return ErrApprovalRequired
+		}
+
+		if availableFunds(account) < arg.Amount {
+			return ErrInsufficientFunds
+		}
+
+		result.Account, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID: arg.FromAccountID,
+			Amount: arg.Amount,
+		})
+		if err != nil {
+			if isConstraintViolation(err, checkViolation, heldAmountWithinBalanceConstraint) {
+				return ErrInsufficientFunds
+			}
+			return err
+		}
+
+		result.Hold, err = q.CreateHold(ctx, CreateHoldParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID: arg.ToAccountID,
+			Amount: arg.Amount,
+			ExpiresAt: arg.ExpiresAt,
+			InitiatedBy: arg.InitiatedBy,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// CaptureHoldTxParams contains the input parameters of the capture hold transaction
+type CaptureHoldTxParams struct {
+	HoldID int64 `json:"hold_id"`
+	// Amount is how much of the hold to transfer, zero transfers all of it
+	Amount int64 `json:"amount"`
+	// DefaultLimits are the bank-wide transfer limits by period, for users and accounts without limits of their own
+	DefaultLimits map[string]DefaultTransferLimit `json:"-"`
+}
+
+// CaptureHoldTxResult is the result of the capture hold transaction
+type CaptureHoldTxResult struct {
+	TransferTxResult
+	Hold Hold `json:"hold"`
+}
+
+// CaptureHoldTx settles an authorized hold with a transfer of all or part of its amount
+// The whole hold is released, so whatever isn't captured becomes available again
+// It returns ErrHoldNotAuthorized if the hold was already captured or released, ErrHoldExpired if it is past its expiry,
+// ErrCaptureExceedsHold if more would be captured than was held,
+// an error wrapping ErrTransferLimitExceeded if the capture would go over a transfer limit of the account or of the user who authorized the hold,
+// and ErrApprovalRequired if the account's approval threshold was lowered below the captured amount since the hold was authorized
+func (store *SQLStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := q.GetHold(ctx, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		// lock the user who authorized the hold and the accounts before the hold, in the same order as transfers and the other hold transactions
+		err = lockUsers(ctx, q, hold.InitiatedBy)
+		if err != nil {
+			return err
+		}
+
+		fromAccount, _, err := lockAccounts(ctx, q, hold.FromAccountID, hold.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		hold, err = lockAuthorizedHold(ctx, q, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		if !hold.ExpiresAt.After(time.Now()) {
+			return ErrHoldExpired
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount > hold.Amount {
+			return ErrCaptureExceedsHold
+		}
+
+		if NeedsApproval(fromAccount, amount) {
+			return ErrApprovalRequired
+		}
+
+		err = checkTransferLimits(ctx, q, fromAccount, hold.InitiatedBy, amount, arg.DefaultLimits)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID: hold.FromAccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = transferMoney(ctx, q, CreateTransferParams{
+			FromAccountID: hold.FromAccountID,
+			ToAccountID: hold.ToAccountID,
+			Amount: amount,
+			ToAmount: amount,
+			ExchangeRate: sameCurrencyRate,
+			InitiatedBy: initiatedBy(hold.InitiatedBy),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.CaptureHold(ctx, CaptureHoldParams{
+			ID: hold.ID,
+			CapturedAmount: amount,
+			TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ReleaseHoldTxParams contains the input parameters of the release hold transaction
+type ReleaseHoldTxParams struct {
+	HoldID int64 `json:"hold_id"`
+	// Status is what the hold ends up as, HoldVoided or HoldExpired
+	Status string `json:"status"`
+}
+
+// ReleaseHoldTx ends an authorized hold without transferring anything, making its amount available again
+// It returns ErrHoldNotAuthorized if the hold was already captured or released
+func (store *SQLStore) ReleaseHoldTx(ctx context.Context, arg ReleaseHoldTxParams) (HoldTxResult, error) {
+	var result HoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := q.GetHold(ctx, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.GetAccountForUpdate(ctx, hold.FromAccountID)
+		if err != nil {
+			return err
+		}
+
+		hold, err = lockAuthorizedHold(ctx, q, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID: hold.FromAccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.UpdateHoldStatus(ctx, UpdateHoldStatusParams{
+			ID: hold.ID,
+			Status: arg.Status,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// lockAuthorizedHold locks the hold for update and returns it, or ErrHoldNotAuthorized if it was already captured or released
+// The hold's from account must already be locked, so the hold can't change between reading it and locking it
+func lockAuthorizedHold(ctx context.Context, q *Queries, holdID int64) (Hold, error) {
+	hold, err := q.GetHoldForUpdate(ctx, holdID)
+	if err != nil {
+		return hold, err
+	}
+
+	if hold.Status != HoldAuthorized {
+		return hold, ErrHoldNotAuthorized
+	}
+
+	return hold, nil
+}
@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizeHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	result, err := store.AuthorizeHoldTx(context.Background(), AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 400,
+		ExpiresAt: time.Now().Add(time.Hour),
+		InitiatedBy: account1.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldAuthorized, result.Hold.Status)
+	require.Equal(t, int64(400), result.Hold.Amount)
+	require.Equal(t, account1.Owner, result.Hold.InitiatedBy)
+
+	// the money stays in the account but is no longer available
+	require.Equal(t, int64(1000), result.Account.Balance)
+	require.Equal(t, int64(400), result.Account.HeldAmount)
+	require.Equal(t, int64(600), result.Account.AvailableBalance)
+
+	// neither holds nor transfers can use held money
+	_, err = store.AuthorizeHoldTx(context.Background(), AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 601,
+		ExpiresAt: time.Now().Add(time.Hour),
+		InitiatedBy: account1.Owner,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 601,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 600,
+	})
+	require.NoError(t, err)
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	authorized, err := store.AuthorizeHoldTx(context.Background(), AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 400,
+		ExpiresAt: time.Now().Add(time.Hour),
+		InitiatedBy: account1.Owner,
+	})
+	require.NoError(t, err)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: authorized.Hold.ID,
+		Amount: 401,
+	})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	// capture part of the hold, the rest becomes available again
+	result, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: authorized.Hold.ID,
+		Amount: 300,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, HoldCaptured, result.Hold.Status)
+	require.Equal(t, int64(300), result.Hold.CapturedAmount)
+	require.True(t, result.Hold.TransferID.Valid)
+	require.Equal(t, result.Transfer.ID, result.Hold.TransferID.Int64)
+
+	require.Equal(t, account1.ID, result.Transfer.FromAccountID)
+	require.Equal(t, account2.ID, result.Transfer.ToAccountID)
+	require.Equal(t, int64(300), result.Transfer.Amount)
+
+	require.Equal(t, int64(700), result.FromAccount.Balance)
+	require.Zero(t, result.FromAccount.HeldAmount)
+	require.Equal(t, int64(700), result.FromAccount.AvailableBalance)
+	require.Equal(t, account2.Balance+300, result.ToAccount.Balance)
+
+	// a hold is only captured once
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: authorized.Hold.ID,
+	})
+	require.ErrorIs(t, err, ErrHoldNotAuthorized)
+}
+
+func TestCaptureExpiredHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	authorized, err := store.AuthorizeHoldTx(context.Background(), AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 400,
+		ExpiresAt: time.Now().Add(-time.Second),
+		InitiatedBy: account1.Owner,
+	})
+	require.NoError(t, err)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: authorized.Hold.ID,
+	})
+	require.ErrorIs(t, err, ErrHoldExpired)
+
+	result, err := store.ReleaseHoldTx(context.Background(), ReleaseHoldTxParams{
+		HoldID: authorized.Hold.ID,
+		Status: HoldExpired,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldExpired, result.Hold.Status)
+	require.Equal(t, int64(1000), result.Account.AvailableBalance)
+}
+
+func TestHoldApprovalThreshold(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	_, err := testQueries.UpdateAccountApprovalThreshold(context.Background(), UpdateAccountApprovalThresholdParams{
+		ID: account1.ID,
+		ApprovalThreshold: sql.NullInt64{Int64: 500, Valid: true},
+	})
+	require.NoError(t, err)
+
+	// a hold above the threshold is never authorized
+	_, err = store.AuthorizeHoldTx(context.Background(), AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 501,
+		ExpiresAt: time.Now().Add(time.Hour),
+		InitiatedBy: account1.Owner,
+	})
+	require.ErrorIs(t, err, ErrApprovalRequired)
+
+	authorized, err := store.AuthorizeHoldTx(context.Background(), AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 400,
+		ExpiresAt: time.Now().Add(time.Hour),
+		InitiatedBy: account1.Owner,
+	})
+	require.NoError(t, err)
+
+	// lowering the threshold afterwards stops the hold from being captured above it
+	_, err = testQueries.UpdateAccountApprovalThreshold(context.Background(), UpdateAccountApprovalThresholdParams{
+		ID: account1.ID,
+		ApprovalThreshold: sql.NullInt64{Int64: 300, Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: authorized.Hold.ID,
+	})
+	require.ErrorIs(t, err, ErrApprovalRequired)
+
+	result, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: authorized.Hold.ID,
+		Amount: 300,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(300), result.Transfer.Amount)
+}
+
+func TestReleaseHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	authorized, err := store.AuthorizeHoldTx(context.Background(), AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 400,
+		ExpiresAt: time.Now().Add(time.Hour),
+		InitiatedBy: account1.Owner,
+	})
+	require.NoError(t, err)
+
+	result, err := store.ReleaseHoldTx(context.Background(), ReleaseHoldTxParams{
+		HoldID: authorized.Hold.ID,
+		Status: HoldVoided,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldVoided, result.Hold.Status)
+	require.Zero(t, result.Hold.CapturedAmount)
+	require.Equal(t, int64(1000), result.Account.Balance)
+	require.Zero(t, result.Account.HeldAmount)
+	require.Equal(t, int64(1000), result.Account.AvailableBalance)
+
+	_, err = store.ReleaseHoldTx(context.Background(), ReleaseHoldTxParams{
+		HoldID: authorized.Hold.ID,
+		Status: HoldVoided,
+	})
+	require.ErrorIs(t, err, ErrHoldNotAuthorized)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: authorized.Hold.ID,
+	})
+	require.ErrorIs(t, err, ErrHoldNotAuthorized)
+}
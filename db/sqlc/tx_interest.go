@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nokkvi/simplebank/util"
+)
+
+// AccrueInterestTxParams contains the input parameters of the accrue interest transaction
+type AccrueInterestTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Date is the day interest is accrued for, at midnight UTC
+	Date time.Time `json:"date"`
+}
+
+// AccrueInterestTx records the interest an account earned over a day, on its balance at the end of the day
+// and at the rate and day count convention of its product
+// A day is only ever accrued once, accruing it again returns the accrual recorded the first time
+// It returns ErrNoInterest if the account has no product
+func (store *SQLStore) AccrueInterestTx(ctx context.Context, arg AccrueInterestTxParams) (InterestAccrual, error) {
+	var result InterestAccrual
+
+	// the balance is worked out from snapshots and entries read from the same snapshot
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead}
+	err := store.execTxWithOptions(ctx, opts, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if !account.Product.Valid {
+			return ErrNoInterest
+		}
+
+		product, err := q.GetAccountProduct(ctx, account.Product.String)
+		if err != nil {
+			return err
+		}
+
+		balance, err := balanceAt(ctx, q, account, arg.Date.AddDate(0, 0, 1))
+		if err != nil {
+			return err
+		}
+
+		interest, err := util.DailyInterest(balance, product.AnnualInterestRate, product.DayCountConvention, arg.Date)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID: account.ID,
+			AccrualDate: arg.Date,
+			Balance: balance,
+			AnnualInterestRate: product.AnnualInterestRate,
+			DayCountConvention: product.DayCountConvention,
+			Amount: util.FormatInterest(interest),
+		})
+		if err == sql.ErrNoRows {
+			result, err = q.GetInterestAccrual(ctx, GetInterestAccrualParams{
+				AccountID: account.ID,
+				AccrualDate: arg.Date,
+			})
+		}
+		return err
+	})
+
+	return result, err
+}
+
+// CapitalizeInterestTxParams contains the input parameters of the capitalize interest transaction
+type CapitalizeInterestTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Period is the first day of the month the interest is paid for, at midnight UTC
+	Period time.Time `json:"period"`
+}
+
+// CapitalizeInterestTxResult is the result of the capitalize interest transaction
+type CapitalizeInterestTxResult struct {
+	Capitalization InterestCapitalization `json:"capitalization"`
+	// Transfer is nil when the interest came to less than a minor unit and there was nothing to pay
+	Transfer *TransferTxResult `json:"transfer"`
+}
+
+// CapitalizeInterestTx pays the interest accrued until the end of a month into the account, from the bank's interest account
+// Only whole minor units are paid, the fractions left over carry on to the next month
+// It returns ErrInterestAlreadyCapitalized if the month was already paid, and an error wrapping ErrAccountFrozen or ErrAccountClosed
+// if the account isn't active, in which case the month can be paid later
+func (store *SQLStore) CapitalizeInterestTx(ctx context.Context, arg CapitalizeInterestTxParams) (CapitalizeInterestTxResult, error) {
+	var result CapitalizeInterestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// claiming the period first makes a concurrent capitalization of the same month wait for this one and then fail
+		_, err := q.CreateInterestCapitalization(ctx, CreateInterestCapitalizationParams{
+			AccountID: arg.AccountID,
+			Period: arg.Period,
+		})
+		if err == sql.ErrNoRows {
+			return ErrInterestAlreadyCapitalized
+		}
+		if err != nil {
+			return err
+		}
+
+		accrued, err := q.GetAccruedInterestBefore(ctx, GetAccruedInterestBeforeParams{
+			AccountID: arg.AccountID,
+			Before: arg.Period.AddDate(0, 1, 0),
+		})
+		if err != nil {
+			return err
+		}
+
+		capitalized, err := q.GetCapitalizedInterest(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		update := UpdateInterestCapitalizationParams{
+			AccountID: arg.AccountID,
+			Period: arg.Period,
+			Amount: accrued - capitalized,
+		}
+
+		if update.Amount > 0 {
+			account, err := q.GetAccount(ctx, arg.AccountID)
+			if err != nil {
+				return err
+			}
+
+			interestAccount, err := systemAccount(ctx, q, SystemInterest, account.Currency)
+			if err != nil {
+				return err
+			}
+
+			month := arg.Period.Format("2006-01")
+			transfer, err := transferMoney(ctx, q, CreateTransferParams{
+				FromAccountID: interestAccount.ID,
+				ToAccountID: account.ID,
+				Amount: update.Amount,
+				ToAmount: update.Amount,
+				ExchangeRate: sameCurrencyRate,
+				Memo: fmt.Sprintf("Interest for %s", month),
+				Reference: fmt.Sprintf("interest-%s", month),
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Transfer = &transfer
+			update.TransferID = sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true}
+		} else {
+			update.Amount = 0
+		}
+
+		result.Capitalization, err = q.UpdateInterestCapitalization(ctx, update)
+		return err
+	})
+
+	return result, err
+}
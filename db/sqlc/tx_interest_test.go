@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAccountProduct(t *testing.T, annualInterestRate string) AccountProduct {
+	arg := UpsertAccountProductParams{
+		Code: util.RandomOwner(),
+		Name: util.RandomOwner(),
+		AnnualInterestRate: annualInterestRate,
+		DayCountConvention: util.DayCountActual365,
+		MaxAccounts: 1,
+		OverdraftAnnualRate: "0",
+	}
+
+	product, err := testQueries.UpsertAccountProduct(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Code, product.Code)
+	require.Equal(t, arg.DayCountConvention, product.DayCountConvention)
+	require.Equal(t, arg.MaxAccounts, product.MaxAccounts)
+	return product
+}
+
+func createRandomInterestAccount(t *testing.T, product AccountProduct, balance int64) Account {
+	user := createRandomUser(t)
+
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner: user.Username,
+		Balance: balance,
+		Currency: util.RandomCurrency(),
+		Product: sql.NullString{String: product.Code, Valid: true},
+		Number: sql.NullString{String: util.RandomAccountNumber(), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, product.Code, account.Product.String)
+	return account
+}
+
+func TestAccrueInterestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	// 36.5% a year is 0.1% a day under act/365
+	product := createRandomAccountProduct(t, "0.365")
+	account := createRandomInterestAccount(t, product, 1500)
+	day := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	accrual, err := store.AccrueInterestTx(context.Background(), AccrueInterestTxParams{
+		AccountID: account.ID,
+		Date: day,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, accrual.AccountID)
+	require.True(t, day.Equal(accrual.AccrualDate))
+	require.Equal(t, int64(1500), accrual.Balance)
+	require.Equal(t, product.DayCountConvention, accrual.DayCountConvention)
+	require.Equal(t, "1.5000000000", accrual.Amount)
+
+	// a day is accrued once, at the balance it was first accrued on
+	_, err = testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID: account.ID,
+		Balance: 3000,
+	})
+	require.NoError(t, err)
+
+	accrual2, err := store.AccrueInterestTx(context.Background(), AccrueInterestTxParams{
+		AccountID: account.ID,
+		Date: day,
+	})
+	require.NoError(t, err)
+	require.Equal(t, accrual.Amount, accrual2.Amount)
+	require.Equal(t, accrual.Balance, accrual2.Balance)
+
+	// accounts without a product earn nothing
+	_, err = store.AccrueInterestTx(context.Background(), AccrueInterestTxParams{
+		AccountID: createRandomAccount(t).ID,
+		Date: day,
+	})
+	require.ErrorIs(t, err, ErrNoInterest)
+}
+
+func TestCapitalizeInterestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	product := createRandomAccountProduct(t, "0.365")
+	account := createRandomInterestAccount(t, product, 1500)
+	january := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	february := january.AddDate(0, 1, 0)
+
+	for _, day := range []time.Time{january, january.AddDate(0, 0, 1), january.AddDate(0, 0, 2), february} {
+		_, err := store.AccrueInterestTx(context.Background(), AccrueInterestTxParams{
+			AccountID: account.ID,
+			Date: day,
+		})
+		require.NoError(t, err)
+	}
+
+	// 4.5 was accrued in January, the half carries on to February
+	result, err := store.CapitalizeInterestTx(context.Background(), CapitalizeInterestTxParams{
+		AccountID: account.ID,
+		Period: january,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), result.Capitalization.Amount)
+	require.NotNil(t, result.Transfer)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Capitalization.TransferID.Int64)
+	require.Equal(t, account.ID, result.Transfer.ToAccount.ID)
+	require.Equal(t, int64(1504), result.Transfer.ToAccount.Balance)
+	require.Equal(t, "interest-2020-01", result.Transfer.Transfer.Reference)
+
+	interestAccount := result.Transfer.FromAccount
+	require.Equal(t, SystemUsername, interestAccount.Owner)
+	require.Equal(t, SystemInterest, interestAccount.SystemPurpose.String)
+	require.Equal(t, account.Currency, interestAccount.Currency)
+
+	_, err = store.CapitalizeInterestTx(context.Background(), CapitalizeInterestTxParams{
+		AccountID: account.ID,
+		Period: january,
+	})
+	require.ErrorIs(t, err, ErrInterestAlreadyCapitalized)
+
+	result, err = store.CapitalizeInterestTx(context.Background(), CapitalizeInterestTxParams{
+		AccountID: account.ID,
+		Period: february,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), result.Capitalization.Amount)
+	require.NotNil(t, result.Transfer)
+
+	// the same interest account pays every account of the currency, and may go negative
+	require.Equal(t, interestAccount.ID, result.Transfer.FromAccount.ID)
+	require.Equal(t, interestAccount.Balance-2, result.Transfer.FromAccount.Balance)
+	require.Negative(t, result.Transfer.FromAccount.Balance)
+}
@@ -0,0 +1,181 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nokkvi/simplebank/util"
+)
+
+// Constants for all kinds of notifications sent to account owners
+const (
+	NotificationOverdraftStarted = "overdraft_started"
+	NotificationOverdraftCharged = "overdraft_charged"
+)
+
+// availableFunds is what can still be taken out of an account: its available balance and whatever is left of its overdraft
+func availableFunds(account Account) int64 {
+	return account.AvailableBalance + account.OverdraftLimit
+}
+
+// SetOverdraftTxParams contains the input parameters of the set overdraft transaction
+type SetOverdraftTxParams struct {
+	AccountID int64 `json:"account_id"`
+	Limit int64 `json:"limit"`
+	AnnualRate string `json:"annual_rate"`
+	DailyFee int64 `json:"daily_fee"`
+}
+
+// SetOverdraftTx sets how far below zero an account may go and what it is charged for every day it ends overdrawn
+// A limit of zero takes the overdraft away
+// It returns ErrOverdraftInUse if the account already uses more of its overdraft than the new limit
+func (store *SQLStore) SetOverdraftTx(ctx context.Context, arg SetOverdraftTxParams) (Account, error) {
+	var result Account
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.UpdateAccountOverdraft(ctx, UpdateAccountOverdraftParams{
+			ID: arg.AccountID,
+			OverdraftLimit: arg.Limit,
+			OverdraftAnnualRate: arg.AnnualRate,
+			OverdraftDailyFee: arg.DailyFee,
+		})
+		if isConstraintViolation(err, checkViolation, balanceWithinOverdraftConstraint) || isConstraintViolation(err, checkViolation, heldAmountWithinBalanceConstraint) {
+			return ErrOverdraftInUse
+		}
+		return err
+	})
+
+	return result, err
+}
+
+// ChargeOverdraftTxParams contains the input parameters of the charge overdraft transaction
+type ChargeOverdraftTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Date is the day the overdraft is charged for, at midnight UTC
+	Date time.Time `json:"date"`
+}
+
+// ChargeOverdraftTxResult is the result of the charge overdraft transaction
+type ChargeOverdraftTxResult struct {
+	Charge OverdraftCharge `json:"charge"`
+	// Transfer is nil when there was nothing to charge
+	Transfer *TransferTxResult `json:"transfer"`
+}
+
+// ChargeOverdraftTx charges an account that ended a day overdrawn the daily fee and a day of interest on the overdrawn balance,
+// paid into the bank's overdraft account
+// The interest is rounded up to a whole minor unit, and what would take the account past its overdraft limit is waived
+// It returns ErrOverdraftAlreadyCharged if the day was already charged, and an error wrapping ErrAccountFrozen or ErrAccountClosed
+// if the account isn't active, in which case the day can be charged later
+func (store *SQLStore) ChargeOverdraftTx(ctx context.Context, arg ChargeOverdraftTxParams) (ChargeOverdraftTxResult, error) {
+	var result ChargeOverdraftTxResult
+
+	// the balance is worked out from snapshots and entries read from the same snapshot
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead}
+	err := store.execTxWithOptions(ctx, opts, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		balance, err := balanceAt(ctx, q, account, arg.Date.AddDate(0, 0, 1))
+		if err != nil {
+			return err
+		}
+
+		var interest, fee int64
+		if balance < 0 {
+			dailyInterest, err := util.DailyInterest(-balance, account.OverdraftAnnualRate, util.DayCountActual365, arg.Date)
+			if err != nil {
+				return err
+			}
+			interest, fee = util.RoundUpInterest(dailyInterest), account.OverdraftDailyFee
+		}
+
+		var overdraftAccount Account
+		var charged int64
+		if interest+fee > 0 {
+			overdraftAccount, err = systemAccount(ctx, q, SystemOverdraft, account.Currency)
+			if err != nil {
+				return err
+			}
+
+			account, _, err = lockAccounts(ctx, q, account.ID, overdraftAccount.ID)
+			if err != nil {
+				return err
+			}
+
+			charged = interest + fee
+			if funds := availableFunds(account); charged > funds {
+				charged = funds
+			}
+			if charged < 0 {
+				charged = 0
+			}
+		}
+
+		result.Charge, err = q.CreateOverdraftCharge(ctx, CreateOverdraftChargeParams{
+			AccountID: account.ID,
+			ChargeDate: arg.Date,
+			Balance: balance,
+			AnnualRate: account.OverdraftAnnualRate,
+			Interest: interest,
+			Fee: fee,
+			Waived: interest + fee - charged,
+		})
+		if err == sql.ErrNoRows {
+			return ErrOverdraftAlreadyCharged
+		}
+		if err != nil || charged == 0 {
+			return err
+		}
+
+		day := arg.Date.Format("2006-01-02")
+		transfer, err := transferMoney(ctx, q, CreateTransferParams{
+			FromAccountID: account.ID,
+			ToAccountID: overdraftAccount.ID,
+			Amount: charged,
+			ToAmount: charged,
+			ExchangeRate: sameCurrencyRate,
+			Memo: fmt.Sprintf("Overdraft interest and fees for %s", day),
+			Reference: fmt.Sprintf("overdraft-%s", day),
+		})
+		if err != nil {
+			return err
+		}
+		result.Transfer = &transfer
+
+		result.Charge, err = q.UpdateOverdraftChargeTransfer(ctx, UpdateOverdraftChargeTransferParams{
+			AccountID: account.ID,
+			ChargeDate: arg.Date,
+			TransferID: sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateNotification(ctx, CreateNotificationParams{
+			Username: account.Owner,
+			AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+			Kind: NotificationOverdraftCharged,
+			Message: fmt.Sprintf("account %d was charged %s for its overdraft on %s", account.ID, util.NewMoney(charged, account.Currency), day),
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// notifyOverdraftStarted lets the owner of an account that just went below zero know it is using its overdraft
+func notifyOverdraftStarted(ctx context.Context, q *Queries, account Account) error {
+	_, err := q.CreateNotification(ctx, CreateNotificationParams{
+		Username: account.Owner,
+		AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+		Kind: NotificationOverdraftStarted,
+		Message: fmt.Sprintf("account %d is using its overdraft, its balance is %s", account.ID, util.NewMoney(account.Balance, account.Currency)),
+	})
+	return err
+}
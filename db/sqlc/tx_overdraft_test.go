@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetOverdraftTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccountWithBalance(t, 100)
+
+	account, err := store.SetOverdraftTx(context.Background(), SetOverdraftTxParams{
+		AccountID: account.ID,
+		Limit: 500,
+		AnnualRate: "0.2",
+		DailyFee: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(500), account.OverdraftLimit)
+	require.Equal(t, "0.2", account.OverdraftAnnualRate)
+	require.Equal(t, int64(10), account.OverdraftDailyFee)
+
+	// the overdraft counts towards the funds a transfer can take
+	account2 := createRandomAccount(t)
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID: account2.ID,
+		Amount: 400,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-300), result.FromAccount.Balance)
+
+	notifications, err := testQueries.ListNotifications(context.Background(), ListNotificationsParams{
+		Username: account.Owner,
+		Limit: 5,
+	})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	require.Equal(t, NotificationOverdraftStarted, notifications[0].Kind)
+	require.Equal(t, account.ID, notifications[0].AccountID.Int64)
+
+	// but not past the limit
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID: account2.ID,
+		Amount: 201,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// and the limit can't go below what is used of it
+	_, err = store.SetOverdraftTx(context.Background(), SetOverdraftTxParams{
+		AccountID: account.ID,
+		Limit: 200,
+		AnnualRate: "0.2",
+	})
+	require.ErrorIs(t, err, ErrOverdraftInUse)
+}
+
+func TestChargeOverdraftTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	// 36.5% a year is 0.1% a day
+	account := createRandomAccountWithBalance(t, 0)
+	account, err := store.SetOverdraftTx(context.Background(), SetOverdraftTxParams{
+		AccountID: account.ID,
+		Limit: 1520,
+		AnnualRate: "0.365",
+		DailyFee: 10,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID: createRandomAccount(t).ID,
+		Amount: 1500,
+	})
+	require.NoError(t, err)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+
+	// the account ended yesterday with nothing in it, so there is nothing to charge
+	result, err := store.ChargeOverdraftTx(context.Background(), ChargeOverdraftTxParams{
+		AccountID: account.ID,
+		Date: yesterday,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Charge.Interest)
+	require.Zero(t, result.Charge.Fee)
+	require.Nil(t, result.Transfer)
+
+	// today it ends 1500 overdrawn, the 1.5 interest is rounded up and comes to 12 with the fee
+	result, err = store.ChargeOverdraftTx(context.Background(), ChargeOverdraftTxParams{
+		AccountID: account.ID,
+		Date: today,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-1500), result.Charge.Balance)
+	require.Equal(t, int64(2), result.Charge.Interest)
+	require.Equal(t, int64(10), result.Charge.Fee)
+	require.Zero(t, result.Charge.Waived)
+	require.NotNil(t, result.Transfer)
+	require.Equal(t, int64(-1512), result.Transfer.FromAccount.Balance)
+	require.Equal(t, SystemOverdraft, result.Transfer.ToAccount.SystemPurpose.String)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Charge.TransferID.Int64)
+
+	// the owner is told what was charged in the major unit of the currency
+	notifications, err := testQueries.ListNotifications(context.Background(), ListNotificationsParams{
+		Username: account.Owner,
+		Limit: 5,
+	})
+	require.NoError(t, err)
+	var charged []Notification
+	for _, notification := range notifications {
+		if notification.Kind == NotificationOverdraftCharged {
+			charged = append(charged, notification)
+		}
+	}
+	require.Len(t, charged, 1)
+	require.Contains(t, charged[0].Message, "was charged 0.12 "+account.Currency)
+
+	_, err = store.ChargeOverdraftTx(context.Background(), ChargeOverdraftTxParams{
+		AccountID: account.ID,
+		Date: today,
+	})
+	require.ErrorIs(t, err, ErrOverdraftAlreadyCharged)
+
+	// with 8 of the overdraft left, what would go past the limit is waived
+	result, err = store.ChargeOverdraftTx(context.Background(), ChargeOverdraftTxParams{
+		AccountID: account.ID,
+		Date: today.AddDate(0, 0, 1),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), result.Charge.Interest)
+	require.Equal(t, int64(10), result.Charge.Fee)
+	require.Equal(t, int64(4), result.Charge.Waived)
+	require.Equal(t, int64(-1520), result.Transfer.FromAccount.Balance)
+}
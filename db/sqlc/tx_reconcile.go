@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// ReconciliationReport lists everything in the ledger that doesn't add up
+type ReconciliationReport struct {
+	CheckedAt time.Time `json:"checked_at"`
+	AccountsChecked int64 `json:"accounts_checked"`
+	TransfersChecked int64 `json:"transfers_checked"`
+	// BalanceMismatches are the accounts whose balance isn't the sum of their entries
+	BalanceMismatches []ListAccountBalanceMismatchesRow `json:"balance_mismatches"`
+	// HeldAmountMismatches are the accounts whose held amount isn't the sum of their authorized holds
+	HeldAmountMismatches []ListAccountHeldAmountMismatchesRow `json:"held_amount_mismatches"`
+	// TransferMismatches are the transfers without exactly one debit of their amount from the from account
+	// and one credit of their to amount to the to account
+	TransferMismatches []ListTransferEntryMismatchesRow `json:"transfer_mismatches"`
+	// OrphanEntries are the entries that no transfer made
+	OrphanEntries []Entry `json:"orphan_entries"`
+	// LedgerImbalances are the currencies whose entries don't add up to zero across the bank
+	// Exchange transfers are left out, each side only adds up in its own currency
+	LedgerImbalances []ListLedgerImbalancesRow `json:"ledger_imbalances"`
+}
+
+// Discrepancies is how many discrepancies the reconciliation found
+func (report ReconciliationReport) Discrepancies() int {
+	return len(report.BalanceMismatches) + len(report.HeldAmountMismatches) + len(report.TransferMismatches) + len(report.OrphanEntries) + len(report.LedgerImbalances)
+}
+
+// ReconcileTx checks every account against its entries and holds, every transfer against its entries,
+// and that the entries of each currency add up to zero
+// Everything is read from the same snapshot, so transfers made while it runs can't show up as discrepancies
+// It only reads, the discrepancies are left for someone to look into
+func (store *SQLStore) ReconcileTx(ctx context.Context) (ReconciliationReport, error) {
+	var result ReconciliationReport
+
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := store.execTxWithOptions(ctx, opts, func(q *Queries) error {
+		report := ReconciliationReport{CheckedAt: time.Now().UTC()}
+
+		var err error
+		report.AccountsChecked, err = q.CountAccounts(ctx)
+		if err != nil {
+			return err
+		}
+
+		report.TransfersChecked, err = q.CountTransfers(ctx)
+		if err != nil {
+			return err
+		}
+
+		report.BalanceMismatches, err = q.ListAccountBalanceMismatches(ctx)
+		if err != nil {
+			return err
+		}
+
+		report.HeldAmountMismatches, err = q.ListAccountHeldAmountMismatches(ctx)
+		if err != nil {
+			return err
+		}
+
+		report.TransferMismatches, err = q.ListTransferEntryMismatches(ctx)
+		if err != nil {
+			return err
+		}
+
+		report.OrphanEntries, err = q.ListOrphanEntries(ctx)
+		if err != nil {
+			return err
+		}
+
+		report.LedgerImbalances, err = q.ListLedgerImbalances(ctx)
+		if err != nil {
+			return err
+		}
+
+		result = report
+		return nil
+	})
+
+	return result, err
+}
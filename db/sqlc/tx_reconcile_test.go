@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 0)
+	account2 := createRandomAccountWithBalance(t, 0)
+
+	_, err := testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID: account1.ID,
+		Amount: 100,
+	})
+	require.NoError(t, err)
+
+	// an entry without a transfer brings the balance in line, but is itself a discrepancy
+	orphan, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: account1.ID,
+		Amount: 100,
+		Metadata: emptyMetadata,
+	})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 60,
+	})
+	require.NoError(t, err)
+
+	// a transfer that moved no money
+	transfer := createRandomTransfer(t, account2, account1)
+
+	// and a balance that was changed without an entry
+	_, err = testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID: account2.ID,
+		Amount: 5,
+	})
+	require.NoError(t, err)
+
+	report, err := store.ReconcileTx(context.Background())
+	require.NoError(t, err)
+	require.NotZero(t, report.AccountsChecked)
+	require.NotZero(t, report.TransfersChecked)
+	require.NotZero(t, report.Discrepancies())
+
+	balanceMismatches := make(map[int64]ListAccountBalanceMismatchesRow)
+	for _, mismatch := range report.BalanceMismatches {
+		balanceMismatches[mismatch.AccountID] = mismatch
+	}
+	require.NotContains(t, balanceMismatches, account1.ID)
+	require.Contains(t, balanceMismatches, account2.ID)
+	require.Equal(t, int64(65), balanceMismatches[account2.ID].Balance)
+	require.Equal(t, int64(60), balanceMismatches[account2.ID].EntriesTotal)
+
+	transferMismatches := make(map[int64]ListTransferEntryMismatchesRow)
+	for _, mismatch := range report.TransferMismatches {
+		transferMismatches[mismatch.TransferID] = mismatch
+	}
+	require.NotContains(t, transferMismatches, result.Transfer.ID)
+	require.Contains(t, transferMismatches, transfer.ID)
+	require.Zero(t, transferMismatches[transfer.ID].EntryCount)
+
+	orphanIDs := make([]int64, len(report.OrphanEntries))
+	for i, entry := range report.OrphanEntries {
+		orphanIDs[i] = entry.ID
+	}
+	require.Contains(t, orphanIDs, orphan.ID)
+	require.NotContains(t, orphanIDs, result.FromEntry.ID)
+
+	// the orphan entry has nothing on the other side to balance it
+	imbalances := make([]string, len(report.LedgerImbalances))
+	for i, imbalance := range report.LedgerImbalances {
+		imbalances[i] = imbalance.Currency
+	}
+	require.Contains(t, imbalances, account1.Currency)
+}
+
+func TestReconciliationReportDiscrepancies(t *testing.T) {
+	var report ReconciliationReport
+	require.Zero(t, report.Discrepancies())
+
+	report.BalanceMismatches = []ListAccountBalanceMismatchesRow{{AccountID: 1}}
+	report.TransferMismatches = []ListTransferEntryMismatchesRow{{TransferID: 1}, {TransferID: 2}}
+	report.OrphanEntries = []Entry{{ID: 1}}
+	report.LedgerImbalances = []ListLedgerImbalancesRow{{Currency: "USD", EntriesTotal: 100}}
+	require.Equal(t, 5, report.Discrepancies())
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"math/rand"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Limits of the retries of transactions that fail with a retryable error
+const (
+	maxTxAttempts    = 5
+	txRetryBaseDelay = 10 * time.Millisecond
+	txRetryMaxDelay  = 200 * time.Millisecond
+)
+
+// retryableConditions are the postgres errors after which running the whole transaction again can succeed
+var retryableConditions = map[pq.ErrorCode]bool{
+	"40P01": true, // deadlock_detected
+	"40001": true, // serialization_failure
+}
+
+// Counters of transaction retries, published at /debug/vars
+var (
+	// txRetries counts the retries by the name of the condition that caused them
+	txRetries = expvar.NewMap("db_tx_retries")
+	// txRetriesExhausted counts the transactions that still failed with a retryable error after their last attempt
+	txRetriesExhausted = expvar.NewInt("db_tx_retries_exhausted")
+)
+
+// retryableCondition returns the name of the postgres condition behind err if running the transaction again can succeed
+func retryableCondition(err error) (string, bool) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || !retryableConditions[pqErr.Code] {
+		return "", false
+	}
+
+	return pqErr.Code.Name(), true
+}
+
+// txRetryDelay returns how long to wait before the given retry, counting from 1
+// The delay doubles with every retry up to txRetryMaxDelay, and a random half of it is dropped
+// so that transactions which failed on each other don't retry in lockstep
+func txRetryDelay(retry int) time.Duration {
+	delay := txRetryBaseDelay << (retry - 1)
+	if delay <= 0 || delay > txRetryMaxDelay {
+		delay = txRetryMaxDelay
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// waitForRetry waits for the delay of the given retry and reports whether the transaction should run again
+// It doesn't wait when the context would be done before the retry could start
+func waitForRetry(ctx context.Context, retry int) bool {
+	delay := txRetryDelay(retry)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryableCondition(t *testing.T) {
+	condition, ok := retryableCondition(&pq.Error{Code: "40P01"})
+	require.True(t, ok)
+	require.Equal(t, "deadlock_detected", condition)
+
+	// wrapped errors, like those of a failed rollback, are still retried
+	condition, ok = retryableCondition(fmt.Errorf("tx er: %w, rb err: %v", &pq.Error{Code: "40001"}, errors.New("bad connection")))
+	require.True(t, ok)
+	require.Equal(t, "serialization_failure", condition)
+
+	_, ok = retryableCondition(&pq.Error{Code: "23514"})
+	require.False(t, ok)
+
+	_, ok = retryableCondition(ErrInsufficientFunds)
+	require.False(t, ok)
+
+	_, ok = retryableCondition(nil)
+	require.False(t, ok)
+}
+
+func TestTxRetryDelay(t *testing.T) {
+	for retry := 1; retry <= 64; retry++ {
+		delay := txRetryDelay(retry)
+		require.Positive(t, delay)
+		require.LessOrEqual(t, delay, txRetryMaxDelay)
+	}
+
+	require.LessOrEqual(t, txRetryDelay(1), txRetryBaseDelay)
+	require.GreaterOrEqual(t, txRetryDelay(1), txRetryBaseDelay/2)
+}
+
+func TestWaitForRetryDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	require.False(t, waitForRetry(ctx, maxTxAttempts))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	require.False(t, waitForRetry(ctx, 1))
+
+	require.True(t, waitForRetry(context.Background(), 1))
+}
+
+func TestExecTxRetry(t *testing.T) {
+	store := NewStore(testDB).(*SQLStore)
+	account := createRandomAccount(t)
+
+	// the first attempt fails as if postgres aborted it, and its update must not survive
+	attempts := 0
+	err := store.execTx(context.Background(), func(q *Queries) error {
+		attempts++
+		_, err := q.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+			ID: account.ID,
+			Amount: 10,
+		})
+		if err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return &pq.Error{Code: "40001"}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+10, updatedAccount.Balance)
+
+	// a transaction that keeps failing is given up after the last attempt
+	attempts = 0
+	err = store.execTx(context.Background(), func(q *Queries) error {
+		attempts++
+		return &pq.Error{Code: "40P01"}
+	})
+	require.Error(t, err)
+	require.Equal(t, maxTxAttempts, attempts)
+
+	// other errors aren't retried
+	attempts = 0
+	err = store.execTx(context.Background(), func(q *Queries) error {
+		attempts++
+		return ErrInsufficientFunds
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+	require.Equal(t, 1, attempts)
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+)
+
+// Constants for the reversal status of a transfer
+const (
+	ReversalNone    = "none"
+	ReversalPartial = "partial"
+	ReversalFull    = "full"
+)
+
+// ReversalStatus reports how much of the transfer was reversed
+func (transfer Transfer) ReversalStatus() string {
+	switch {
+	case transfer.ReversedAmount == 0:
+		return ReversalNone
+	case transfer.ReversedAmount < transfer.Amount:
+		return ReversalPartial
+	}
+
+	return ReversalFull
+}
+
+// ReverseTransferTxParams contains the input parameters of the reverse transfer transaction
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount is how much to reverse, in the currency of the original transfer's amount
+	// Zero reverses everything that wasn't reversed yet
+	Amount int64 `json:"amount"`
+	// InitiatedBy is the user reversing the transfer
+	InitiatedBy string `json:"initiated_by"`
+	// IdempotencyKey makes retries of the same request replay the original result when set
+	IdempotencyKey *IdempotencyKeyParams `json:"-"`
+}
+
+// ReverseTransferTxResult is the result of the reverse transfer transaction
+type ReverseTransferTxResult struct {
+	TransferTxResult
+	OriginalTransfer Transfer `json:"original_transfer"`
+}
+
+// ReverseTransferTx moves money of a transfer back from its to account to its from account
+// The reversal is a transfer of its own that is linked to the original, and the original keeps track of how much was reversed
+// It returns ErrReversalExceedsTransfer if more would be reversed than the original transfer's amount in total,
+// and ErrReverseReversal if the transfer is itself a reversal
+// A partial reversal of a transfer between currencies takes back the same share of the converted amount
+// The reversal carries the reference of the original, so a search by reference finds both
+func (store *SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if arg.IdempotencyKey != nil {
+			replayed, err := claimIdempotencyKey(ctx, q, *arg.IdempotencyKey, &result)
+			if err != nil || replayed {
+				return err
+			}
+		}
+
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if original.ReversalOf.Valid {
+			return ErrReverseReversal
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = original.Amount - original.ReversedAmount
+		}
+		if amount <= 0 || original.ReversedAmount+amount > original.Amount {
+			return ErrReversalExceedsTransfer
+		}
+
+		toAmount := reversedShare(original, original.ReversedAmount+amount) - reversedShare(original, original.ReversedAmount)
+		if toAmount <= 0 {
+			return ErrAmountTooSmall
+		}
+
+		result.TransferTxResult, err = transferMoney(ctx, q, CreateTransferParams{
+			FromAccountID: original.ToAccountID,
+			ToAccountID: original.FromAccountID,
+			Amount: toAmount,
+			ToAmount: amount,
+			ExchangeRate: original.ExchangeRate,
+			ReversalOf: sql.NullInt64{Int64: original.ID, Valid: true},
+			Reference: original.Reference,
+			InitiatedBy: initiatedBy(arg.InitiatedBy),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.OriginalTransfer, err = q.AddTransferReversedAmount(ctx, AddTransferReversedAmountParams{
+			ID: original.ID,
+			Amount: amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.IdempotencyKey != nil {
+			return saveIdempotencyKeyResponse(ctx, q, *arg.IdempotencyKey, result)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// reversedShare returns the part of the transfer's to amount that matches reversing reversedAmount of its amount, rounded down
+// Taking the difference of two shares keeps partial reversals from drifting, so a transfer reversed in full returns exactly its to amount
+func reversedShare(transfer Transfer, reversedAmount int64) int64 {
+	share := new(big.Int).Mul(big.NewInt(transfer.ToAmount), big.NewInt(reversedAmount))
+	return share.Quo(share, big.NewInt(transfer.Amount)).Int64()
+}
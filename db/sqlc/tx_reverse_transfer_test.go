@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	transferResult, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 100,
+	})
+	require.NoError(t, err)
+	original := transferResult.Transfer
+	require.Equal(t, ReversalNone, original.ReversalStatus())
+
+	// reverse part of the transfer
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.ID,
+		Amount: 30,
+	})
+	require.NoError(t, err)
+
+	reversal := result.Transfer
+	require.Equal(t, account2.ID, reversal.FromAccountID)
+	require.Equal(t, account1.ID, reversal.ToAccountID)
+	require.Equal(t, int64(30), reversal.Amount)
+	require.Equal(t, int64(30), reversal.ToAmount)
+	require.True(t, reversal.ReversalOf.Valid)
+	require.Equal(t, original.ID, reversal.ReversalOf.Int64)
+
+	require.Equal(t, int64(-30), result.FromEntry.Amount)
+	require.Equal(t, int64(30), result.ToEntry.Amount)
+	require.Equal(t, int64(1000-100+30), result.ToAccount.Balance)
+	require.Equal(t, account2.Balance+100-30, result.FromAccount.Balance)
+
+	require.Equal(t, int64(30), result.OriginalTransfer.ReversedAmount)
+	require.Equal(t, ReversalPartial, result.OriginalTransfer.ReversalStatus())
+
+	// a reversal can't be reversed
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: reversal.ID,
+	})
+	require.ErrorIs(t, err, ErrReverseReversal)
+
+	// no more than what is left can be reversed
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.ID,
+		Amount: 71,
+	})
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+
+	// reverse the rest
+	result, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(70), result.Transfer.Amount)
+	require.Equal(t, int64(100), result.OriginalTransfer.ReversedAmount)
+	require.Equal(t, ReversalFull, result.OriginalTransfer.ReversalStatus())
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.ID,
+	})
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+}
+
+func TestReverseTransferTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	transferResult, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 100,
+	})
+	require.NoError(t, err)
+
+	// only two of the five reversals fit into the transfer
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: transferResult.Transfer.ID,
+				Amount: 50,
+			})
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+	}
+	require.Equal(t, 2, succeeded)
+
+	original, err := testQueries.GetTransfer(context.Background(), transferResult.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, original.Amount, original.ReversedAmount)
+}
+
+func TestReverseExchangeTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithCurrency(t, util.USD, 1000)
+	account2 := createRandomAccountWithCurrency(t, util.EUR, 0)
+	quote := createRandomExchangeQuote(t, account1.Owner, util.USD, util.EUR, time.Minute)
+
+	transferResult, err := store.ExchangeTransferTx(context.Background(), ExchangeTransferTxParams{
+		TransferTxParams: TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID: account2.ID,
+			Amount: 100,
+		},
+		QuoteID: quote.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(92), transferResult.Transfer.ToAmount)
+
+	// the converted amount is taken back in proportion, so all partial reversals add up to it
+	var reversedToAmount int64
+	for _, amount := range []int64{33, 33, 34} {
+		result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+			TransferID: transferResult.Transfer.ID,
+			Amount: amount,
+		})
+		require.NoError(t, err)
+		require.Equal(t, amount, result.Transfer.ToAmount)
+		require.Equal(t, quote.Rate, result.Transfer.ExchangeRate)
+		reversedToAmount += result.Transfer.Amount
+	}
+	require.Equal(t, transferResult.Transfer.ToAmount, reversedToAmount)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Zero(t, updatedAccount2.Balance)
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+)
+
+// Constants for all statuses of a scheduled transfer
+const (
+	ScheduledTransferActive    = "active"
+	ScheduledTransferCompleted = "completed"
+	ScheduledTransferFailed    = "failed"
+	ScheduledTransferCancelled = "cancelled"
+)
+
+// Constants for all outcomes of a scheduled transfer run
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+)
+
+// RecordScheduledTransferRunTxParams contains the input parameters of the record scheduled transfer run transaction
+type RecordScheduledTransferRunTxParams struct {
+	// Run is the outcome of the attempt at the scheduled transfer's next occurrence
+	Run CreateScheduledTransferRunParams
+	// Schedule is where the scheduled transfer moves on to after the attempt
+	Schedule UpdateScheduledTransferScheduleParams
+}
+
+// RecordScheduledTransferRunTxResult is the result of the record scheduled transfer run transaction
+type RecordScheduledTransferRunTxResult struct {
+	Run ScheduledTransferRun `json:"run"`
+	ScheduledTransfer ScheduledTransfer `json:"scheduled_transfer"`
+}
+
+// RecordScheduledTransferRunTx records the outcome of an attempt at a scheduled transfer's next occurrence and moves the schedule on
+// It returns ErrRunAlreadyRecorded if the occurrence is no longer the next one, because another worker recorded it first
+// A scheduled transfer that was cancelled while the attempt ran gets the run recorded but stays cancelled
+func (store *SQLStore) RecordScheduledTransferRunTx(ctx context.Context, arg RecordScheduledTransferRunTxParams) (RecordScheduledTransferRunTxResult, error) {
+	var result RecordScheduledTransferRunTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		scheduled, err := q.GetScheduledTransferForUpdate(ctx, arg.Run.ScheduledTransferID)
+		if err != nil {
+			return err
+		}
+
+		if !scheduled.NextRunAt.Equal(arg.Run.ScheduledFor) {
+			return ErrRunAlreadyRecorded
+		}
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, arg.Run)
+		if err != nil {
+			return err
+		}
+
+		if scheduled.Status != ScheduledTransferActive {
+			result.ScheduledTransfer = scheduled
+			return nil
+		}
+
+		result.ScheduledTransfer, err = q.UpdateScheduledTransferSchedule(ctx, arg.Schedule)
+		return err
+	})
+
+	return result, err
+}
+
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordScheduledTransferRunTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	scheduled := createRandomScheduledTransfer(t, time.Now().Add(-time.Minute))
+	nextRunAt := scheduled.NextRunAt.AddDate(0, 1, 0)
+
+	arg := RecordScheduledTransferRunTxParams{
+		Run: CreateScheduledTransferRunParams{
+			ScheduledTransferID: scheduled.ID,
+			ScheduledFor: scheduled.NextRunAt,
+			Status: RunFailed,
+			Error: ErrInsufficientFunds.Error(),
+		},
+		Schedule: UpdateScheduledTransferScheduleParams{
+			ID: scheduled.ID,
+			Status: ScheduledTransferActive,
+			NextRunAt: nextRunAt,
+			NextAttemptAt: nextRunAt,
+			Attempts: 0,
+		},
+	}
+
+	result, err := store.RecordScheduledTransferRunTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	run := result.Run
+	require.NotZero(t, run.ID)
+	require.Equal(t, scheduled.ID, run.ScheduledTransferID)
+	require.WithinDuration(t, scheduled.NextRunAt, run.ScheduledFor, time.Second)
+	require.Equal(t, RunFailed, run.Status)
+	require.Equal(t, arg.Run.Error, run.Error)
+	require.False(t, run.TransferID.Valid)
+
+	require.WithinDuration(t, nextRunAt, result.ScheduledTransfer.NextRunAt, time.Second)
+
+	// recording the same occurrence again, as a second worker would, is rejected
+	_, err = store.RecordScheduledTransferRunTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrRunAlreadyRecorded)
+
+	runs, err := store.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit: 5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+}
+
+func TestRecordScheduledTransferRunTxCancelled(t *testing.T) {
+	store := NewStore(testDB)
+
+	scheduled := createRandomScheduledTransfer(t, time.Now().Add(-time.Minute))
+	_, err := store.CancelScheduledTransfer(context.Background(), scheduled.ID)
+	require.NoError(t, err)
+
+	arg := RecordScheduledTransferRunTxParams{
+		Run: CreateScheduledTransferRunParams{
+			ScheduledTransferID: scheduled.ID,
+			ScheduledFor: scheduled.NextRunAt,
+			Status: RunFailed,
+			Error: ErrInsufficientFunds.Error(),
+		},
+		Schedule: UpdateScheduledTransferScheduleParams{
+			ID: scheduled.ID,
+			Status: ScheduledTransferActive,
+			NextRunAt: scheduled.NextRunAt,
+			NextAttemptAt: time.Now().Add(time.Hour),
+			Attempts: 1,
+		},
+	}
+
+	result, err := store.RecordScheduledTransferRunTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, result.Run.ID)
+	require.Equal(t, ScheduledTransferCancelled, result.ScheduledTransfer.Status)
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// StatementTxParams contains the input parameters of the statement transaction
+type StatementTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// From and To bound the statement, entries made at From are on it and those made at To are not
+	From time.Time `json:"from"`
+	To time.Time `json:"to"`
+}
+
+// Statement lists the entries of an account over a period along with its balance before and after them
+type Statement struct {
+	Account Account `json:"account"`
+	From time.Time `json:"from"`
+	To time.Time `json:"to"`
+	OpeningBalance int64 `json:"opening_balance"`
+	ClosingBalance int64 `json:"closing_balance"`
+	// TotalCredits is the sum of the money that came into the account, TotalDebits of the money that left it, both positive
+	TotalCredits int64 `json:"total_credits"`
+	TotalDebits int64 `json:"total_debits"`
+	Lines []StatementLine `json:"lines"`
+}
+
+// StatementLine is an entry on a statement with the balance of the account right after it
+type StatementLine struct {
+	Entry Entry `json:"entry"`
+	RunningBalance int64 `json:"running_balance"`
+}
+
+// StatementTx builds the statement of an account for the period between arg.From and arg.To
+// The balances are worked back from the balance the account had at arg.To, see BalanceAtTx, and everything is read from the same snapshot,
+// so the closing balance is the account's current balance when arg.To isn't in the past
+func (store *SQLStore) StatementTx(ctx context.Context, arg StatementTxParams) (Statement, error) {
+	var result Statement
+
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := store.execTxWithOptions(ctx, opts, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		closingBalance, err := balanceAt(ctx, q, account, arg.To)
+		if err != nil {
+			return err
+		}
+
+		entries, err := q.ListAccountEntriesBetween(ctx, ListAccountEntriesBetweenParams{
+			AccountID: arg.AccountID,
+			CreatedAfter: arg.From,
+			CreatedBefore: arg.To,
+		})
+		if err != nil {
+			return err
+		}
+
+		result = newStatement(account, arg.From, arg.To, closingBalance, entries)
+		return nil
+	})
+
+	return result, err
+}
+
+// newStatement adds up the entries of a statement on top of its closing balance
+func newStatement(account Account, from time.Time, to time.Time, closingBalance int64, entries []Entry) Statement {
+	statement := Statement{
+		Account: account,
+		From: from,
+		To: to,
+		ClosingBalance: closingBalance,
+		Lines: make([]StatementLine, len(entries)),
+	}
+
+	for _, entry := range entries {
+		if entry.Amount > 0 {
+			statement.TotalCredits += entry.Amount
+		} else {
+			statement.TotalDebits -= entry.Amount
+		}
+	}
+
+	balance := closingBalance - statement.TotalCredits + statement.TotalDebits
+	statement.OpeningBalance = balance
+	for i, entry := range entries {
+		balance += entry.Amount
+		statement.Lines[i] = StatementLine{
+			Entry: entry,
+			RunningBalance: balance,
+		}
+	}
+
+	return statement
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 100,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID: account1.ID,
+		Amount: 30,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	statement, err := store.StatementTx(context.Background(), StatementTxParams{
+		AccountID: account1.ID,
+		From: now.Add(-time.Hour),
+		To: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), statement.OpeningBalance)
+	require.Equal(t, int64(930), statement.ClosingBalance)
+	require.Equal(t, int64(30), statement.TotalCredits)
+	require.Equal(t, int64(100), statement.TotalDebits)
+	require.Len(t, statement.Lines, 2)
+	require.Equal(t, int64(-100), statement.Lines[0].Entry.Amount)
+	require.Equal(t, int64(900), statement.Lines[0].RunningBalance)
+	require.Equal(t, int64(930), statement.Lines[1].RunningBalance)
+
+	// the closing balance is the balance the account has at the end of the statement
+	account1, err = testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, statement.ClosingBalance)
+
+	// entries made after a statement are taken off its closing balance
+	statement, err = store.StatementTx(context.Background(), StatementTxParams{
+		AccountID: account1.ID,
+		From: now.Add(-2 * time.Hour),
+		To: now.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), statement.OpeningBalance)
+	require.Equal(t, int64(1000), statement.ClosingBalance)
+	require.Empty(t, statement.Lines)
+}
+
+func TestNewStatement(t *testing.T) {
+	entries := []Entry{
+		{ID: 1, Amount: 500},
+		{ID: 2, Amount: -200},
+		{ID: 3, Amount: 50},
+	}
+
+	statement := newStatement(Account{}, time.Time{}, time.Time{}, 1000, entries)
+	require.Equal(t, int64(650), statement.OpeningBalance)
+	require.Equal(t, int64(1000), statement.ClosingBalance)
+	require.Equal(t, int64(550), statement.TotalCredits)
+	require.Equal(t, int64(200), statement.TotalDebits)
+
+	require.Len(t, statement.Lines, 3)
+	require.Equal(t, int64(1150), statement.Lines[0].RunningBalance)
+	require.Equal(t, int64(950), statement.Lines[1].RunningBalance)
+	require.Equal(t, int64(1000), statement.Lines[2].RunningBalance)
+
+	statement = newStatement(Account{}, time.Time{}, time.Time{}, 1000, nil)
+	require.Equal(t, int64(1000), statement.OpeningBalance)
+	require.Empty(t, statement.Lines)
+}
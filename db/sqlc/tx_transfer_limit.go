@@ -0,0 +1,254 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nokkvi/simplebank/util"
+)
+
+// Constants for the rolling windows transfer limits apply to
+const (
+	LimitDaily   = "daily"
+	LimitMonthly = "monthly"
+)
+
+// Constants for what a transfer limit applies to
+const (
+	LimitScopeAccount = "account"
+	LimitScopeUser    = "user"
+)
+
+// limitPeriods are the periods of transfer limits, in the order they are checked
+var limitPeriods = []string{LimitDaily, LimitMonthly}
+
+// DefaultTransferLimit is a bank-wide transfer limit of a period, for users and accounts without a limit of their own
+// MaxAmount is a whole number of major units of the currency of the account it applies to,
+// so the same limit suits currencies with any number of minor units
+type DefaultTransferLimit struct {
+	Period string `json:"period"`
+	MaxAmount int64 `json:"max_amount"`
+	MaxCount int32 `json:"max_count"`
+}
+
+// inCurrency returns the default limit as a limit on an account in the currency, with its amount in minor units
+func (limit DefaultTransferLimit) inCurrency(currency string) (TransferLimit, error) {
+	maxAmount, err := util.MajorUnitMoney(limit.MaxAmount, currency)
+	if err != nil {
+		return TransferLimit{}, err
+	}
+
+	return TransferLimit{
+		Period: limit.Period,
+		MaxAmount: maxAmount.Amount,
+		MaxCount: limit.MaxCount,
+	}, nil
+}
+
+// DefaultTransferLimits returns the bank-wide transfer limits by period from the config
+// A period whose amount or count isn't configured has no default limit
+func DefaultTransferLimits(config util.Config) map[string]DefaultTransferLimit {
+	limits := make(map[string]DefaultTransferLimit)
+	if config.DailyTransferLimitAmount > 0 && config.DailyTransferLimitCount > 0 {
+		limits[LimitDaily] = DefaultTransferLimit{
+			Period: LimitDaily,
+			MaxAmount: config.DailyTransferLimitAmount,
+			MaxCount: config.DailyTransferLimitCount,
+		}
+	}
+	if config.MonthlyTransferLimitAmount > 0 && config.MonthlyTransferLimitCount > 0 {
+		limits[LimitMonthly] = DefaultTransferLimit{
+			Period: LimitMonthly,
+			MaxAmount: config.MonthlyTransferLimitAmount,
+			MaxCount: config.MonthlyTransferLimitCount,
+		}
+	}
+
+	return limits
+}
+
+// TransferAllowance is how much of a transfer limit was used within its rolling window and how much is left
+type TransferAllowance struct {
+	Scope string `json:"scope"`
+	Period string `json:"period"`
+	MaxAmount int64 `json:"max_amount"`
+	MaxCount int32 `json:"max_count"`
+	UsedAmount int64 `json:"used_amount"`
+	UsedCount int64 `json:"used_count"`
+	RemainingAmount int64 `json:"remaining_amount"`
+	RemainingCount int64 `json:"remaining_count"`
+}
+
+// TransferAllowancesTxParams contains the input parameters of the transfer allowances transaction
+type TransferAllowancesTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Username is the user whose own limits are returned along with the account's
+	Username string `json:"username"`
+	// DefaultLimits are the bank-wide limits by period, for users and accounts without limits of their own
+	DefaultLimits map[string]DefaultTransferLimit `json:"-"`
+}
+
+// TransferAllowancesTx returns what is left of the transfer limits of an account and of a user
+// The user's limits count the transfers they made out of any account in the account's currency
+func (store *SQLStore) TransferAllowancesTx(ctx context.Context, arg TransferAllowancesTxParams) ([]TransferAllowance, error) {
+	var result []TransferAllowance
+
+	// every total is taken from the same snapshot
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := store.execTxWithOptions(ctx, opts, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		result, err = transferAllowances(ctx, q, account, arg.Username, arg.DefaultLimits)
+		return err
+	})
+
+	return result, err
+}
+
+// transferAllowances returns what is left of the account's transfer limits and of the user's
+// A limit set for the account or the user takes precedence over the default for its period
+// Without a user, as for the transfers the bank makes, only the account's limits apply
+func transferAllowances(ctx context.Context, q *Queries, account Account, username string, defaultLimits map[string]DefaultTransferLimit) ([]TransferAllowance, error) {
+	limits, err := q.ListTransferLimits(ctx, ListTransferLimitsParams{
+		Username: username,
+		AccountID: account.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ownLimits := make(map[string]TransferLimit, len(limits))
+	for _, limit := range limits {
+		scope := LimitScopeUser
+		if limit.AccountID.Valid {
+			scope = LimitScopeAccount
+		}
+		ownLimits[scope+":"+limit.Period] = limit
+	}
+
+	now := time.Now()
+	allowances := []TransferAllowance{}
+	scopes := []string{LimitScopeAccount}
+	if username != "" {
+		scopes = append(scopes, LimitScopeUser)
+	}
+
+	for _, scope := range scopes {
+		for _, period := range limitPeriods {
+			limit, ok := ownLimits[scope+":"+period]
+			if !ok {
+				defaultLimit, isDefault := defaultLimits[period]
+				if !isDefault {
+					continue
+				}
+
+				limit, err = defaultLimit.inCurrency(account.Currency)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			used, err := outgoingTotal(ctx, q, scope, account, username, limitWindowStart(period, now))
+			if err != nil {
+				return nil, err
+			}
+
+			allowance := TransferAllowance{
+				Scope: scope,
+				Period: period,
+				MaxAmount: limit.MaxAmount,
+				MaxCount: limit.MaxCount,
+				UsedAmount: used.Amount,
+				UsedCount: used.Count,
+			}
+			if used.Amount < limit.MaxAmount {
+				allowance.RemainingAmount = limit.MaxAmount - used.Amount
+			}
+			if used.Count < int64(limit.MaxCount) {
+				allowance.RemainingCount = int64(limit.MaxCount) - used.Count
+			}
+			allowances = append(allowances, allowance)
+		}
+	}
+
+	return allowances, nil
+}
+
+// outgoingTotal sums up the transfers out of the account, or those the user made out of any account in its currency, since the given time
+// Reversals don't count, they only give back money that was received, and neither do transfers to the bank's own accounts,
+// such as fees, overdraft charges and cash withdrawals, which are no payments the user makes and aren't checked against limits
+func outgoingTotal(ctx context.Context, q *Queries, scope string, account Account, username string, since time.Time) (GetAccountOutgoingTotalRow, error) {
+	if scope == LimitScopeAccount {
+		return q.GetAccountOutgoingTotal(ctx, GetAccountOutgoingTotalParams{
+			AccountID: account.ID,
+			Since: since,
+		})
+	}
+
+	total, err := q.GetUserOutgoingTotal(ctx, GetUserOutgoingTotalParams{
+		Username: username,
+		Currency: account.Currency,
+		Since: since,
+	})
+	return GetAccountOutgoingTotalRow(total), err
+}
+
+// limitWindowStart returns when the rolling window of a limit period that ends at now starts
+func limitWindowStart(period string, now time.Time) time.Time {
+	if period == LimitMonthly {
+		return now.AddDate(0, -1, 0)
+	}
+
+	return now.AddDate(0, 0, -1)
+}
+
+// checkTransferLimits returns an error wrapping ErrTransferLimitExceeded if the user transferring amount out of the account
+// would go over one of the limits of the account or of their own
+// The user and the account must already be locked, so concurrent transfers can't both use up the same allowance
+func checkTransferLimits(ctx context.Context, q *Queries, account Account, username string, amount int64, defaultLimits map[string]DefaultTransferLimit) error {
+	allowances, err := transferAllowances(ctx, q, account, username, defaultLimits)
+	if err != nil {
+		return err
+	}
+
+	for _, allowance := range allowances {
+		if allowance.RemainingAmount < amount {
+			return fmt.Errorf("%w: %s amount limit of the %s", ErrTransferLimitExceeded, allowance.Period, allowance.Scope)
+		}
+		if allowance.RemainingCount < 1 {
+			return fmt.Errorf("%w: %s count limit of the %s", ErrTransferLimitExceeded, allowance.Period, allowance.Scope)
+		}
+	}
+
+	return nil
+}
+
+// lockUsers locks the given users, in ascending username order, skipping empty usernames
+// Transfers lock the user making them before the accounts they move money between,
+// so transfers a user makes out of different accounts can't both use up the same allowance of the user
+func lockUsers(ctx context.Context, q *Queries, usernames ...string) error {
+	users := make([]string, 0, len(usernames))
+	seen := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		if username != "" && !seen[username] {
+			seen[username] = true
+			users = append(users, username)
+		}
+	}
+	sort.Strings(users)
+
+	for _, username := range users {
+		_, err := q.GetUserForUpdate(ctx, username)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
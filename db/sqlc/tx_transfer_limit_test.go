@@ -0,0 +1,287 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	defaultLimits := map[string]DefaultTransferLimit{
+		LimitDaily: {Period: LimitDaily, MaxAmount: 3, MaxCount: 10},
+		LimitMonthly: {Period: LimitMonthly, MaxAmount: 10, MaxCount: 10},
+	}
+
+	arg := TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 200,
+		InitiatedBy: account1.Owner,
+		DefaultLimits: defaultLimits,
+	}
+
+	_, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// the second transfer would go over the daily default
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+
+	arg.Amount = 100
+	_, err = store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// a limit of the account's own takes precedence over the default
+	_, err = testQueries.SetAccountTransferLimit(context.Background(), SetAccountTransferLimitParams{
+		AccountID: sql.NullInt64{Int64: account1.ID, Valid: true},
+		Period: LimitDaily,
+		MaxAmount: 500,
+		MaxCount: 3,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrTransferLimitExceeded, "the user's daily default still applies")
+
+	_, err = testQueries.SetUserTransferLimit(context.Background(), SetUserTransferLimitParams{
+		Username: sql.NullString{String: account1.Owner, Valid: true},
+		Period: LimitDaily,
+		MaxAmount: 500,
+		MaxCount: 10,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// the account's count limit is used up
+	arg.Amount = 1
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+
+	allowances, err := store.TransferAllowancesTx(context.Background(), TransferAllowancesTxParams{
+		AccountID: account1.ID,
+		Username: account1.Owner,
+		DefaultLimits: defaultLimits,
+	})
+	require.NoError(t, err)
+	require.Len(t, allowances, 4)
+
+	daily := allowances[0]
+	require.Equal(t, LimitScopeAccount, daily.Scope)
+	require.Equal(t, LimitDaily, daily.Period)
+	require.Equal(t, int64(500), daily.MaxAmount)
+	require.Equal(t, int64(400), daily.UsedAmount)
+	require.Equal(t, int64(3), daily.UsedCount)
+	require.Equal(t, int64(100), daily.RemainingAmount)
+	require.Zero(t, daily.RemainingCount)
+
+	monthly := allowances[1]
+	require.Equal(t, LimitScopeAccount, monthly.Scope)
+	require.Equal(t, LimitMonthly, monthly.Period)
+	require.Equal(t, int64(1000), monthly.MaxAmount)
+	require.Equal(t, int64(600), monthly.RemainingAmount)
+}
+
+func TestTransferLimitsSkipBankAccounts(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+	banker := createRandomUser(t)
+
+	defaultLimits := map[string]DefaultTransferLimit{
+		LimitDaily: {Period: LimitDaily, MaxAmount: 10, MaxCount: 10},
+	}
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 100,
+		InitiatedBy: account1.Owner,
+		DefaultLimits: defaultLimits,
+	})
+	require.NoError(t, err)
+
+	// cash withdrawals and fees go to the bank's own accounts, so they use up no allowance of the account or the banker
+	_, err = store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account1.ID,
+		Amount: 300,
+		Fee: 5,
+		Banker: banker.Username,
+	})
+	require.NoError(t, err)
+
+	for _, username := range []string{account1.Owner, banker.Username} {
+		allowances, err := store.TransferAllowancesTx(context.Background(), TransferAllowancesTxParams{
+			AccountID: account1.ID,
+			Username: username,
+			DefaultLimits: defaultLimits,
+		})
+		require.NoError(t, err)
+		require.Len(t, allowances, 2)
+
+		require.Equal(t, LimitScopeAccount, allowances[0].Scope)
+		require.Equal(t, int64(100), allowances[0].UsedAmount)
+		require.Equal(t, int64(1), allowances[0].UsedCount)
+
+		require.Equal(t, LimitScopeUser, allowances[1].Scope)
+		if username == banker.Username {
+			require.Zero(t, allowances[1].UsedAmount)
+		} else {
+			require.Equal(t, int64(100), allowances[1].UsedAmount)
+		}
+	}
+}
+
+func TestTransferTxWithoutLimits(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	allowances, err := store.TransferAllowancesTx(context.Background(), TransferAllowancesTxParams{
+		AccountID: account1.ID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, allowances)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 1000,
+	})
+	require.NoError(t, err)
+}
+
+func TestHoldTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+
+	defaultLimits := map[string]DefaultTransferLimit{
+		LimitDaily: {Period: LimitDaily, MaxAmount: 3, MaxCount: 10},
+	}
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 200,
+		InitiatedBy: account1.Owner,
+		DefaultLimits: defaultLimits,
+	})
+	require.NoError(t, err)
+
+	// a hold can't reserve more than what is left of the daily limit
+	arg := AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 101,
+		ExpiresAt: time.Now().Add(time.Hour),
+		InitiatedBy: account1.Owner,
+		DefaultLimits: defaultLimits,
+	}
+	_, err = store.AuthorizeHoldTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+
+	arg.Amount = 100
+	authorized, err := store.AuthorizeHoldTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// by the time it is captured another transfer used up the limit
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 100,
+		InitiatedBy: account1.Owner,
+		DefaultLimits: defaultLimits,
+	})
+	require.NoError(t, err)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: authorized.Hold.ID,
+		DefaultLimits: defaultLimits,
+	})
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+}
+
+
+func TestTransferTxLimitsOfPayer(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+	payer := createRandomUser(t)
+
+	_, err := testQueries.UpsertAccountMember(context.Background(), UpsertAccountMemberParams{
+		AccountID: account1.ID,
+		Username: payer.Username,
+		Role: MemberPayer,
+	})
+	require.NoError(t, err)
+
+	defaultLimits := map[string]DefaultTransferLimit{
+		LimitDaily: {Period: LimitDaily, MaxAmount: 3, MaxCount: 10},
+	}
+
+	_, err = testQueries.SetUserTransferLimit(context.Background(), SetUserTransferLimitParams{
+		Username: sql.NullString{String: account1.Owner, Valid: true},
+		Period: LimitDaily,
+		MaxAmount: 100,
+		MaxCount: 10,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.SetAccountTransferLimit(context.Background(), SetAccountTransferLimitParams{
+		AccountID: sql.NullInt64{Int64: account1.ID, Valid: true},
+		Period: LimitDaily,
+		MaxAmount: 1000,
+		MaxCount: 10,
+	})
+	require.NoError(t, err)
+
+	// the payer's transfer counts against the payer's limits, not the owner's
+	arg := TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 200,
+		InitiatedBy: payer.Username,
+		DefaultLimits: defaultLimits,
+	}
+	result, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, sql.NullString{String: payer.Username, Valid: true}, result.Transfer.InitiatedBy)
+
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrTransferLimitExceeded, "it would go over the payer's daily default")
+
+	// the owner's own allowance is untouched by the payer's transfer
+	allowances, err := store.TransferAllowancesTx(context.Background(), TransferAllowancesTxParams{
+		AccountID: account1.ID,
+		Username: account1.Owner,
+		DefaultLimits: defaultLimits,
+	})
+	require.NoError(t, err)
+	require.Len(t, allowances, 2)
+
+	require.Equal(t, LimitScopeAccount, allowances[0].Scope)
+	require.Equal(t, int64(200), allowances[0].UsedAmount)
+
+	require.Equal(t, LimitScopeUser, allowances[1].Scope)
+	require.Equal(t, int64(100), allowances[1].MaxAmount)
+	require.Zero(t, allowances[1].UsedAmount)
+
+	arg.Amount = 100
+	arg.InitiatedBy = account1.Owner
+	_, err = store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+}
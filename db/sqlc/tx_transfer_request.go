@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Constants for all statuses of a transfer request
+const (
+	TransferRequestPending  = "pending"
+	TransferRequestApproved = "approved"
+	TransferRequestRejected = "rejected"
+)
+
+// NeedsApproval reports whether a transfer of amount out of the account has to wait for one of the account's approvers
+func NeedsApproval(account Account, amount int64) bool {
+	return account.ApprovalThreshold.Valid && amount > account.ApprovalThreshold.Int64
+}
+
+// RequestTransferTxParams contains the input parameters of the request transfer transaction
+type RequestTransferTxParams struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID int64 `json:"to_account_id"`
+	Amount int64 `json:"amount"`
+	RequestedBy string `json:"requested_by"`
+	TransferDetails
+	// IdempotencyKey makes retries of the same request replay the original result when set
+	IdempotencyKey *IdempotencyKeyParams `json:"-"`
+}
+
+// RequestTransferTx records a transfer that waits for one of the from account's approvers instead of moving any money
+// If an idempotency key is given and was already used, the original request is returned without recording another one
+func (store *SQLStore) RequestTransferTx(ctx context.Context, arg RequestTransferTxParams) (TransferRequest, error) {
+	var result TransferRequest
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if arg.IdempotencyKey != nil {
+			replayed, err := claimIdempotencyKey(ctx, q, *arg.IdempotencyKey, &result)
+			if err != nil || replayed {
+				return err
+			}
+		}
+
+		var err error
+		result, err = q.CreateTransferRequest(ctx, CreateTransferRequestParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID: arg.ToAccountID,
+			Amount: arg.Amount,
+			RequestedBy: arg.RequestedBy,
+			Memo: arg.Memo,
+			Reference: arg.Reference,
+			Metadata: arg.metadataJSON(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.IdempotencyKey != nil {
+			return saveIdempotencyKeyResponse(ctx, q, *arg.IdempotencyKey, result)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// ApproveTransferRequestTxParams contains the input parameters of the approve transfer request transaction
+type ApproveTransferRequestTxParams struct {
+	RequestID int64 `json:"request_id"`
+	Approver string `json:"approver"`
+	// DefaultLimits are the bank-wide transfer limits by period, for users and accounts without limits of their own
+	DefaultLimits map[string]DefaultTransferLimit `json:"-"`
+}
+
+// ApproveTransferRequestTxResult is the result of the approve transfer request transaction
+type ApproveTransferRequestTxResult struct {
+	TransferTxResult
+	TransferRequest TransferRequest `json:"transfer_request"`
+}
+
+// ApproveTransferRequestTx performs the transfer of a pending request and marks the request as approved
+// The balance and the transfer limits are checked as they are at approval time, not as they were when the transfer was requested,
+// and a request that fails those checks stays pending
+// It returns ErrTransferRequestNotPending if the request was already approved or rejected
+func (store *SQLStore) ApproveTransferRequestTx(ctx context.Context, arg ApproveTransferRequestTxParams) (ApproveTransferRequestTxResult, error) {
+	var result ApproveTransferRequestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		request, err := q.GetTransferRequest(ctx, arg.RequestID)
+		if err != nil {
+			return err
+		}
+
+		// lock the user who requested the transfer and the accounts before the request, in the same order as transfers
+		err = lockUsers(ctx, q, request.RequestedBy)
+		if err != nil {
+			return err
+		}
+
+		fromAccount, _, err := lockAccounts(ctx, q, request.FromAccountID, request.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		request, err = q.GetTransferRequestForUpdate(ctx, arg.RequestID)
+		if err != nil {
+			return err
+		}
+
+		if request.Status != TransferRequestPending {
+			return ErrTransferRequestNotPending
+		}
+
+		err = checkTransferLimits(ctx, q, fromAccount, request.RequestedBy, request.Amount, arg.DefaultLimits)
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = transferMoney(ctx, q, CreateTransferParams{
+			FromAccountID: request.FromAccountID,
+			ToAccountID: request.ToAccountID,
+			Amount: request.Amount,
+			ToAmount: request.Amount,
+			ExchangeRate: sameCurrencyRate,
+			Memo: request.Memo,
+			Reference: request.Reference,
+			Metadata: request.Metadata,
+			InitiatedBy: initiatedBy(request.RequestedBy),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.TransferRequest, err = q.DecideTransferRequest(ctx, DecideTransferRequestParams{
+			ID: request.ID,
+			Status: TransferRequestApproved,
+			DecidedBy: sql.NullString{String: arg.Approver, Valid: true},
+			TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproveTransferRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+	approver := addRandomAccountApprover(t, account1)
+
+	request, err := store.RequestTransferTx(context.Background(), RequestTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID: account2.ID,
+		Amount: 600,
+		RequestedBy: account1.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestPending, request.Status)
+
+	// requesting moves no money
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), updatedAccount1.Balance)
+
+	arg := ApproveTransferRequestTxParams{
+		RequestID: request.ID,
+		Approver: approver.Username,
+	}
+
+	result, err := store.ApproveTransferRequestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(600), result.Transfer.Amount)
+	require.Equal(t, int64(400), result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+600, result.ToAccount.Balance)
+
+	require.Equal(t, TransferRequestApproved, result.TransferRequest.Status)
+	require.Equal(t, approver.Username, result.TransferRequest.DecidedBy.String)
+	require.Equal(t, result.Transfer.ID, result.TransferRequest.TransferID.Int64)
+
+	_, err = store.ApproveTransferRequestTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrTransferRequestNotPending)
+}
+
+func TestApproveTransferRequestTxRecheck(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+	approver := addRandomAccountApprover(t, account1)
+
+	request1 := createRandomTransferRequest(t, account1, account2, 600)
+	request2 := createRandomTransferRequest(t, account1, account2, 600)
+
+	_, err := store.ApproveTransferRequestTx(context.Background(), ApproveTransferRequestTxParams{
+		RequestID: request1.ID,
+		Approver: approver.Username,
+	})
+	require.NoError(t, err)
+
+	// the balance left after the first approval doesn't cover the second request, which stays pending
+	_, err = store.ApproveTransferRequestTx(context.Background(), ApproveTransferRequestTxParams{
+		RequestID: request2.ID,
+		Approver: approver.Username,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	request2, err = testQueries.GetTransferRequest(context.Background(), request2.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestPending, request2.Status)
+
+	// limits are checked at approval time too
+	request3 := createRandomTransferRequest(t, account1, account2, 100)
+	_, err = store.ApproveTransferRequestTx(context.Background(), ApproveTransferRequestTxParams{
+		RequestID: request3.ID,
+		Approver: approver.Username,
+		DefaultLimits: map[string]DefaultTransferLimit{
+			LimitDaily: {Period: LimitDaily, MaxAmount: 6, MaxCount: 10},
+		},
+	})
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+}
+
+func TestApproveTransferRequestTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccountWithBalance(t, 1000)
+	account2 := createRandomAccount(t)
+	approver := addRandomAccountApprover(t, account1)
+	request := createRandomTransferRequest(t, account1, account2, 100)
+
+	// only one of the approvals transfers the money
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.ApproveTransferRequestTx(context.Background(), ApproveTransferRequestTxParams{
+				RequestID: request.ID,
+				Approver: approver.Username,
+			})
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrTransferRequestNotPending)
+	}
+	require.Equal(t, 1, succeeded)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(900), updatedAccount1.Balance)
+}
@@ -2,9 +2,11 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/nokkvi/simplebank/util"
 	"github.com/stretchr/testify/require"
 )
@@ -27,6 +29,7 @@ func createRandomUser(t *testing.T) User {
 	require.Equal(t, arg.HashedPassword, user.HashedPassword)
 	require.Equal(t, arg.FullName, user.FullName)
 	require.Equal(t, arg.Email, user.Email)
+	require.Equal(t, util.DepositorRole, user.Role)
 
 	require.True(t, user.PasswordChangedAt.IsZero())
 	require.NotZero(t, user.CreatedAt)
@@ -47,6 +50,87 @@ func TestGetUser(t *testing.T) {
 	require.Equal(t, user1.HashedPassword, user2.HashedPassword)
 	require.Equal(t, user1.FullName, user2.FullName)
 	require.Equal(t, user1.Email, user2.Email)
+	require.Equal(t, user1.Role, user2.Role)
 	require.WithinDuration(t, user1.PasswordChangedAt, user2.PasswordChangedAt, time.Second)
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
-}
\ No newline at end of file
+}
+
+func TestUpdateUser(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	// only the fields given change
+	newEmail := util.RandomEmail()
+	user2, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username: user1.Username,
+		Email: sql.NullString{String: newEmail, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, user1.FullName, user2.FullName)
+	require.Equal(t, newEmail, user2.Email)
+	require.Equal(t, user1.HashedPassword, user2.HashedPassword)
+
+	newFullName := util.RandomOwner()
+	user3, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username: user1.Username,
+		FullName: sql.NullString{String: newFullName, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, newFullName, user3.FullName)
+	require.Equal(t, newEmail, user3.Email)
+}
+
+func TestChangePasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	var sessions []Session
+	for i := 0; i < 2; i++ {
+		session, err := testQueries.CreateSession(context.Background(), CreateSessionParams{
+			ID: uuid.New(),
+			Username: user.Username,
+			RefreshToken: util.RandomString(32),
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		sessions = append(sessions, session)
+	}
+
+	otherSession, err := testQueries.CreateSession(context.Background(), CreateSessionParams{
+		ID: uuid.New(),
+		Username: createRandomUser(t).Username,
+		RefreshToken: util.RandomString(32),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	changedAt := time.Now()
+	result, err := store.ChangePasswordTx(context.Background(), ChangePasswordTxParams{
+		Username: user.Username,
+		HashedPassword: hashedPassword,
+		ChangedAt: changedAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, result.User.HashedPassword)
+	require.WithinDuration(t, changedAt, result.User.PasswordChangedAt, time.Microsecond)
+	require.Equal(t, int64(len(sessions)), result.BlockedSessions)
+
+	for _, session := range sessions {
+		session, err = testQueries.GetSession(context.Background(), session.ID)
+		require.NoError(t, err)
+		require.True(t, session.IsBlocked)
+	}
+
+	// sessions of other users are left alone
+	otherSession, err = testQueries.GetSession(context.Background(), otherSession.ID)
+	require.NoError(t, err)
+	require.False(t, otherSession.IsBlocked)
+
+	_, err = store.ChangePasswordTx(context.Background(), ChangePasswordTxParams{
+		Username: util.RandomOwner(),
+		HashedPassword: hashedPassword,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -8,7 +8,8 @@ CREATE TABLE "users" (
   "full_name" varchar NOT NULL,
   "email" varchar UNIQUE NOT NULL,
   "password_changed_at" timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z',
-  "created_at" timestamptz NOT NULL DEFAULT (now())
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "role" varchar NOT NULL DEFAULT 'depositor'
 );
 
 CREATE TABLE "sessions" (
@@ -27,6 +28,27 @@ CREATE TABLE "accounts" (
   "owner" varchar NOT NULL,
   "balance" bigint NOT NULL,
   "currency" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "held_amount" bigint NOT NULL DEFAULT 0,
+  "available_balance" bigint NOT NULL,
+  "approval_threshold" bigint,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "product" varchar,
+  "system_purpose" varchar,
+  "overdraft_limit" bigint NOT NULL DEFAULT 0,
+  "overdraft_annual_rate" numeric NOT NULL DEFAULT 0,
+  "overdraft_daily_fee" bigint NOT NULL DEFAULT 0,
+  "number" varchar UNIQUE,
+  "nickname" varchar
+);
+
+CREATE TABLE "account_status_changes" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "from_status" varchar NOT NULL,
+  "to_status" varchar NOT NULL,
+  "actor" varchar NOT NULL,
+  "reason" varchar NOT NULL,
   "created_at" timestamptz NOT NULL DEFAULT (now())
 );
 
@@ -34,7 +56,11 @@ CREATE TABLE "entries" (
   "id" bigserial PRIMARY KEY,
   "account_id" bigint NOT NULL,
   "amount" bigint NOT NULL,
-  "created_at" timestamptz NOT NULL DEFAULT (now())
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "memo" varchar NOT NULL DEFAULT '',
+  "reference" varchar NOT NULL DEFAULT '',
+  "metadata" jsonb NOT NULL DEFAULT '{}',
+  "transfer_id" bigint
 );
 
 CREATE TABLE "transfers" (
@@ -42,31 +68,497 @@ CREATE TABLE "transfers" (
   "from_account_id" bigint NOT NULL,
   "to_account_id" bigint NOT NULL,
   "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "to_amount" bigint NOT NULL,
+  "exchange_rate" numeric NOT NULL DEFAULT 1,
+  "reversal_of" bigint,
+  "reversed_amount" bigint NOT NULL DEFAULT 0,
+  "memo" varchar NOT NULL DEFAULT '',
+  "reference" varchar NOT NULL DEFAULT '',
+  "metadata" jsonb NOT NULL DEFAULT '{}',
+  "initiated_by" varchar
+);
+
+CREATE TABLE "idempotency_keys" (
+  "username" varchar NOT NULL,
+  "key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "response" jsonb NOT NULL DEFAULT '{}',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "key")
+);
+
+CREATE TABLE "exchange_rates" (
+  "from_currency" varchar NOT NULL,
+  "to_currency" varchar NOT NULL,
+  "rate" numeric NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("from_currency", "to_currency")
+);
+
+CREATE TABLE "exchange_quotes" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "from_currency" varchar NOT NULL,
+  "to_currency" varchar NOT NULL,
+  "rate" numeric NOT NULL,
+  "is_used" boolean NOT NULL DEFAULT false,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "scheduled_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "recurrence" varchar NOT NULL,
+  "day_of_month" integer NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "next_run_at" timestamptz NOT NULL,
+  "next_attempt_at" timestamptz NOT NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "scheduled_transfer_runs" (
+  "id" bigserial PRIMARY KEY,
+  "scheduled_transfer_id" bigint NOT NULL,
+  "scheduled_for" timestamptz NOT NULL,
+  "status" varchar NOT NULL,
+  "transfer_id" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'authorized',
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "initiated_by" varchar NOT NULL
+);
+
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar,
+  "account_id" bigint,
+  "period" varchar NOT NULL,
+  "max_amount" bigint NOT NULL,
+  "max_count" integer NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "account_approvers" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
+CREATE TABLE "transfer_requests" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "requested_by" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "decided_by" varchar,
+  "transfer_id" bigint,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "memo" varchar NOT NULL DEFAULT '',
+  "reference" varchar NOT NULL DEFAULT '',
+  "metadata" jsonb NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE "balance_snapshots" (
+  "account_id" bigint NOT NULL,
+  "taken_at" timestamptz NOT NULL,
+  "balance" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "taken_at")
+);
+
+CREATE TABLE "account_products" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "annual_interest_rate" numeric NOT NULL DEFAULT 0,
+  "day_count_convention" varchar NOT NULL DEFAULT 'act/365',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "max_accounts" int NOT NULL DEFAULT 1,
+  "overdraft_limit" bigint NOT NULL DEFAULT 0,
+  "overdraft_annual_rate" numeric NOT NULL DEFAULT 0,
+  "overdraft_daily_fee" bigint NOT NULL DEFAULT 0
+);
+
+CREATE TABLE "interest_accruals" (
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_interest_rate" numeric NOT NULL,
+  "day_count_convention" varchar NOT NULL,
+  "amount" numeric NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "accrual_date")
+);
+
+CREATE TABLE "interest_capitalizations" (
+  "account_id" bigint NOT NULL,
+  "period" date NOT NULL,
+  "amount" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "period")
+);
+
+CREATE TABLE "overdraft_charges" (
+  "account_id" bigint NOT NULL,
+  "charge_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_rate" numeric NOT NULL,
+  "interest" bigint NOT NULL,
+  "fee" bigint NOT NULL,
+  "waived" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "charge_date")
+);
+
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "account_id" bigint,
+  "kind" varchar NOT NULL,
+  "message" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "account_members" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
+CREATE TABLE "cash_movements" (
+  "id" bigserial PRIMARY KEY,
+  "kind" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "fee" bigint NOT NULL DEFAULT 0,
+  "banker" varchar NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "fee_transfer_id" bigint,
   "created_at" timestamptz NOT NULL DEFAULT (now())
 );
 
+CREATE TABLE "currencies" (
+  "code" varchar PRIMARY KEY,
+  "numeric_code" int UNIQUE NOT NULL,
+  "minor_units" int NOT NULL,
+  "symbol" varchar NOT NULL DEFAULT '',
+  "enabled" boolean NOT NULL DEFAULT true,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
 CREATE INDEX ON "accounts" ("owner");
 
-CREATE UNIQUE INDEX ON "accounts" ("owner", "currency");
+CREATE UNIQUE INDEX ON "accounts" ("owner", "nickname") WHERE "status" <> 'closed';
+
+CREATE INDEX ON "accounts" ("owner", "currency", "product");
+
+CREATE UNIQUE INDEX ON "accounts" ("system_purpose", "currency") WHERE "system_purpose" IS NOT NULL;
+
+CREATE INDEX ON "account_status_changes" ("account_id");
 
 CREATE INDEX ON "entries" ("account_id");
 
+CREATE INDEX ON "entries" ("account_id", "created_at");
+
+CREATE INDEX ON "entries" ("transfer_id");
+
 CREATE INDEX ON "transfers" ("from_account_id");
 
 CREATE INDEX ON "transfers" ("to_account_id");
 
 CREATE INDEX ON "transfers" ("from_account_id", "to_account_id");
 
+CREATE INDEX ON "transfers" ("reversal_of");
+
+CREATE INDEX ON "transfers" ("initiated_by", "created_at");
+
+CREATE INDEX ON "scheduled_transfers" ("owner");
+
+CREATE INDEX ON "scheduled_transfers" ("status", "next_attempt_at");
+
+CREATE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id");
+
+CREATE UNIQUE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id", "scheduled_for") WHERE "status" = 'succeeded';
+
+CREATE INDEX ON "holds" ("from_account_id");
+
+CREATE INDEX ON "holds" ("to_account_id");
+
+CREATE INDEX ON "holds" ("status", "expires_at");
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("username", "period");
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("account_id", "period");
+
+CREATE INDEX ON "account_approvers" ("username");
+
+CREATE INDEX ON "transfer_requests" ("from_account_id", "status");
+
+CREATE INDEX ON "notifications" ("username");
+
+CREATE INDEX ON "account_members" ("username");
+
+CREATE INDEX ON "cash_movements" ("account_id");
+
+COMMENT ON COLUMN "accounts"."balance" IS 'must not go below the overdraft limit, except on system accounts';
+
+COMMENT ON COLUMN "accounts"."held_amount" IS 'sum of the authorized holds on the account';
+
+COMMENT ON COLUMN "accounts"."available_balance" IS 'balance that is not held';
+
+COMMENT ON COLUMN "accounts"."approval_threshold" IS 'transfers above it wait for an approver, none need approval when null';
+
+COMMENT ON COLUMN "accounts"."status" IS 'active, frozen or closed';
+
+COMMENT ON COLUMN "accounts"."product" IS 'the product the account was opened as, its interest rate applies';
+
+COMMENT ON COLUMN "accounts"."system_purpose" IS 'set on the accounts of the bank itself, which may go negative';
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'how far below zero the balance may go';
+
+COMMENT ON COLUMN "accounts"."overdraft_annual_rate" IS 'interest charged on the overdrawn balance over a year, 0.2 for 20%';
+
+COMMENT ON COLUMN "accounts"."overdraft_daily_fee" IS 'charged for every day the account ends overdrawn';
+
+COMMENT ON COLUMN "accounts"."number" IS 'public IBAN-style account number with mod-97 check digits, the bank''s own accounts have none';
+
+COMMENT ON COLUMN "accounts"."nickname" IS 'name the owner tells their accounts apart by, unique among their open accounts';
+
+COMMENT ON COLUMN "account_status_changes"."actor" IS 'the user who changed the status';
+
 COMMENT ON COLUMN "entries"."amount" IS 'can be negative or positive';
 
-COMMENT ON COLUMN "transfers"."amount" IS 'must be positive';
+COMMENT ON COLUMN "entries"."memo" IS 'memo of the transfer that made the entry';
+
+COMMENT ON COLUMN "entries"."reference" IS 'reference of the transfer that made the entry';
+
+COMMENT ON COLUMN "entries"."metadata" IS 'metadata of the transfer that made the entry';
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'the transfer that made the entry';
+
+COMMENT ON COLUMN "users"."role" IS 'depositor or banker';
+
+COMMENT ON COLUMN "transfers"."amount" IS 'must be positive, in the from account currency';
+
+COMMENT ON COLUMN "transfers"."to_amount" IS 'credited to the to account, in its currency';
+
+COMMENT ON COLUMN "transfers"."exchange_rate" IS 'a reversal records the rate of the transfer it reverses';
+
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'the transfer this one reverses';
+
+COMMENT ON COLUMN "transfers"."reversed_amount" IS 'how much of amount was reversed so far';
+
+COMMENT ON COLUMN "transfers"."memo" IS 'what the transfer is for, as the sender put it';
+
+COMMENT ON COLUMN "transfers"."reference" IS 'identifier of the payment in another system, such as an invoice number';
+
+COMMENT ON COLUMN "transfers"."metadata" IS 'string values by key, copied to both entries';
+
+COMMENT ON COLUMN "transfers"."initiated_by" IS 'user who made the transfer, their transfer limits count it, null when the bank made it';
+
+COMMENT ON COLUMN "idempotency_keys"."response" IS 'serialized result of the first request made with the key';
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'amount of to_currency one unit of from_currency buys';
+
+COMMENT ON COLUMN "scheduled_transfers"."amount" IS 'must be positive';
+
+COMMENT ON COLUMN "scheduled_transfers"."recurrence" IS 'once, weekly, monthly or last_business_day';
+
+COMMENT ON COLUMN "scheduled_transfers"."day_of_month" IS 'day a monthly transfer runs on, clamped to the end of shorter months';
+
+COMMENT ON COLUMN "scheduled_transfers"."status" IS 'active, completed, failed or cancelled';
+
+COMMENT ON COLUMN "scheduled_transfers"."next_run_at" IS 'the occurrence that is due next';
+
+COMMENT ON COLUMN "scheduled_transfers"."next_attempt_at" IS 'when the next occurrence is tried, later than next_run_at after a failed attempt';
+
+COMMENT ON COLUMN "scheduled_transfers"."attempts" IS 'failed attempts of the next occurrence';
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."status" IS 'succeeded or failed';
+
+COMMENT ON COLUMN "holds"."amount" IS 'must be positive';
+
+COMMENT ON COLUMN "holds"."status" IS 'authorized, captured, voided or expired';
+
+COMMENT ON COLUMN "holds"."captured_amount" IS 'part of amount that was transferred on capture';
+
+COMMENT ON COLUMN "holds"."initiated_by" IS 'user who authorized the hold, their transfer limits count its capture';
+
+COMMENT ON COLUMN "transfer_limits"."username" IS 'set for a limit on all accounts of a user, per currency';
+
+COMMENT ON COLUMN "transfer_limits"."account_id" IS 'set for a limit on a single account';
+
+COMMENT ON COLUMN "transfer_limits"."period" IS 'daily or monthly, a rolling window ending now';
+
+COMMENT ON COLUMN "transfer_limits"."max_amount" IS 'total outgoing amount allowed within the period';
+
+COMMENT ON COLUMN "transfer_limits"."max_count" IS 'number of outgoing transfers allowed within the period';
+
+COMMENT ON COLUMN "transfer_requests"."amount" IS 'must be positive';
+
+COMMENT ON COLUMN "transfer_requests"."status" IS 'pending, approved or rejected';
+
+COMMENT ON COLUMN "transfer_requests"."transfer_id" IS 'the transfer made on approval';
+
+COMMENT ON COLUMN "transfer_requests"."metadata" IS 'string values by key, given to the transfer on approval along with the memo and reference';
+
+COMMENT ON COLUMN "balance_snapshots"."balance" IS 'balance of the account after every entry made before taken_at';
+
+COMMENT ON COLUMN "account_products"."annual_interest_rate" IS 'fraction of the balance earned over a year, 0.05 for 5%';
+
+COMMENT ON COLUMN "account_products"."day_count_convention" IS 'act/365, act/360 or 30/360';
+
+COMMENT ON COLUMN "account_products"."max_accounts" IS 'how many open accounts of the product a user may have in each currency';
+
+COMMENT ON COLUMN "account_products"."overdraft_limit" IS 'overdraft limit accounts of the product are opened with';
+
+COMMENT ON COLUMN "account_products"."overdraft_annual_rate" IS 'overdraft interest accounts of the product are opened with';
+
+COMMENT ON COLUMN "account_products"."overdraft_daily_fee" IS 'overdraft fee accounts of the product are opened with';
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'balance at the end of the day';
+
+COMMENT ON COLUMN "interest_accruals"."amount" IS 'interest earned over the day, in fractions of a minor unit';
+
+COMMENT ON COLUMN "interest_capitalizations"."period" IS 'first day of the month the interest is paid for';
+
+COMMENT ON COLUMN "interest_capitalizations"."amount" IS 'whole minor units paid, the fractions left over are paid with the next month';
+
+COMMENT ON COLUMN "overdraft_charges"."balance" IS 'balance at the end of the day';
+
+COMMENT ON COLUMN "overdraft_charges"."waived" IS 'part of the interest and fee not charged because it would go over the overdraft limit';
+
+COMMENT ON COLUMN "notifications"."kind" IS 'overdraft_started or overdraft_charged';
+
+COMMENT ON COLUMN "account_members"."role" IS 'owner, co_owner, viewer or payer';
+
+COMMENT ON COLUMN "cash_movements"."kind" IS 'deposit or withdrawal';
+
+COMMENT ON COLUMN "cash_movements"."fee" IS 'charged to the account and paid into the bank''s fees account';
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 alphabetic code';
+
+COMMENT ON COLUMN "currencies"."numeric_code" IS 'ISO 4217 numeric code';
+
+COMMENT ON COLUMN "currencies"."minor_units" IS 'decimals of the currency, amounts are stored in its minor unit';
+
+COMMENT ON COLUMN "currencies"."enabled" IS 'only enabled currencies can be used for new accounts, transfers and rates';
 
 ALTER TABLE "sessions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
 
 ALTER TABLE "accounts" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
 
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("actor") REFERENCES "users" ("username");
+
 ALTER TABLE "entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
 
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
 ALTER TABLE "transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
 
 ALTER TABLE "transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "exchange_quotes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_approvers" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_approvers" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("requested_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "balance_snapshots" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("product") REFERENCES "account_products" ("code");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_capitalizations" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_capitalizations" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "overdraft_charges" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "overdraft_charges" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("banker") REFERENCES "users" ("username");
+
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("fee_transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("from_currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("to_currency") REFERENCES "currencies" ("code");
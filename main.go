@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"log"
+	"os"
 
 	_ "github.com/lib/pq"
 	"github.com/nokkvi/simplebank/api"
 	db "github.com/nokkvi/simplebank/db/sqlc"
 	"github.com/nokkvi/simplebank/util"
+	"github.com/nokkvi/simplebank/worker"
 )
 
 func main() {
@@ -21,6 +25,46 @@ func main() {
 	}
 
 	store := db.NewStore(conn)
+
+	if len(os.Args) > 1 {
+		runCommand(store, os.Args[1])
+		return
+	}
+	if config.CurrenciesFile != "" {
+		loadCurrencies(store, config.CurrenciesFile)
+	}
+	err = db.LoadCurrencyRegistry(context.Background(), store)
+	if err != nil {
+		log.Fatal("cannot load currency registry:", err)
+	}
+
+	if config.ExchangeRatesFile != "" {
+		loadExchangeRates(store, config.ExchangeRatesFile)
+	}
+
+	assignAccountNumbers(store, config)
+
+	currencyRefresher := worker.NewCurrencyRefresher(config, store)
+	go currencyRefresher.Start(context.Background())
+
+	scheduler := worker.NewScheduler(config, store)
+	go scheduler.Start(context.Background())
+
+	holdExpirer := worker.NewHoldExpirer(config, store)
+	go holdExpirer.Start(context.Background())
+
+	reconciler := worker.NewReconciler(config, store)
+	go reconciler.Start(context.Background())
+
+	balanceSnapshotter := worker.NewBalanceSnapshotter(config, store)
+	go balanceSnapshotter.Start(context.Background())
+
+	interestAccruer := worker.NewInterestAccruer(config, store)
+	go interestAccruer.Start(context.Background())
+
+	overdraftCharger := worker.NewOverdraftCharger(config, store)
+	go overdraftCharger.Start(context.Background())
+
 	server, err := api.NewServer(config, store)
 	if err != nil {
 		log.Fatal("cannot create server:", err)
@@ -32,3 +76,96 @@ func main() {
 	}
 
 }
+
+// loadCurrencies stores the currencies from the given file, replacing the current ones with the same codes
+func loadCurrencies(store db.Store, path string) {
+	currencies, err := util.LoadCurrencies(path)
+	if err != nil {
+		log.Fatal("cannot load currencies:", err)
+	}
+
+	for _, currency := range currencies {
+		_, err = store.UpsertCurrencyTx(context.Background(), db.UpsertCurrencyParams{
+			Code: currency.Code,
+			NumericCode: currency.NumericCode,
+			MinorUnits: currency.MinorUnits,
+			Symbol: currency.Symbol,
+			Enabled: currency.Enabled,
+		})
+		if err != nil {
+			log.Fatal("cannot store currency:", err)
+		}
+	}
+
+	log.Printf("loaded %d currencies from %s", len(currencies), path)
+}
+
+// assignAccountNumbers numbers the accounts opened before account numbers with the bank codes from the config
+func assignAccountNumbers(store db.Store, config util.Config) {
+	count, err := store.AssignAccountNumbers(context.Background(), db.AssignAccountNumbersParams{
+		CountryCode: config.BankCountryCode,
+		BankCode: config.BankCode,
+	})
+	if err != nil {
+		log.Fatal("cannot assign account numbers:", err)
+	}
+
+	if count > 0 {
+		log.Printf("assigned account numbers to %d accounts", count)
+	}
+}
+
+// loadExchangeRates stores the exchange rates from the given file, replacing the current ones for the same currencies
+func loadExchangeRates(store db.Store, path string) {
+	rates, err := util.LoadExchangeRates(path)
+	if err != nil {
+		log.Fatal("cannot load exchange rates:", err)
+	}
+
+	arg := db.UpsertExchangeRatesTxParams{
+		Rates: make([]db.UpsertExchangeRateParams, 0, len(rates)),
+	}
+	for _, rate := range rates {
+		arg.Rates = append(arg.Rates, db.UpsertExchangeRateParams{
+			FromCurrency: rate.FromCurrency,
+			ToCurrency: rate.ToCurrency,
+			Rate: rate.Rate,
+		})
+	}
+
+	_, err = store.UpsertExchangeRatesTx(context.Background(), arg)
+	if err != nil {
+		log.Fatal("cannot store exchange rates:", err)
+	}
+
+	log.Printf("loaded %d exchange rates from %s", len(rates), path)
+}
+
+// runCommand runs a one-off command instead of the server
+func runCommand(store db.Store, command string) {
+	switch command {
+	case "reconcile":
+		reconcile(store)
+	default:
+		log.Fatalf("unknown command %q, the only command is reconcile", command)
+	}
+}
+
+// reconcile prints the reconciliation report of the ledger as JSON and exits with status 1 if it found any discrepancy
+func reconcile(store db.Store) {
+	report, err := store.ReconcileTx(context.Background())
+	if err != nil {
+		log.Fatal("cannot reconcile ledger:", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(report)
+	if err != nil {
+		log.Fatal("cannot write reconciliation report:", err)
+	}
+
+	if report.Discrepancies() > 0 {
+		os.Exit(1)
+	}
+}
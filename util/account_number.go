@@ -0,0 +1,73 @@
+package util
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+)
+
+// accountNumberDigits is how many digits after the bank code tell the bank's accounts apart
+const accountNumberDigits = 10
+
+var (
+	countryCodeFormat   = regexp.MustCompile(`^[A-Z]{2}$`)
+	bankCodeFormat      = regexp.MustCompile(`^[A-Z0-9]{4}$`)
+	accountNumberFormat = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{4}[0-9]{10}$`)
+)
+
+// ValidateBankCode checks the country and bank codes that account numbers are made with
+func ValidateBankCode(countryCode string, bankCode string) error {
+	if !countryCodeFormat.MatchString(countryCode) {
+		return fmt.Errorf("invalid country code %q", countryCode)
+	}
+	if !bankCodeFormat.MatchString(bankCode) {
+		return fmt.Errorf("invalid bank code %q", bankCode)
+	}
+
+	return nil
+}
+
+// NewAccountNumber generates a random IBAN-style account number, like XB60SMPL0123456789
+// It is the country code, two check digits, the bank code and ten random digits, so it doesn't give away how many accounts there are
+func NewAccountNumber(countryCode string, bankCode string) (string, error) {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(accountNumberDigits), nil)
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+
+	bban := fmt.Sprintf("%s%0*d", bankCode, accountNumberDigits, n)
+	return countryCode + checkDigits(countryCode, bban) + bban, nil
+}
+
+// ValidateAccountNumber checks that an account number is well formed and that its check digits match,
+// which catches a single mistyped character and most swapped ones
+func ValidateAccountNumber(number string) error {
+	if !accountNumberFormat.MatchString(number) {
+		return fmt.Errorf("invalid account number %q", number)
+	}
+	if mod97(number[4:]+number[:4]) != 1 {
+		return fmt.Errorf("invalid account number %q, its check digits don't match", number)
+	}
+
+	return nil
+}
+
+// checkDigits returns the two check digits that make the account number's remainder by 97 one, as in an IBAN
+func checkDigits(countryCode string, bban string) string {
+	return fmt.Sprintf("%02d", 98-mod97(bban+countryCode+"00"))
+}
+
+// mod97 returns the remainder by 97 of the number made by writing each letter as its place in the alphabet plus 9, A as 10
+func mod97(s string) int {
+	remainder := 0
+	for _, c := range s {
+		if c >= 'A' && c <= 'Z' {
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		}
+	}
+	return remainder
+}
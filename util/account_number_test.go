@@ -0,0 +1,64 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAccountNumber(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		number, err := NewAccountNumber("XB", "SMPL")
+		require.NoError(t, err)
+		require.Len(t, number, 18)
+		require.Equal(t, "XB", number[:2])
+		require.Equal(t, "SMPL", number[4:8])
+		require.NoError(t, ValidateAccountNumber(number))
+	}
+}
+
+func TestValidateAccountNumber(t *testing.T) {
+	require.NoError(t, ValidateAccountNumber("XB60SMPL0123456789"))
+	// the numbers migrated accounts get from their id
+	require.NoError(t, ValidateAccountNumber("XB19SMPL0000000001"))
+
+	testCases := []struct {
+		name   string
+		number string
+	}{
+		{"MistypedDigit", "XB60SMPL0123456788"},
+		{"SwappedDigits", "XB60SMPL1023456789"},
+		{"WrongCheckDigits", "XB61SMPL0123456789"},
+		{"Lowercase", "xb60smpl0123456789"},
+		{"Spaces", "XB60 SMPL 0123 4567 89"},
+		{"TooShort", "XB60SMPL012345678"},
+		{"Empty", ""},
+	}
+
+	for _, tc := range testCases {
+		require.Error(t, ValidateAccountNumber(tc.number), tc.name)
+	}
+
+	// every single mistyped digit is caught
+	number := []byte("XB60SMPL0123456789")
+	for i := 8; i < len(number); i++ {
+		original := number[i]
+		for d := byte('0'); d <= '9'; d++ {
+			if d == original {
+				continue
+			}
+			number[i] = d
+			require.Error(t, ValidateAccountNumber(string(number)))
+		}
+		number[i] = original
+	}
+}
+
+func TestValidateBankCode(t *testing.T) {
+	require.NoError(t, ValidateBankCode("XB", "SMPL"))
+	require.NoError(t, ValidateBankCode("IS", "0133"))
+	require.Error(t, ValidateBankCode("XBX", "SMPL"))
+	require.Error(t, ValidateBankCode("xb", "SMPL"))
+	require.Error(t, ValidateBankCode("XB", "SMP"))
+	require.Error(t, ValidateBankCode("XB", "smpl"))
+}
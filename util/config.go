@@ -15,6 +15,27 @@ type Config struct {
 	TokenSymmetricKey string `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	BankCountryCode string `mapstructure:"BANK_COUNTRY_CODE"`
+	BankCode string `mapstructure:"BANK_CODE"`
+	CurrenciesFile string `mapstructure:"CURRENCIES_FILE"`
+	ExchangeRatesFile string `mapstructure:"EXCHANGE_RATES_FILE"`
+	CurrencyRefreshInterval time.Duration `mapstructure:"CURRENCY_REFRESH_INTERVAL"`
+	ExchangeQuoteDuration time.Duration `mapstructure:"EXCHANGE_QUOTE_DURATION"`
+	SchedulerInterval time.Duration `mapstructure:"SCHEDULER_INTERVAL"`
+	ScheduledTransferMaxAttempts int32 `mapstructure:"SCHEDULED_TRANSFER_MAX_ATTEMPTS"`
+	ScheduledTransferRetryDelay time.Duration `mapstructure:"SCHEDULED_TRANSFER_RETRY_DELAY"`
+	HoldDuration time.Duration `mapstructure:"HOLD_DURATION"`
+	HoldExpiryInterval time.Duration `mapstructure:"HOLD_EXPIRY_INTERVAL"`
+	DailyTransferLimitAmount int64 `mapstructure:"DAILY_TRANSFER_LIMIT_AMOUNT"`
+	DailyTransferLimitCount int32 `mapstructure:"DAILY_TRANSFER_LIMIT_COUNT"`
+	MonthlyTransferLimitAmount int64 `mapstructure:"MONTHLY_TRANSFER_LIMIT_AMOUNT"`
+	MonthlyTransferLimitCount int32 `mapstructure:"MONTHLY_TRANSFER_LIMIT_COUNT"`
+	BalanceSnapshotInterval time.Duration `mapstructure:"BALANCE_SNAPSHOT_INTERVAL"`
+	ReconciliationInterval time.Duration `mapstructure:"RECONCILIATION_INTERVAL"`
+	InterestAccrualInterval time.Duration `mapstructure:"INTEREST_ACCRUAL_INTERVAL"`
+	InterestAccrualDelay time.Duration `mapstructure:"INTEREST_ACCRUAL_DELAY"`
+	OverdraftChargeInterval time.Duration `mapstructure:"OVERDRAFT_CHARGE_INTERVAL"`
+	OverdraftChargeDelay time.Duration `mapstructure:"OVERDRAFT_CHARGE_DELAY"`
 }
 
 // LoadConfig read configuration from file or environment variables
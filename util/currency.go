@@ -1,18 +1,190 @@
 package util
 
-// Constants for all supported currencies
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Constants for the currencies the bank started with, the registry holds them until it is loaded
 const (
 	USD = "USD"
 	EUR = "EUR"
 	CAD = "CAD"
 )
 
-// IsSupportedCurrency returns true if the currency is supported
-func IsSupportedCurrency(currency string) bool {
-	switch currency {
-	case USD, EUR, CAD:
-		return true
+// maxMinorUnits is the most decimals a currency may have, ISO 4217 has none with more than four
+const maxMinorUnits = 4
+
+// Currency is an ISO 4217 currency the bank may hold accounts in
+type Currency struct {
+	Code        string `json:"code"`
+	NumericCode int32  `json:"numeric_code"`
+	// MinorUnits is how many decimals amounts in the currency have, amounts are stored in the minor unit
+	MinorUnits int32  `json:"minor_units"`
+	Symbol     string `json:"symbol"`
+	// Enabled currencies are the ones new accounts, transfers and rates can use
+	Enabled bool `json:"enabled"`
+}
+
+var currencyCodeFormat = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Validate checks that the code is an ISO 4217 alphabetic code and that the numeric code and minor units are in range
+func (currency Currency) Validate() error {
+	if !currencyCodeFormat.MatchString(currency.Code) {
+		return fmt.Errorf("invalid currency code %q", currency.Code)
+	}
+	if currency.NumericCode < 1 || currency.NumericCode > 999 {
+		return fmt.Errorf("invalid numeric code %d for currency %s", currency.NumericCode, currency.Code)
+	}
+	if currency.MinorUnits < 0 || currency.MinorUnits > maxMinorUnits {
+		return fmt.Errorf("invalid minor units %d for currency %s", currency.MinorUnits, currency.Code)
+	}
+
+	return nil
+}
+
+// defaultCurrencies are in the registry until it is loaded from the database
+var defaultCurrencies = []Currency{
+	{Code: USD, NumericCode: 840, MinorUnits: 2, Symbol: "$", Enabled: true},
+	{Code: EUR, NumericCode: 978, MinorUnits: 2, Symbol: "€", Enabled: true},
+	{Code: CAD, NumericCode: 124, MinorUnits: 2, Symbol: "CA$", Enabled: true},
+}
+
+// currencyRegistry holds the currencies the bank knows about by code
+var currencyRegistry = struct {
+	sync.RWMutex
+	currencies map[string]Currency
+}{currencies: currencyMap(defaultCurrencies)}
+
+func currencyMap(currencies []Currency) map[string]Currency {
+	m := make(map[string]Currency, len(currencies))
+	for _, currency := range currencies {
+		m[currency.Code] = currency
+	}
+	return m
+}
+
+// SetCurrencies replaces the currencies in the registry
+func SetCurrencies(currencies []Currency) {
+	m := currencyMap(currencies)
+
+	currencyRegistry.Lock()
+	defer currencyRegistry.Unlock()
+	currencyRegistry.currencies = m
+}
+
+// PutCurrency adds a currency to the registry or replaces the one with the same code
+func PutCurrency(currency Currency) {
+	currencyRegistry.Lock()
+	defer currencyRegistry.Unlock()
+	currencyRegistry.currencies[currency.Code] = currency
+}
+
+// Currencies returns every currency in the registry, enabled or not
+func Currencies() []Currency {
+	currencyRegistry.RLock()
+	defer currencyRegistry.RUnlock()
+
+	currencies := make([]Currency, 0, len(currencyRegistry.currencies))
+	for _, currency := range currencyRegistry.currencies {
+		currencies = append(currencies, currency)
+	}
+	return currencies
+}
+
+// GetCurrency returns the currency with the code from the registry, enabled or not
+func GetCurrency(code string) (Currency, bool) {
+	currencyRegistry.RLock()
+	defer currencyRegistry.RUnlock()
+	currency, ok := currencyRegistry.currencies[code]
+	return currency, ok
+}
+
+// IsSupportedCurrency returns true if the currency is in the registry and enabled
+func IsSupportedCurrency(code string) bool {
+	currency, ok := GetCurrency(code)
+	return ok && currency.Enabled
+}
+
+// LoadCurrencies reads currencies from a .json or .csv file
+// A JSON file holds an array of currencies, a CSV file has one code,numeric_code,minor_units,symbol,enabled row per currency
+// and an optional header row
+func LoadCurrencies(path string) ([]Currency, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var currencies []Currency
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.NewDecoder(file).Decode(&currencies)
+	case ".csv":
+		currencies, err = readCurrenciesCSV(file)
+	default:
+		err = fmt.Errorf("unsupported currencies file format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, currency := range currencies {
+		if err := currency.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return currencies, nil
+}
+
+func readCurrenciesCSV(r io.Reader) ([]Currency, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 5
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) > 0 && records[0][0] == "code" {
+		records = records[1:]
+	}
+
+	currencies := make([]Currency, 0, len(records))
+	for _, record := range records {
+		numericCode, err := strconv.ParseInt(record[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid numeric code %q for currency %s", record[1], record[0])
+		}
+
+		minorUnits, err := strconv.ParseInt(record[2], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid minor units %q for currency %s", record[2], record[0])
+		}
+
+		enabled, err := strconv.ParseBool(record[4])
+		if err != nil {
+			return nil, fmt.Errorf("invalid enabled flag %q for currency %s", record[4], record[0])
+		}
+
+		currencies = append(currencies, Currency{
+			Code:        record[0],
+			NumericCode: int32(numericCode),
+			MinorUnits:  int32(minorUnits),
+			Symbol:      record[3],
+			Enabled:     enabled,
+		})
 	}
 
-	return false
-}
\ No newline at end of file
+	return currencies, nil
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSupportedCurrency(t *testing.T) {
+	t.Cleanup(func() { SetCurrencies(defaultCurrencies) })
+	require.Len(t, Currencies(), len(defaultCurrencies))
+
+	require.True(t, IsSupportedCurrency(USD))
+	require.False(t, IsSupportedCurrency("JPY"))
+
+	SetCurrencies([]Currency{
+		{Code: USD, NumericCode: 840, MinorUnits: 2, Symbol: "$", Enabled: false},
+		{Code: "JPY", NumericCode: 392, MinorUnits: 0, Symbol: "¥", Enabled: true},
+	})
+	require.False(t, IsSupportedCurrency(USD))
+	require.True(t, IsSupportedCurrency("JPY"))
+	require.False(t, IsSupportedCurrency(EUR))
+
+	// a disabled currency is still in the registry
+	currency, ok := GetCurrency(USD)
+	require.True(t, ok)
+	require.Equal(t, int32(2), currency.MinorUnits)
+
+	PutCurrency(Currency{Code: "KWD", NumericCode: 414, MinorUnits: 3, Enabled: true})
+	require.True(t, IsSupportedCurrency("KWD"))
+	require.True(t, IsSupportedCurrency("JPY"))
+}
+
+func TestCurrencyValidate(t *testing.T) {
+	require.NoError(t, Currency{Code: "KWD", NumericCode: 414, MinorUnits: 3}.Validate())
+	require.Error(t, Currency{Code: "kwd", NumericCode: 414, MinorUnits: 3}.Validate())
+	require.Error(t, Currency{Code: "KWDX", NumericCode: 414, MinorUnits: 3}.Validate())
+	require.Error(t, Currency{Code: "KWD", NumericCode: 0, MinorUnits: 3}.Validate())
+	require.Error(t, Currency{Code: "KWD", NumericCode: 1000, MinorUnits: 3}.Validate())
+	require.Error(t, Currency{Code: "KWD", NumericCode: 414, MinorUnits: 5}.Validate())
+}
+
+func TestLoadCurrencies(t *testing.T) {
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "currencies.csv")
+	err := os.WriteFile(csvPath, []byte("code,numeric_code,minor_units,symbol,enabled\nJPY,392,0,¥,true\nKWD,414,3,KD,false\n"), 0600)
+	require.NoError(t, err)
+
+	currencies, err := LoadCurrencies(csvPath)
+	require.NoError(t, err)
+	require.Equal(t, []Currency{
+		{Code: "JPY", NumericCode: 392, MinorUnits: 0, Symbol: "¥", Enabled: true},
+		{Code: "KWD", NumericCode: 414, MinorUnits: 3, Symbol: "KD", Enabled: false},
+	}, currencies)
+
+	jsonPath := filepath.Join(dir, "currencies.json")
+	err = os.WriteFile(jsonPath, []byte(`[{"code":"GBP","numeric_code":826,"minor_units":2,"symbol":"£","enabled":true}]`), 0600)
+	require.NoError(t, err)
+
+	currencies, err = LoadCurrencies(jsonPath)
+	require.NoError(t, err)
+	require.Equal(t, []Currency{{Code: "GBP", NumericCode: 826, MinorUnits: 2, Symbol: "£", Enabled: true}}, currencies)
+
+	invalidPath := filepath.Join(dir, "invalid.csv")
+	err = os.WriteFile(invalidPath, []byte("JPY,392,zero,¥,true\n"), 0600)
+	require.NoError(t, err)
+
+	_, err = LoadCurrencies(invalidPath)
+	require.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package util
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ExchangeRate is the amount of ToCurrency that one unit of FromCurrency buys
+type ExchangeRate struct {
+	FromCurrency string `json:"from_currency"`
+	ToCurrency   string `json:"to_currency"`
+	Rate         string `json:"rate"`
+}
+
+var rateFormat = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// ParseRate parses a decimal exchange rate and checks that it is positive
+func ParseRate(rate string) (*big.Rat, error) {
+	if !rateFormat.MatchString(rate) {
+		return nil, fmt.Errorf("invalid exchange rate %q", rate)
+	}
+
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid exchange rate %q", rate)
+	}
+
+	return r, nil
+}
+
+// ConvertAmount converts an amount in the minor unit of one currency to the minor unit of another at the given rate,
+// rounding down to a whole minor unit
+// The rate is between the major units, so the amount is also scaled by the difference in the currencies' decimals
+func ConvertAmount(amount int64, fromCurrency string, toCurrency string, rate string) (int64, error) {
+	r, err := ParseRate(rate)
+	if err != nil {
+		return 0, err
+	}
+
+	scale := new(big.Rat).SetFrac(minorUnitScale(minorUnits(toCurrency)), minorUnitScale(minorUnits(fromCurrency)))
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), r)
+	converted.Mul(converted, scale)
+	result := roundRat(converted, RoundDown)
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("converted amount of %d at rate %s overflows", amount, rate)
+	}
+
+	return result.Int64(), nil
+}
+
+// Validate checks that both currencies are supported and different, and that the rate is valid
+func (rate ExchangeRate) Validate() error {
+	if !IsSupportedCurrency(rate.FromCurrency) {
+		return fmt.Errorf("unsupported currency %q", rate.FromCurrency)
+	}
+	if !IsSupportedCurrency(rate.ToCurrency) {
+		return fmt.Errorf("unsupported currency %q", rate.ToCurrency)
+	}
+	if rate.FromCurrency == rate.ToCurrency {
+		return fmt.Errorf("exchange rate from %s to itself", rate.FromCurrency)
+	}
+
+	_, err := ParseRate(rate.Rate)
+	return err
+}
+
+// LoadExchangeRates reads exchange rates from a .json or .csv file
+// A JSON file holds an array of rates, a CSV file has one from_currency,to_currency,rate row per rate and an optional header row
+func LoadExchangeRates(path string) ([]ExchangeRate, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rates []ExchangeRate
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.NewDecoder(file).Decode(&rates)
+	case ".csv":
+		rates, err = readExchangeRatesCSV(file)
+	default:
+		err = fmt.Errorf("unsupported exchange rates file format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rate := range rates {
+		if err := rate.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return rates, nil
+}
+
+func readExchangeRatesCSV(r io.Reader) ([]ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) > 0 && records[0][0] == "from_currency" {
+		records = records[1:]
+	}
+
+	rates := make([]ExchangeRate, 0, len(records))
+	for _, record := range records {
+		rates = append(rates, ExchangeRate{
+			FromCurrency: record[0],
+			ToCurrency:   record[1],
+			Rate:         record[2],
+		})
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,140 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertAmount(t *testing.T) {
+	amount, err := ConvertAmount(1000, USD, EUR, "0.9215")
+	require.NoError(t, err)
+	require.Equal(t, int64(921), amount)
+
+	amount, err = ConvertAmount(1000, USD, EUR, "1")
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), amount)
+
+	_, err = ConvertAmount(1000, USD, EUR, "-1")
+	require.Error(t, err)
+
+	_, err = ConvertAmount(1000, USD, EUR, "0")
+	require.Error(t, err)
+
+	_, err = ConvertAmount(9223372036854775807, USD, EUR, "2")
+	require.Error(t, err)
+}
+
+func TestConvertAmountMinorUnits(t *testing.T) {
+	SetCurrencies(append(defaultCurrencies,
+		Currency{Code: "JPY", NumericCode: 392, MinorUnits: 0, Symbol: "¥", Enabled: true},
+		Currency{Code: "KWD", NumericCode: 414, MinorUnits: 3, Symbol: "KD", Enabled: true},
+	))
+	defer SetCurrencies(defaultCurrencies)
+
+	testCases := []struct {
+		name         string
+		amount       int64
+		fromCurrency string
+		toCurrency   string
+		rate         string
+		converted    int64
+	}{
+		{
+			// 10.00 USD at 150 yen a dollar
+			name:         "TwoToZero",
+			amount:       1000,
+			fromCurrency: USD,
+			toCurrency:   "JPY",
+			rate:         "150",
+			converted:    1500,
+		},
+		{
+			// 1500 yen at 0.0067 dollars a yen, 10.05 USD
+			name:         "ZeroToTwo",
+			amount:       1500,
+			fromCurrency: "JPY",
+			toCurrency:   USD,
+			rate:         "0.0067",
+			converted:    1005,
+		},
+		{
+			// 10.00 USD at 0.307 dinar a dollar, 3.070 KWD
+			name:         "TwoToThree",
+			amount:       1000,
+			fromCurrency: USD,
+			toCurrency:   "KWD",
+			rate:         "0.307",
+			converted:    3070,
+		},
+		{
+			// 1.234 KWD at 3.25 dollars a dinar, 4.0105 rounds down to 4.01 USD
+			name:         "ThreeToTwo",
+			amount:       1234,
+			fromCurrency: "KWD",
+			toCurrency:   USD,
+			rate:         "3.25",
+			converted:    401,
+		},
+		{
+			// 1000 yen at 0.00205 dinar a yen, 2.050 KWD
+			name:         "ZeroToThree",
+			amount:       1000,
+			fromCurrency: "JPY",
+			toCurrency:   "KWD",
+			rate:         "0.00205",
+			converted:    2050,
+		},
+		{
+			// 0.001 KWD at 487 yen a dinar, 0.487 yen rounds down to nothing
+			name:         "ThreeToZero",
+			amount:       1,
+			fromCurrency: "KWD",
+			toCurrency:   "JPY",
+			rate:         "487",
+			converted:    0,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			converted, err := ConvertAmount(tc.amount, tc.fromCurrency, tc.toCurrency, tc.rate)
+			require.NoError(t, err)
+			require.Equal(t, tc.converted, converted)
+		})
+	}
+}
+
+func TestLoadExchangeRates(t *testing.T) {
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "rates.csv")
+	err := os.WriteFile(csvPath, []byte("from_currency,to_currency,rate\nUSD,EUR,0.92\nEUR,USD,1.08\n"), 0600)
+	require.NoError(t, err)
+
+	rates, err := LoadExchangeRates(csvPath)
+	require.NoError(t, err)
+	require.Equal(t, []ExchangeRate{
+		{FromCurrency: USD, ToCurrency: EUR, Rate: "0.92"},
+		{FromCurrency: EUR, ToCurrency: USD, Rate: "1.08"},
+	}, rates)
+
+	jsonPath := filepath.Join(dir, "rates.json")
+	err = os.WriteFile(jsonPath, []byte(`[{"from_currency":"USD","to_currency":"CAD","rate":"1.37"}]`), 0600)
+	require.NoError(t, err)
+
+	rates, err = LoadExchangeRates(jsonPath)
+	require.NoError(t, err)
+	require.Equal(t, []ExchangeRate{{FromCurrency: USD, ToCurrency: CAD, Rate: "1.37"}}, rates)
+
+	invalidPath := filepath.Join(dir, "invalid.csv")
+	err = os.WriteFile(invalidPath, []byte("USD,XYZ,1.5\n"), 0600)
+	require.NoError(t, err)
+
+	_, err = LoadExchangeRates(invalidPath)
+	require.Error(t, err)
+}
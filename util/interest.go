@@ -0,0 +1,102 @@
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Constants for all supported day count conventions of an interest rate
+const (
+	DayCountActual365 = "act/365"
+	DayCountActual360 = "act/360"
+	DayCount30360     = "30/360"
+)
+
+// interestPrecision is the number of decimals accrued interest is kept to, in fractions of a minor unit
+const interestPrecision = 10
+
+// IsSupportedDayCount returns true if the day count convention is supported
+func IsSupportedDayCount(convention string) bool {
+	switch convention {
+	case DayCountActual365, DayCountActual360, DayCount30360:
+		return true
+	}
+
+	return false
+}
+
+// ParseInterestRate parses a decimal annual interest rate, 0.05 for 5%, and checks that it is at most 100%
+func ParseInterestRate(rate string) (*big.Rat, error) {
+	if !rateFormat.MatchString(rate) {
+		return nil, fmt.Errorf("invalid interest rate %q", rate)
+	}
+
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Cmp(big.NewRat(1, 1)) > 0 {
+		return nil, fmt.Errorf("invalid interest rate %q", rate)
+	}
+
+	return r, nil
+}
+
+// DayFraction returns the fraction of a year that the day counts for under the convention
+// Under 30/360 every month counts for 30 days, so the 31st counts for nothing and the last day of February makes up the rest of its month
+func DayFraction(convention string, day time.Time) (*big.Rat, error) {
+	switch convention {
+	case DayCountActual365:
+		return big.NewRat(1, 365), nil
+	case DayCountActual360:
+		return big.NewRat(1, 360), nil
+	case DayCount30360:
+		year, month, d := day.Date()
+		days := 1
+		lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		if d == 31 {
+			days = 0
+		} else if month == time.February && d == lastDay {
+			days = 30 - lastDay + 1
+		}
+		return big.NewRat(int64(days), 360), nil
+	}
+
+	return nil, fmt.Errorf("unsupported day count convention %q", convention)
+}
+
+// DailyInterest returns the interest a balance earns over a day at an annual rate, in fractions of a minor unit
+// Negative balances earn no interest
+func DailyInterest(balance int64, annualRate string, convention string, day time.Time) (*big.Rat, error) {
+	rate, err := ParseInterestRate(annualRate)
+	if err != nil {
+		return nil, err
+	}
+
+	fraction, err := DayFraction(convention, day)
+	if err != nil {
+		return nil, err
+	}
+
+	if balance <= 0 {
+		return new(big.Rat), nil
+	}
+
+	interest := new(big.Rat).Mul(new(big.Rat).SetInt64(balance), rate)
+	return interest.Mul(interest, fraction), nil
+}
+
+// FormatInterest formats accrued interest to the precision it is stored with, rounding down
+func FormatInterest(interest *big.Rat) string {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(interestPrecision), nil)
+	scaled := new(big.Int).Mul(interest.Num(), scale)
+	scaled.Quo(scaled, interest.Denom())
+	return new(big.Rat).SetFrac(scaled, scale).FloatString(interestPrecision)
+}
+
+// RoundUpInterest rounds interest up to a whole minor unit, for the interest the bank charges
+func RoundUpInterest(interest *big.Rat) int64 {
+	rounded, remainder := new(big.Int).QuoRem(interest.Num(), interest.Denom(), new(big.Int))
+	if remainder.Sign() > 0 {
+		rounded.Add(rounded, big.NewInt(1))
+	}
+	return rounded.Int64()
+}
@@ -0,0 +1,80 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInterestRate(t *testing.T) {
+	rate, err := ParseInterestRate("0.05")
+	require.NoError(t, err)
+	require.Equal(t, big.NewRat(1, 20), rate)
+
+	rate, err = ParseInterestRate("0")
+	require.NoError(t, err)
+	require.Zero(t, rate.Sign())
+
+	for _, invalid := range []string{"", "-0.05", "5%", "1.5", "abc"} {
+		_, err = ParseInterestRate(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestDayFraction(t *testing.T) {
+	day := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+	fraction, err := DayFraction(DayCountActual365, day)
+	require.NoError(t, err)
+	require.Equal(t, big.NewRat(1, 365), fraction)
+
+	fraction, err = DayFraction(DayCountActual360, day)
+	require.NoError(t, err)
+	require.Equal(t, big.NewRat(1, 360), fraction)
+
+	_, err = DayFraction("actual", day)
+	require.Error(t, err)
+}
+
+func TestDayFraction30360(t *testing.T) {
+	// every month counts for 30 days, whatever its length
+	for _, year := range []int{2023, 2024} {
+		for month := time.January; month <= time.December; month++ {
+			total := new(big.Rat)
+			for day := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC); day.Month() == month; day = day.AddDate(0, 0, 1) {
+				fraction, err := DayFraction(DayCount30360, day)
+				require.NoError(t, err)
+				total.Add(total, fraction)
+			}
+			require.Equal(t, big.NewRat(30, 360), total, "%d-%02d", year, month)
+		}
+	}
+}
+
+func TestDailyInterest(t *testing.T) {
+	day := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	interest, err := DailyInterest(365_000, "0.05", DayCountActual365, day)
+	require.NoError(t, err)
+	require.Equal(t, big.NewRat(50, 1), interest)
+
+	interest, err = DailyInterest(100, "0.05", DayCountActual360, day)
+	require.NoError(t, err)
+	require.Equal(t, "0.0138888888", FormatInterest(interest))
+
+	interest, err = DailyInterest(-100, "0.05", DayCountActual365, day)
+	require.NoError(t, err)
+	require.Zero(t, interest.Sign())
+
+	_, err = DailyInterest(100, "5%", DayCountActual365, day)
+	require.Error(t, err)
+}
+
+func TestRoundUpInterest(t *testing.T) {
+	require.Equal(t, int64(0), RoundUpInterest(new(big.Rat)))
+	require.Equal(t, int64(1), RoundUpInterest(big.NewRat(1, 365)))
+	require.Equal(t, int64(2), RoundUpInterest(big.NewRat(2, 1)))
+	require.Equal(t, int64(3), RoundUpInterest(big.NewRat(5, 2)))
+}
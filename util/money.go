@@ -0,0 +1,349 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+// RoundingMode is how an amount that falls between two minor units is rounded to one of them
+type RoundingMode int
+
+// Constants for all supported rounding modes
+const (
+	// RoundDown rounds toward zero
+	RoundDown RoundingMode = iota
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundHalfUp rounds to the nearest minor unit, away from zero when it is halfway
+	RoundHalfUp
+	// RoundHalfEven rounds to the nearest minor unit, to the even one when it is halfway
+	RoundHalfEven
+)
+
+// defaultMinorUnits is how many decimals amounts in a currency that isn't in the registry are taken to have
+const defaultMinorUnits = 2
+
+// Errors returned by arithmetic on amounts of money
+var (
+	ErrMoneyOverflow    = errors.New("amount of money overflows")
+	ErrCurrencyMismatch = errors.New("amounts of money are in different currencies")
+)
+
+// Money is an amount in the minor unit of its currency, cents for USD
+// It is encoded in JSON as a decimal string in the major unit, "10.00" for ten dollars
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney returns the amount of minor units in the currency
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// minorUnits returns how many decimals amounts in the currency have
+func minorUnits(currency string) int32 {
+	if c, ok := GetCurrency(currency); ok {
+		return c.MinorUnits
+	}
+	return defaultMinorUnits
+}
+
+func minorUnitScale(units int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(units)), nil)
+}
+
+var decimalFormat = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// parseDecimal parses an exact decimal number like "10.00" or "-0.5"
+func parseDecimal(decimal string) (*big.Rat, error) {
+	if !decimalFormat.MatchString(decimal) {
+		return nil, fmt.Errorf("invalid amount %q", decimal)
+	}
+
+	r, ok := new(big.Rat).SetString(decimal)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", decimal)
+	}
+
+	return r, nil
+}
+
+// ParseMoney parses a decimal amount in the major unit of the currency, "10.00" or "10" for ten dollars
+// It fails rather than round when the amount has more decimals than the currency, unless they are zeros
+func ParseMoney(decimal string, currency string) (Money, error) {
+	r, err := parseDecimal(decimal)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return ratMoney(r, currency, decimal)
+}
+
+// MajorUnitMoney returns a whole number of major units of the currency, 10 for ten dollars or ten yen
+func MajorUnitMoney(amount int64, currency string) (Money, error) {
+	return ratMoney(new(big.Rat).SetInt64(amount), currency, strconv.FormatInt(amount, 10))
+}
+
+// ratMoney converts an amount in the major unit of the currency to minor units, text is the amount as given for errors
+func ratMoney(r *big.Rat, currency string, text string) (Money, error) {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(minorUnitScale(minorUnits(currency))))
+	if !scaled.IsInt() {
+		return Money{}, fmt.Errorf("amount %q has more decimals than %s allows", text, currency)
+	}
+	if !scaled.Num().IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return NewMoney(scaled.Num().Int64(), currency), nil
+}
+
+// Decimal formats the amount in the major unit of its currency, "10.00" for 1000 cents
+func (money Money) Decimal() string {
+	units := minorUnits(money.Currency)
+	return new(big.Rat).SetFrac(big.NewInt(money.Amount), minorUnitScale(units)).FloatString(int(units))
+}
+
+// String formats the amount with its currency, like "10.00 USD"
+func (money Money) String() string {
+	return money.Decimal() + " " + money.Currency
+}
+
+// MarshalJSON encodes the amount as a decimal string in the major unit of its currency
+func (money Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(money.Decimal())
+}
+
+// UnmarshalJSON reads a decimal string in the major unit of the currency already set on the money
+func (money *Money) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("invalid amount %s, it should be a decimal string", data)
+	}
+
+	m, err := ParseMoney(text, money.Currency)
+	if err != nil {
+		return err
+	}
+	*money = m
+	return nil
+}
+
+// Sign returns -1, 0 or 1 for a negative, zero or positive amount
+func (money Money) Sign() int {
+	switch {
+	case money.Amount < 0:
+		return -1
+	case money.Amount > 0:
+		return 1
+	}
+	return 0
+}
+
+func (money Money) sameCurrency(other Money) error {
+	if money.Currency != other.Currency {
+		return fmt.Errorf("%w: %s vs %s", ErrCurrencyMismatch, money.Currency, other.Currency)
+	}
+	return nil
+}
+
+// Add returns the sum of two amounts in the same currency
+func (money Money) Add(other Money) (Money, error) {
+	if err := money.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	if (other.Amount > 0 && money.Amount > math.MaxInt64-other.Amount) ||
+		(other.Amount < 0 && money.Amount < math.MinInt64-other.Amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return NewMoney(money.Amount+other.Amount, money.Currency), nil
+}
+
+// Sub returns the difference of two amounts in the same currency
+func (money Money) Sub(other Money) (Money, error) {
+	if err := money.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	if (other.Amount < 0 && money.Amount > math.MaxInt64+other.Amount) ||
+		(other.Amount > 0 && money.Amount < math.MinInt64+other.Amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return NewMoney(money.Amount-other.Amount, money.Currency), nil
+}
+
+// Mul multiplies the amount by a factor, such as an exchange or interest rate, rounding to a whole minor unit
+func (money Money) Mul(factor *big.Rat, mode RoundingMode) (Money, error) {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(money.Amount), factor)
+	rounded := roundRat(product, mode)
+	if !rounded.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return NewMoney(rounded.Int64(), money.Currency), nil
+}
+
+// roundRat rounds a number to a whole one
+func roundRat(r *big.Rat, mode RoundingMode) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	// the quotient is rounded toward zero, rounding away from it moves one further in the direction of the sign
+	away := big.NewInt(int64(r.Sign()))
+	switch mode {
+	case RoundUp:
+		return quotient.Add(quotient, away)
+	case RoundHalfUp, RoundHalfEven:
+		twice := new(big.Int).Abs(remainder)
+		switch twice.Lsh(twice, 1).Cmp(r.Denom()) {
+		case 1:
+			return quotient.Add(quotient, away)
+		case 0:
+			if mode == RoundHalfUp || quotient.Bit(0) == 1 {
+				return quotient.Add(quotient, away)
+			}
+		}
+	}
+
+	return quotient
+}
+
+// Allocate splits the amount into parts in proportion to the ratios without losing a minor unit
+// Each part gets its share rounded toward zero, and what is left over goes a minor unit at a time
+// to the parts with a non-zero ratio, first ones first
+func (money Money) Allocate(ratios ...int64) ([]Money, error) {
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, fmt.Errorf("invalid allocation ratio %d", ratio)
+		}
+		total.Add(total, big.NewInt(ratio))
+	}
+	if total.Sign() == 0 {
+		return nil, errors.New("allocation ratios add up to zero")
+	}
+
+	parts := make([]Money, len(ratios))
+	remainder := money.Amount
+	for i, ratio := range ratios {
+		share := new(big.Int).Mul(big.NewInt(money.Amount), big.NewInt(ratio))
+		share.Quo(share, total)
+		parts[i] = NewMoney(share.Int64(), money.Currency)
+		remainder -= share.Int64()
+	}
+
+	unit := int64(1)
+	if remainder < 0 {
+		unit = -1
+	}
+	for i := 0; remainder != 0; i++ {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].Amount += unit
+		remainder -= unit
+	}
+
+	return parts, nil
+}
+
+// Split splits the amount into n parts that differ by at most a minor unit, the larger ones first
+func (money Money) Split(n int) ([]Money, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("cannot split an amount into %d parts", n)
+	}
+
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return money.Allocate(ratios...)
+}
+
+// Amount is an amount of money as a request gives it, before the currency it is in is known
+// A JSON string is a decimal in the major unit of the currency, "10.00" for ten dollars,
+// while a JSON number is a whole number of minor units, which is how amounts were given before decimals
+type Amount struct {
+	// decimal is set when the amount was given as a decimal string, and text is that string
+	decimal *big.Rat
+	text    string
+	minor   int64
+}
+
+// MinorAmount returns an amount given in minor units
+func MinorAmount(amount int64) Amount {
+	return Amount{minor: amount}
+}
+
+// DecimalAmount returns an amount given as a decimal in the major unit of its currency
+func DecimalAmount(decimal string) (Amount, error) {
+	r, err := parseDecimal(decimal)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	return Amount{decimal: r, text: decimal}, nil
+}
+
+// Sign returns -1, 0 or 1 for a negative, zero or positive amount, whatever the currency
+func (amount Amount) Sign() int {
+	if amount.decimal != nil {
+		return amount.decimal.Sign()
+	}
+	return NewMoney(amount.minor, "").Sign()
+}
+
+// Money returns the amount in minor units of the currency
+func (amount Amount) Money(currency string) (Money, error) {
+	if amount.decimal != nil {
+		return ratMoney(amount.decimal, currency, amount.text)
+	}
+	return NewMoney(amount.minor, currency), nil
+}
+
+// UnmarshalJSON reads a decimal string or a whole number of minor units
+func (amount *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+
+		a, err := DecimalAmount(text)
+		if err != nil {
+			return err
+		}
+		*amount = a
+		return nil
+	}
+
+	minor, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %s, it should be a decimal string or a whole number of minor units", data)
+	}
+	*amount = MinorAmount(minor)
+	return nil
+}
+
+// MarshalJSON encodes the amount the way it was given
+func (amount Amount) MarshalJSON() ([]byte, error) {
+	if amount.decimal != nil {
+		return json.Marshal(amount.text)
+	}
+	return json.Marshal(amount.minor)
+}
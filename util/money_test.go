@@ -0,0 +1,272 @@
+package util
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	t.Cleanup(func() { SetCurrencies(defaultCurrencies) })
+	PutCurrency(Currency{Code: "JPY", NumericCode: 392, MinorUnits: 0, Symbol: "¥", Enabled: true})
+	PutCurrency(Currency{Code: "KWD", NumericCode: 414, MinorUnits: 3, Enabled: true})
+
+	testCases := []struct {
+		decimal  string
+		currency string
+		amount   int64
+		valid    bool
+	}{
+		{"10.00", USD, 1000, true},
+		{"10", USD, 1000, true},
+		{"0.5", USD, 50, true},
+		{"-12.34", EUR, -1234, true},
+		{"10.000", USD, 1000, true},
+		{"10.005", USD, 0, false},
+		{"1500", "JPY", 1500, true},
+		{"1500.5", "JPY", 0, false},
+		{"1.234", "KWD", 1234, true},
+		{"92233720368547758.07", USD, math.MaxInt64, true},
+		{"92233720368547758.08", USD, 0, false},
+		{"1,000.00", USD, 0, false},
+		{".5", USD, 0, false},
+		{"1e3", USD, 0, false},
+		{"", USD, 0, false},
+	}
+
+	for _, tc := range testCases {
+		money, err := ParseMoney(tc.decimal, tc.currency)
+		if !tc.valid {
+			require.Error(t, err, tc.decimal)
+			continue
+		}
+
+		require.NoError(t, err, tc.decimal)
+		require.Equal(t, NewMoney(tc.amount, tc.currency), money)
+	}
+}
+
+func TestMajorUnitMoney(t *testing.T) {
+	t.Cleanup(func() { SetCurrencies(defaultCurrencies) })
+	PutCurrency(Currency{Code: "JPY", NumericCode: 392, MinorUnits: 0, Symbol: "¥", Enabled: true})
+	PutCurrency(Currency{Code: "KWD", NumericCode: 414, MinorUnits: 3, Enabled: true})
+
+	money, err := MajorUnitMoney(10, USD)
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(1000, USD), money)
+
+	money, err = MajorUnitMoney(10, "JPY")
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(10, "JPY"), money)
+
+	money, err = MajorUnitMoney(10, "KWD")
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(10000, "KWD"), money)
+
+	_, err = MajorUnitMoney(math.MaxInt64/10, USD)
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	t.Cleanup(func() { SetCurrencies(defaultCurrencies) })
+	PutCurrency(Currency{Code: "JPY", NumericCode: 392, MinorUnits: 0, Symbol: "¥", Enabled: true})
+
+	require.Equal(t, "10.00", NewMoney(1000, USD).Decimal())
+	require.Equal(t, "0.05", NewMoney(5, USD).Decimal())
+	require.Equal(t, "-0.05", NewMoney(-5, EUR).Decimal())
+	require.Equal(t, "-92233720368547758.08", NewMoney(math.MinInt64, USD).Decimal())
+	require.Equal(t, "1500", NewMoney(1500, "JPY").Decimal())
+	require.Equal(t, "10.00 USD", NewMoney(1000, USD).String())
+
+	data, err := json.Marshal(NewMoney(1000, USD))
+	require.NoError(t, err)
+	require.Equal(t, `"10.00"`, string(data))
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	t.Cleanup(func() { SetCurrencies(defaultCurrencies) })
+	PutCurrency(Currency{Code: "JPY", NumericCode: 392, MinorUnits: 0, Symbol: "¥", Enabled: true})
+
+	money := Money{Currency: USD}
+	require.NoError(t, json.Unmarshal([]byte(`"10.50"`), &money))
+	require.Equal(t, NewMoney(1050, USD), money)
+
+	money = Money{Currency: "JPY"}
+	require.NoError(t, json.Unmarshal([]byte(`"1500"`), &money))
+	require.Equal(t, NewMoney(1500, "JPY"), money)
+
+	data, err := json.Marshal(NewMoney(-5, EUR))
+	require.NoError(t, err)
+	money = Money{Currency: EUR}
+	require.NoError(t, json.Unmarshal(data, &money))
+	require.Equal(t, NewMoney(-5, EUR), money)
+
+	money = Money{Currency: "JPY"}
+	require.Error(t, json.Unmarshal([]byte(`"1500.5"`), &money))
+	money = Money{Currency: USD}
+	require.Error(t, json.Unmarshal([]byte(`1050`), &money))
+	require.Error(t, json.Unmarshal([]byte(`"ten"`), &money))
+}
+
+func TestMoneyAddSub(t *testing.T) {
+	sum, err := NewMoney(1000, USD).Add(NewMoney(-250, USD))
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(750, USD), sum)
+
+	difference, err := NewMoney(1000, USD).Sub(NewMoney(1250, USD))
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(-250, USD), difference)
+
+	_, err = NewMoney(1000, USD).Add(NewMoney(1000, EUR))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = NewMoney(1000, USD).Sub(NewMoney(1000, EUR))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = NewMoney(math.MaxInt64, USD).Add(NewMoney(1, USD))
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+
+	_, err = NewMoney(math.MinInt64, USD).Add(NewMoney(-1, USD))
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+
+	_, err = NewMoney(math.MinInt64, USD).Sub(NewMoney(1, USD))
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+
+	_, err = NewMoney(0, USD).Sub(NewMoney(math.MinInt64, USD))
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+
+	difference, err = NewMoney(-1, USD).Sub(NewMoney(math.MinInt64, USD))
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(math.MaxInt64, USD), difference)
+}
+
+func TestMoneyMul(t *testing.T) {
+	testCases := []struct {
+		amount int64
+		factor *big.Rat
+		mode   RoundingMode
+		result int64
+	}{
+		{1000, big.NewRat(1, 3), RoundDown, 333},
+		{1000, big.NewRat(1, 3), RoundUp, 334},
+		{1000, big.NewRat(1, 3), RoundHalfUp, 333},
+		{1000, big.NewRat(2, 3), RoundHalfUp, 667},
+		{-1000, big.NewRat(2, 3), RoundDown, -666},
+		{-1000, big.NewRat(2, 3), RoundUp, -667},
+		{-1000, big.NewRat(2, 3), RoundHalfUp, -667},
+		{25, big.NewRat(1, 10), RoundHalfUp, 3},
+		{25, big.NewRat(1, 10), RoundHalfEven, 2},
+		{35, big.NewRat(1, 10), RoundHalfEven, 4},
+		{-25, big.NewRat(1, 10), RoundHalfUp, -3},
+		{-25, big.NewRat(1, 10), RoundHalfEven, -2},
+		{1000, big.NewRat(2, 1), RoundDown, 2000},
+	}
+
+	for _, tc := range testCases {
+		result, err := NewMoney(tc.amount, USD).Mul(tc.factor, tc.mode)
+		require.NoError(t, err)
+		require.Equal(t, NewMoney(tc.result, USD), result, "%d * %s", tc.amount, tc.factor)
+	}
+
+	_, err := NewMoney(math.MaxInt64, USD).Mul(big.NewRat(2, 1), RoundDown)
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	parts, err := NewMoney(100, USD).Allocate(1, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []Money{NewMoney(34, USD), NewMoney(33, USD), NewMoney(33, USD)}, parts)
+
+	parts, err = NewMoney(5, USD).Allocate(3, 7)
+	require.NoError(t, err)
+	require.Equal(t, []Money{NewMoney(2, USD), NewMoney(3, USD)}, parts)
+
+	// parts with a zero ratio get nothing, not even what is left over
+	parts, err = NewMoney(101, USD).Allocate(0, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []Money{NewMoney(0, USD), NewMoney(51, USD), NewMoney(50, USD)}, parts)
+
+	parts, err = NewMoney(-100, USD).Allocate(1, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []Money{NewMoney(-34, USD), NewMoney(-33, USD), NewMoney(-33, USD)}, parts)
+
+	parts, err = NewMoney(math.MaxInt64, USD).Allocate(math.MaxInt64, math.MaxInt64)
+	require.NoError(t, err)
+	require.Equal(t, int64(math.MaxInt64), parts[0].Amount+parts[1].Amount)
+
+	_, err = NewMoney(100, USD).Allocate(1, -1)
+	require.Error(t, err)
+
+	_, err = NewMoney(100, USD).Allocate(0, 0)
+	require.Error(t, err)
+
+	_, err = NewMoney(100, USD).Allocate()
+	require.Error(t, err)
+}
+
+func TestMoneySplit(t *testing.T) {
+	for n := 1; n <= 10; n++ {
+		amount := RandomMoney()
+		parts, err := NewMoney(amount, USD).Split(n)
+		require.NoError(t, err)
+		require.Len(t, parts, n)
+
+		var total int64
+		for _, part := range parts {
+			require.LessOrEqual(t, parts[0].Amount-part.Amount, int64(1))
+			total += part.Amount
+		}
+		require.Equal(t, amount, total)
+	}
+
+	_, err := NewMoney(100, USD).Split(0)
+	require.Error(t, err)
+}
+
+func TestAmountJSON(t *testing.T) {
+	var req struct {
+		Amount Amount `json:"amount"`
+	}
+
+	err := json.Unmarshal([]byte(`{"amount":"10.50"}`), &req)
+	require.NoError(t, err)
+	require.Equal(t, 1, req.Amount.Sign())
+
+	money, err := req.Amount.Money(USD)
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(1050, USD), money)
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":"10.50"}`, string(data))
+
+	// a number is in minor units, as amounts were given before decimals
+	err = json.Unmarshal([]byte(`{"amount":1050}`), &req)
+	require.NoError(t, err)
+
+	money, err = req.Amount.Money(EUR)
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(1050, EUR), money)
+
+	data, err = json.Marshal(req)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":1050}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"amount":"-0.01"}`), &req)
+	require.NoError(t, err)
+	require.Equal(t, -1, req.Amount.Sign())
+
+	err = json.Unmarshal([]byte(`{"amount":"10.005"}`), &req)
+	require.NoError(t, err)
+	_, err = req.Amount.Money(USD)
+	require.Error(t, err)
+
+	err = json.Unmarshal([]byte(`{"amount":"ten"}`), &req)
+	require.Error(t, err)
+
+	err = json.Unmarshal([]byte(`{"amount":10.5}`), &req)
+	require.Error(t, err)
+}
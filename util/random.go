@@ -60,4 +60,13 @@ func RandomDate() time.Time {
 // RandomEmail generates a random email address
 func RandomEmail() string {
 	return fmt.Sprintf("%s@email.com", RandomString(6))
-}
\ No newline at end of file
+}
+
+// RandomAccountNumber generates a random account number with valid check digits
+func RandomAccountNumber() string {
+	number, err := NewAccountNumber("XB", "TEST")
+	if err != nil {
+		panic(err)
+	}
+	return number
+}
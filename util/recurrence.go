@@ -0,0 +1,79 @@
+package util
+
+import "time"
+
+// Constants for all supported recurrences of a scheduled transfer
+const (
+	RecurrenceOnce            = "once"
+	RecurrenceWeekly          = "weekly"
+	RecurrenceMonthly         = "monthly"
+	RecurrenceLastBusinessDay = "last_business_day"
+)
+
+// IsSupportedRecurrence returns true if the recurrence is supported
+func IsSupportedRecurrence(recurrence string) bool {
+	switch recurrence {
+	case RecurrenceOnce, RecurrenceWeekly, RecurrenceMonthly, RecurrenceLastBusinessDay:
+		return true
+	}
+
+	return false
+}
+
+// FirstOccurrence returns the first occurrence of a recurrence at or after start, at start's time of day
+// Monthly occurrences fall on dayOfMonth, or on the last day of months that are shorter
+func FirstOccurrence(recurrence string, dayOfMonth int, start time.Time) time.Time {
+	switch recurrence {
+	case RecurrenceMonthly:
+		first := dayInMonth(start, 0, dayOfMonth)
+		if first.Before(start) {
+			first = dayInMonth(start, 1, dayOfMonth)
+		}
+		return first
+	case RecurrenceLastBusinessDay:
+		first := lastBusinessDayInMonth(start, 0)
+		if first.Before(start) {
+			first = lastBusinessDayInMonth(start, 1)
+		}
+		return first
+	}
+
+	return start
+}
+
+// NextOccurrence returns the occurrence that follows prev
+// It returns false for recurrences that only occur once
+func NextOccurrence(recurrence string, dayOfMonth int, prev time.Time) (time.Time, bool) {
+	switch recurrence {
+	case RecurrenceWeekly:
+		return prev.AddDate(0, 0, 7), true
+	case RecurrenceMonthly:
+		return dayInMonth(prev, 1, dayOfMonth), true
+	case RecurrenceLastBusinessDay:
+		return lastBusinessDayInMonth(prev, 1), true
+	}
+
+	return time.Time{}, false
+}
+
+// dayInMonth returns the given day of the month that is months after t's, clamped to the length of that month
+func dayInMonth(t time.Time, months int, day int) time.Time {
+	year, month, _ := t.Date()
+	lastDay := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	hour, min, sec := t.Clock()
+	return time.Date(year, month+time.Month(months), day, hour, min, sec, t.Nanosecond(), t.Location())
+}
+
+// lastBusinessDayInMonth returns the last weekday of the month that is months after t's
+func lastBusinessDayInMonth(t time.Time, months int) time.Time {
+	day := dayInMonth(t, months, 31)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return day
+}
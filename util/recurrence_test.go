@@ -0,0 +1,49 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirstOccurrence(t *testing.T) {
+	start := time.Date(2024, time.January, 20, 9, 30, 0, 0, time.UTC)
+
+	require.Equal(t, start, FirstOccurrence(RecurrenceOnce, 0, start))
+	require.Equal(t, start, FirstOccurrence(RecurrenceWeekly, 0, start))
+	require.Equal(t, time.Date(2024, time.January, 25, 9, 30, 0, 0, time.UTC), FirstOccurrence(RecurrenceMonthly, 25, start))
+	require.Equal(t, time.Date(2024, time.February, 15, 9, 30, 0, 0, time.UTC), FirstOccurrence(RecurrenceMonthly, 15, start))
+	require.Equal(t, start, FirstOccurrence(RecurrenceMonthly, 20, start))
+	require.Equal(t, time.Date(2024, time.January, 31, 9, 30, 0, 0, time.UTC), FirstOccurrence(RecurrenceLastBusinessDay, 0, start))
+
+	// the last business day of March 2024 is Friday the 29th
+	start = time.Date(2024, time.March, 30, 9, 30, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2024, time.April, 30, 9, 30, 0, 0, time.UTC), FirstOccurrence(RecurrenceLastBusinessDay, 0, start))
+}
+
+func TestNextOccurrence(t *testing.T) {
+	prev := time.Date(2024, time.January, 31, 9, 30, 0, 0, time.UTC)
+
+	_, ok := NextOccurrence(RecurrenceOnce, 0, prev)
+	require.False(t, ok)
+
+	next, ok := NextOccurrence(RecurrenceWeekly, 0, prev)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, time.February, 7, 9, 30, 0, 0, time.UTC), next)
+
+	// the 31st falls back to the end of February and comes back in March
+	next, ok = NextOccurrence(RecurrenceMonthly, 31, prev)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, time.February, 29, 9, 30, 0, 0, time.UTC), next)
+
+	next, ok = NextOccurrence(RecurrenceMonthly, 31, next)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, time.March, 31, 9, 30, 0, 0, time.UTC), next)
+
+	// the last business day of August 2024 is Friday the 30th
+	prev = time.Date(2024, time.July, 31, 9, 30, 0, 0, time.UTC)
+	next, ok = NextOccurrence(RecurrenceLastBusinessDay, 0, prev)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, time.August, 30, 9, 30, 0, 0, time.UTC), next)
+}
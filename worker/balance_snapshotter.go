@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+)
+
+// snapshotDelay is how long after its time a snapshot is first tried
+// Entries are dated when their transaction starts, so this leaves the transactions that started before then time to commit,
+// and a snapshot tried while one of them is still running is tried again on the next check
+const snapshotDelay = time.Minute
+
+// snapshotCheckInterval is how often the snapshotter checks whether a snapshot is due
+const snapshotCheckInterval = time.Minute
+
+// BalanceSnapshotter records the balance of every open account at the start of each interval
+// so point-in-time balances only have to add up the entries of a single interval
+// Several snapshotters can share a database: a snapshot that was already taken by another one is kept as it is
+type BalanceSnapshotter struct {
+	store db.Store
+	interval time.Duration
+	lastTakenAt time.Time
+}
+
+// NewBalanceSnapshotter creates a new balance snapshotter
+func NewBalanceSnapshotter(config util.Config, store db.Store) *BalanceSnapshotter {
+	return &BalanceSnapshotter{
+		store: store,
+		interval: config.BalanceSnapshotInterval,
+	}
+}
+
+// Start takes the due snapshots until ctx is done
+func (snapshotter *BalanceSnapshotter) Start(ctx context.Context) {
+	ticker := time.NewTicker(snapshotCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		err := snapshotter.TakeDue(ctx, time.Now())
+		if err != nil {
+			log.Println("cannot take balance snapshots:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// TakeDue snapshots the balances at the start of the latest interval that began at least snapshotDelay before now,
+// unless this snapshotter already did
+func (snapshotter *BalanceSnapshotter) TakeDue(ctx context.Context, now time.Time) error {
+	takenAt := now.UTC().Add(-snapshotDelay).Truncate(snapshotter.interval)
+	if !takenAt.After(snapshotter.lastTakenAt) {
+		return nil
+	}
+
+	count, err := snapshotter.store.CreateBalanceSnapshotsTx(ctx, takenAt)
+	if err != nil {
+		return err
+	}
+
+	snapshotter.lastTakenAt = takenAt
+	log.Printf("took %d balance snapshots at %s", count, takenAt.Format(time.RFC3339))
+	return nil
+}
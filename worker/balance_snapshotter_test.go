@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakeDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	snapshotter := NewBalanceSnapshotter(util.Config{BalanceSnapshotInterval: 24 * time.Hour}, store)
+
+	midnight := time.Date(2022, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+	gomock.InOrder(
+		store.EXPECT().CreateBalanceSnapshotsTx(gomock.Any(), gomock.Eq(midnight.AddDate(0, 0, -1))).Times(1).Return(int64(0), sql.ErrConnDone),
+		store.EXPECT().CreateBalanceSnapshotsTx(gomock.Any(), gomock.Eq(midnight.AddDate(0, 0, -1))).Times(1).Return(int64(10), nil),
+		store.EXPECT().CreateBalanceSnapshotsTx(gomock.Any(), gomock.Eq(midnight)).Times(1).Return(int64(0), db.ErrSnapshotTooEarly),
+		store.EXPECT().CreateBalanceSnapshotsTx(gomock.Any(), gomock.Eq(midnight)).Times(1).Return(int64(10), nil),
+	)
+
+	// the snapshot of midnight waits for the transactions that started before it
+	err := snapshotter.TakeDue(context.Background(), midnight.Add(snapshotDelay/2))
+	require.ErrorIs(t, err, sql.ErrConnDone)
+
+	// a failed snapshot is taken again
+	err = snapshotter.TakeDue(context.Background(), midnight.Add(snapshotDelay/2))
+	require.NoError(t, err)
+
+	// a snapshot tried while a transaction that started before it is still running is tried again
+	err = snapshotter.TakeDue(context.Background(), midnight.Add(snapshotDelay))
+	require.ErrorIs(t, err, db.ErrSnapshotTooEarly)
+
+	err = snapshotter.TakeDue(context.Background(), midnight.Add(snapshotDelay+snapshotCheckInterval))
+	require.NoError(t, err)
+
+	// and a snapshot that was taken isn't taken again
+	err = snapshotter.TakeDue(context.Background(), midnight.Add(time.Hour))
+	require.NoError(t, err)
+}
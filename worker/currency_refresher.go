@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+)
+
+// CurrencyRefresher reloads the currency registry from the database every interval
+// so that currencies added or changed through another server are seen by this one too
+type CurrencyRefresher struct {
+	store db.Store
+	interval time.Duration
+}
+
+// NewCurrencyRefresher creates a new currency refresher
+func NewCurrencyRefresher(config util.Config, store db.Store) *CurrencyRefresher {
+	return &CurrencyRefresher{
+		store: store,
+		interval: config.CurrencyRefreshInterval,
+	}
+}
+
+// Start refreshes the currency registry every interval until ctx is done
+func (refresher *CurrencyRefresher) Start(ctx context.Context) {
+	ticker := time.NewTicker(refresher.interval)
+	defer ticker.Stop()
+
+	// the registry is loaded when the server starts, so the first refresh is an interval later
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := refresher.Refresh(ctx)
+		if err != nil {
+			log.Println("cannot refresh currency registry:", err)
+		}
+	}
+}
+
+// Refresh replaces the currency registry with the currencies in the database
+// The registry is left as it was if they can't be loaded
+func (refresher *CurrencyRefresher) Refresh(ctx context.Context) error {
+	return db.LoadCurrencyRegistry(ctx, refresher.store)
+}
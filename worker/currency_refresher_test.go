@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshCurrencies(t *testing.T) {
+	// the other tests only know the currencies the bank started with
+	currencies := util.Currencies()
+	t.Cleanup(func() { util.SetCurrencies(currencies) })
+
+	jpy := db.Currency{Code: "JPY", NumericCode: 392, MinorUnits: 0, Symbol: "¥", Enabled: true}
+
+	testCases := []struct {
+		name string
+		buildStubs func(store *mockdb.MockStore)
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCurrencies(gomock.Any()).
+					Times(1).
+					Return([]db.Currency{jpy}, nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+
+				currency, ok := util.GetCurrency("JPY")
+				require.True(t, ok)
+				require.Equal(t, jpy.Registered(), currency)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCurrencies(gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+
+				// the registry keeps what it had
+				require.True(t, util.IsSupportedCurrency("JPY"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			refresher := NewCurrencyRefresher(util.Config{CurrencyRefreshInterval: time.Minute}, store)
+			err := refresher.Refresh(context.Background())
+			tc.checkResult(t, err)
+		})
+	}
+}
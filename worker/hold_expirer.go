@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+)
+
+// expireBatchSize is how many expired holds are loaded at a time
+const expireBatchSize = 100
+
+// HoldExpirer releases authorized holds once they expire, making their amount available again
+// Several expirers can share a database: a hold that was already released by another one is skipped
+type HoldExpirer struct {
+	store db.Store
+	interval time.Duration
+}
+
+// NewHoldExpirer creates a new hold expirer
+func NewHoldExpirer(config util.Config, store db.Store) *HoldExpirer {
+	return &HoldExpirer{
+		store: store,
+		interval: config.HoldExpiryInterval,
+	}
+}
+
+// Start expires the due holds every interval until ctx is done
+func (expirer *HoldExpirer) Start(ctx context.Context) {
+	ticker := time.NewTicker(expirer.interval)
+	defer ticker.Stop()
+
+	for {
+		err := expirer.ExpireDue(ctx)
+		if err != nil {
+			log.Println("cannot expire holds:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireDue releases the authorized holds that are past their expiry, a batch at a time, until none is left
+func (expirer *HoldExpirer) ExpireDue(ctx context.Context) error {
+	for {
+		holds, err := expirer.store.ListExpiredHolds(ctx, db.ListExpiredHoldsParams{
+			Now: time.Now().UTC(),
+			PageLimit: expireBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, hold := range holds {
+			_, err = expirer.store.ReleaseHoldTx(ctx, db.ReleaseHoldTxParams{
+				HoldID: hold.ID,
+				Status: db.HoldExpired,
+			})
+			if err != nil && !errors.Is(err, db.ErrHoldNotAuthorized) {
+				return err
+			}
+		}
+
+		if len(holds) < expireBatchSize {
+			return nil
+		}
+	}
+}
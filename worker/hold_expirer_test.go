@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpireDue(t *testing.T) {
+	holds := []db.Hold{randomHold(), randomHold()}
+
+	testCases := []struct {
+		name string
+		buildStubs func(store *mockdb.MockStore)
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListExpiredHolds(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(holds, nil)
+
+				for _, hold := range holds {
+					arg := db.ReleaseHoldTxParams{
+						HoldID: hold.ID,
+						Status: db.HoldExpired,
+					}
+					store.EXPECT().
+						ReleaseHoldTx(gomock.Any(), gomock.Eq(arg)).
+						Times(1)
+				}
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AlreadyReleased",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListExpiredHolds(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(holds, nil)
+
+				gomock.InOrder(
+					store.EXPECT().ReleaseHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.HoldTxResult{}, db.ErrHoldNotAuthorized),
+					store.EXPECT().ReleaseHoldTx(gomock.Any(), gomock.Any()).Times(1),
+				)
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "FullBatch",
+			buildStubs: func(store *mockdb.MockStore) {
+				batch := make([]db.Hold, expireBatchSize)
+				for i := range batch {
+					batch[i] = randomHold()
+				}
+
+				gomock.InOrder(
+					store.EXPECT().ListExpiredHolds(gomock.Any(), gomock.Any()).Times(1).Return(batch, nil),
+					store.EXPECT().ListExpiredHolds(gomock.Any(), gomock.Any()).Times(1).Return([]db.Hold{}, nil),
+				)
+
+				store.EXPECT().
+					ReleaseHoldTx(gomock.Any(), gomock.Any()).
+					Times(expireBatchSize)
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "ReleaseError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListExpiredHolds(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(holds, nil)
+
+				store.EXPECT().
+					ReleaseHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.HoldTxResult{}, sql.ErrConnDone)
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+		{
+			name: "ListError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListExpiredHolds(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+
+				store.EXPECT().
+					ReleaseHoldTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			expirer := NewHoldExpirer(util.Config{HoldExpiryInterval: time.Minute}, store)
+			err := expirer.ExpireDue(context.Background())
+			tc.checkResult(t, err)
+		})
+	}
+}
+
+func randomHold() db.Hold {
+	return db.Hold{
+		ID: util.RandomInt(1, 1000),
+		FromAccountID: util.RandomInt(1, 1000),
+		ToAccountID: util.RandomInt(1001, 2000),
+		Amount: util.RandomMoney(),
+		Status: db.HoldAuthorized,
+		ExpiresAt: time.Now().UTC().Add(-time.Minute).Truncate(time.Second),
+	}
+}
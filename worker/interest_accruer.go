@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+)
+
+// interestBatchSize is how many interest bearing accounts are loaded at a time
+const interestBatchSize = 100
+
+// InterestAccruer accrues the interest of every interest bearing account for each day that ended,
+// and pays the interest of the previous month into the accounts once it is over
+// Days the accruer missed are caught up on, and several accruers can share a database:
+// days and months that were already accrued or paid by another one are kept as they are
+type InterestAccruer struct {
+	store db.Store
+	interval time.Duration
+	delay time.Duration
+	lastAccruedUntil time.Time
+}
+
+// NewInterestAccruer creates a new interest accruer
+func NewInterestAccruer(config util.Config, store db.Store) *InterestAccruer {
+	return &InterestAccruer{
+		store: store,
+		interval: config.InterestAccrualInterval,
+		delay: config.InterestAccrualDelay,
+	}
+}
+
+// Start accrues the interest that is due until ctx is done
+func (accruer *InterestAccruer) Start(ctx context.Context) {
+	ticker := time.NewTicker(accruer.interval)
+	defer ticker.Stop()
+
+	for {
+		err := accruer.AccrueDue(ctx, time.Now())
+		if err != nil {
+			log.Println("cannot accrue interest:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AccrueDue accrues the interest of every day that ended at least the accruer's delay before now and capitalizes the previous month,
+// unless this accruer already did today
+// The delay leaves the transactions that started before midnight, and so date their entries on the day before, time to commit
+// An account that fails is logged and tried again on the next check, without holding up the others
+func (accruer *InterestAccruer) AccrueDue(ctx context.Context, now time.Time) error {
+	today := now.UTC().Add(-accruer.delay).Truncate(24 * time.Hour)
+	if !today.After(accruer.lastAccruedUntil) {
+		return nil
+	}
+
+	failed := false
+	var afterID int64
+	for {
+		accounts, err := accruer.store.ListInterestBearingAccounts(ctx, db.ListInterestBearingAccountsParams{
+			AfterID: afterID,
+			PageLimit: interestBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			err = accruer.accrueAccount(ctx, account, today)
+			if err != nil {
+				log.Printf("cannot accrue interest on account %d: %v", account.ID, err)
+				failed = true
+			}
+			afterID = account.ID
+		}
+
+		if len(accounts) < interestBatchSize {
+			break
+		}
+	}
+
+	if !failed {
+		accruer.lastAccruedUntil = today
+	}
+	return nil
+}
+
+// accrueAccount accrues every day of the account from the day after its latest accrual, or the day it was opened, until today
+// and then capitalizes the month before today's
+// Frozen and closed accounts keep accruing, their interest is paid with the first month they can be paid for
+func (accruer *InterestAccruer) accrueAccount(ctx context.Context, account db.Account, today time.Time) error {
+	day := account.CreatedAt.UTC().Truncate(24 * time.Hour)
+
+	latest, err := accruer.store.GetLatestInterestAccrual(ctx, account.ID)
+	if err == nil {
+		day = latest.AccrualDate.UTC().Truncate(24 * time.Hour).AddDate(0, 0, 1)
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	for ; day.Before(today); day = day.AddDate(0, 0, 1) {
+		_, err = accruer.store.AccrueInterestTx(ctx, db.AccrueInterestTxParams{
+			AccountID: account.ID,
+			Date: day,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	period := time.Date(today.Year(), today.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+	if !account.CreatedAt.Before(period.AddDate(0, 1, 0)) {
+		return nil
+	}
+
+	_, err = accruer.store.CapitalizeInterestTx(ctx, db.CapitalizeInterestTxParams{
+		AccountID: account.ID,
+		Period: period,
+	})
+	if errors.Is(err, db.ErrInterestAlreadyCapitalized) || errors.Is(err, db.ErrAccountFrozen) || errors.Is(err, db.ErrAccountClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInterestAccruer(store db.Store) *InterestAccruer {
+	config := util.Config{
+		InterestAccrualInterval: time.Minute,
+		InterestAccrualDelay: time.Minute,
+	}
+
+	return NewInterestAccruer(config, store)
+}
+
+func TestAccrueDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	accruer := newTestInterestAccruer(store)
+
+	today := time.Date(2022, time.March, 2, 0, 0, 0, 0, time.UTC)
+	period := time.Date(2022, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	// the first account was last accrued for February 27th, the second one was opened on March 1st
+	account1 := db.Account{ID: 1, CreatedAt: time.Date(2022, time.January, 10, 12, 0, 0, 0, time.UTC)}
+	account2 := db.Account{ID: 2, CreatedAt: time.Date(2022, time.March, 1, 8, 0, 0, 0, time.UTC)}
+
+	store.EXPECT().
+		ListInterestBearingAccounts(gomock.Any(), gomock.Eq(db.ListInterestBearingAccountsParams{PageLimit: interestBatchSize})).
+		Times(1).
+		Return([]db.Account{account1, account2}, nil)
+
+	store.EXPECT().
+		GetLatestInterestAccrual(gomock.Any(), gomock.Eq(account1.ID)).
+		Times(1).
+		Return(db.InterestAccrual{AccountID: account1.ID, AccrualDate: time.Date(2022, time.February, 27, 0, 0, 0, 0, time.UTC)}, nil)
+	store.EXPECT().
+		GetLatestInterestAccrual(gomock.Any(), gomock.Eq(account2.ID)).
+		Times(1).
+		Return(db.InterestAccrual{}, sql.ErrNoRows)
+
+	for _, day := range []time.Time{today.AddDate(0, 0, -2), today.AddDate(0, 0, -1)} {
+		store.EXPECT().
+			AccrueInterestTx(gomock.Any(), gomock.Eq(db.AccrueInterestTxParams{AccountID: account1.ID, Date: day})).
+			Times(1).
+			Return(db.InterestAccrual{}, nil)
+	}
+	store.EXPECT().
+		AccrueInterestTx(gomock.Any(), gomock.Eq(db.AccrueInterestTxParams{AccountID: account2.ID, Date: today.AddDate(0, 0, -1)})).
+		Times(1).
+		Return(db.InterestAccrual{}, nil)
+
+	// the second account has nothing to capitalize for February
+	store.EXPECT().
+		CapitalizeInterestTx(gomock.Any(), gomock.Eq(db.CapitalizeInterestTxParams{AccountID: account1.ID, Period: period})).
+		Times(1).
+		Return(db.CapitalizeInterestTxResult{}, nil)
+
+	// the previous day is accrued once the transactions that started before midnight had time to commit
+	err := accruer.AccrueDue(context.Background(), today.Add(accruer.delay))
+	require.NoError(t, err)
+
+	// and isn't accrued again
+	err = accruer.AccrueDue(context.Background(), today.Add(time.Hour))
+	require.NoError(t, err)
+}
+
+func TestAccrueDueFailedAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	accruer := newTestInterestAccruer(store)
+
+	today := time.Date(2022, time.March, 2, 0, 0, 0, 0, time.UTC)
+	period := time.Date(2022, time.February, 1, 0, 0, 0, 0, time.UTC)
+	account1 := db.Account{ID: 1, CreatedAt: time.Date(2022, time.January, 10, 12, 0, 0, 0, time.UTC)}
+	account2 := db.Account{ID: 2, CreatedAt: time.Date(2022, time.January, 10, 12, 0, 0, 0, time.UTC)}
+	yesterday := db.InterestAccrual{AccrualDate: today.AddDate(0, 0, -1)}
+
+	store.EXPECT().
+		ListInterestBearingAccounts(gomock.Any(), gomock.Any()).
+		Times(2).
+		Return([]db.Account{account1, account2}, nil)
+	store.EXPECT().
+		GetLatestInterestAccrual(gomock.Any(), gomock.Any()).
+		Times(4).
+		Return(yesterday, nil)
+	store.EXPECT().AccrueInterestTx(gomock.Any(), gomock.Any()).Times(0)
+
+	// a failed account doesn't hold up the others, and the accounts are tried again on the next check
+	gomock.InOrder(
+		store.EXPECT().
+			CapitalizeInterestTx(gomock.Any(), gomock.Eq(db.CapitalizeInterestTxParams{AccountID: account1.ID, Period: period})).
+			Times(1).
+			Return(db.CapitalizeInterestTxResult{}, sql.ErrConnDone),
+		store.EXPECT().
+			CapitalizeInterestTx(gomock.Any(), gomock.Eq(db.CapitalizeInterestTxParams{AccountID: account2.ID, Period: period})).
+			Times(1).
+			Return(db.CapitalizeInterestTxResult{}, fmt.Errorf("%w: account %d", db.ErrAccountFrozen, account2.ID)),
+		store.EXPECT().
+			CapitalizeInterestTx(gomock.Any(), gomock.Eq(db.CapitalizeInterestTxParams{AccountID: account1.ID, Period: period})).
+			Times(1).
+			Return(db.CapitalizeInterestTxResult{}, nil),
+		store.EXPECT().
+			CapitalizeInterestTx(gomock.Any(), gomock.Eq(db.CapitalizeInterestTxParams{AccountID: account2.ID, Period: period})).
+			Times(1).
+			Return(db.CapitalizeInterestTxResult{}, db.ErrInterestAlreadyCapitalized),
+	)
+
+	err := accruer.AccrueDue(context.Background(), today.Add(time.Hour))
+	require.NoError(t, err)
+
+	err = accruer.AccrueDue(context.Background(), today.Add(2*time.Hour))
+	require.NoError(t, err)
+}
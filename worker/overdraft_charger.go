@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+)
+
+// overdraftBatchSize is how many accounts with an overdraft are loaded at a time
+const overdraftBatchSize = 100
+
+// OverdraftCharger charges every account with an overdraft for each day that ended with the account overdrawn
+// Days the charger missed are caught up on, back to the day after the account was last charged,
+// and several chargers can share a database: days that were already charged by another one are kept as they are
+type OverdraftCharger struct {
+	store db.Store
+	interval time.Duration
+	delay time.Duration
+	lastChargedUntil time.Time
+}
+
+// NewOverdraftCharger creates a new overdraft charger
+func NewOverdraftCharger(config util.Config, store db.Store) *OverdraftCharger {
+	return &OverdraftCharger{
+		store: store,
+		interval: config.OverdraftChargeInterval,
+		delay: config.OverdraftChargeDelay,
+	}
+}
+
+// Start charges the overdrafts that are due until ctx is done
+func (charger *OverdraftCharger) Start(ctx context.Context) {
+	ticker := time.NewTicker(charger.interval)
+	defer ticker.Stop()
+
+	for {
+		err := charger.ChargeDue(ctx, time.Now())
+		if err != nil {
+			log.Println("cannot charge overdrafts:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ChargeDue charges the overdrafts of every day that ended at least the charger's delay before now, unless this charger already did today
+// The delay lets the transactions that started before midnight commit, so the balance the day ended with is complete
+// An account that fails is logged and tried again on the next check, without holding up the others
+func (charger *OverdraftCharger) ChargeDue(ctx context.Context, now time.Time) error {
+	today := now.UTC().Add(-charger.delay).Truncate(24 * time.Hour)
+	if !today.After(charger.lastChargedUntil) {
+		return nil
+	}
+
+	failed := false
+	var afterID int64
+	for {
+		accounts, err := charger.store.ListOverdraftAccounts(ctx, db.ListOverdraftAccountsParams{
+			AfterID: afterID,
+			PageLimit: overdraftBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			err = charger.chargeAccount(ctx, account, today)
+			if err != nil {
+				log.Printf("cannot charge overdraft of account %d: %v", account.ID, err)
+				failed = true
+			}
+			afterID = account.ID
+		}
+
+		if len(accounts) < overdraftBatchSize {
+			break
+		}
+	}
+
+	if !failed {
+		charger.lastChargedUntil = today
+	}
+	return nil
+}
+
+// chargeAccount charges every day of the account from the day after it was last charged until today,
+// an account that was never charged is charged from yesterday
+// Frozen and closed accounts are skipped, their days are charged once they can be
+func (charger *OverdraftCharger) chargeAccount(ctx context.Context, account db.Account, today time.Time) error {
+	day := today.AddDate(0, 0, -1)
+
+	latest, err := charger.store.GetLatestOverdraftCharge(ctx, account.ID)
+	if err == nil {
+		day = latest.ChargeDate.UTC().Truncate(24 * time.Hour).AddDate(0, 0, 1)
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	for ; day.Before(today); day = day.AddDate(0, 0, 1) {
+		_, err = charger.store.ChargeOverdraftTx(ctx, db.ChargeOverdraftTxParams{
+			AccountID: account.ID,
+			Date: day,
+		})
+		if errors.Is(err, db.ErrAccountFrozen) || errors.Is(err, db.ErrAccountClosed) {
+			return nil
+		}
+		if err != nil && !errors.Is(err, db.ErrOverdraftAlreadyCharged) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOverdraftCharger(store db.Store) *OverdraftCharger {
+	config := util.Config{
+		OverdraftChargeInterval: time.Minute,
+		OverdraftChargeDelay: time.Minute,
+	}
+
+	return NewOverdraftCharger(config, store)
+}
+
+func TestChargeDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	charger := newTestOverdraftCharger(store)
+
+	today := time.Date(2022, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+	// the first account was last charged for February 27th, the second one was never charged
+	account1 := db.Account{ID: 1, OverdraftLimit: 1000}
+	account2 := db.Account{ID: 2, OverdraftLimit: 1000}
+
+	store.EXPECT().
+		ListOverdraftAccounts(gomock.Any(), gomock.Eq(db.ListOverdraftAccountsParams{PageLimit: overdraftBatchSize})).
+		Times(1).
+		Return([]db.Account{account1, account2}, nil)
+
+	store.EXPECT().
+		GetLatestOverdraftCharge(gomock.Any(), gomock.Eq(account1.ID)).
+		Times(1).
+		Return(db.OverdraftCharge{AccountID: account1.ID, ChargeDate: time.Date(2022, time.February, 27, 0, 0, 0, 0, time.UTC)}, nil)
+	store.EXPECT().
+		GetLatestOverdraftCharge(gomock.Any(), gomock.Eq(account2.ID)).
+		Times(1).
+		Return(db.OverdraftCharge{}, sql.ErrNoRows)
+
+	for _, day := range []time.Time{today.AddDate(0, 0, -2), today.AddDate(0, 0, -1)} {
+		store.EXPECT().
+			ChargeOverdraftTx(gomock.Any(), gomock.Eq(db.ChargeOverdraftTxParams{AccountID: account1.ID, Date: day})).
+			Times(1).
+			Return(db.ChargeOverdraftTxResult{}, nil)
+	}
+
+	// a day another charger got to first is skipped
+	store.EXPECT().
+		ChargeOverdraftTx(gomock.Any(), gomock.Eq(db.ChargeOverdraftTxParams{AccountID: account2.ID, Date: today.AddDate(0, 0, -1)})).
+		Times(1).
+		Return(db.ChargeOverdraftTxResult{}, db.ErrOverdraftAlreadyCharged)
+
+	err := charger.ChargeDue(context.Background(), today.Add(charger.delay))
+	require.NoError(t, err)
+
+	// and the day isn't charged again
+	err = charger.ChargeDue(context.Background(), today.Add(time.Hour))
+	require.NoError(t, err)
+}
+
+func TestChargeDueFailedAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	charger := newTestOverdraftCharger(store)
+
+	today := time.Date(2022, time.March, 2, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+	account1 := db.Account{ID: 1, OverdraftLimit: 1000}
+	account2 := db.Account{ID: 2, OverdraftLimit: 1000}
+
+	store.EXPECT().
+		ListOverdraftAccounts(gomock.Any(), gomock.Any()).
+		Times(2).
+		Return([]db.Account{account1, account2}, nil)
+	store.EXPECT().
+		GetLatestOverdraftCharge(gomock.Any(), gomock.Any()).
+		Times(4).
+		Return(db.OverdraftCharge{}, sql.ErrNoRows)
+
+	// a failed account doesn't hold up the others, and the accounts are tried again on the next check
+	// while a frozen account is left until it can be charged
+	gomock.InOrder(
+		store.EXPECT().
+			ChargeOverdraftTx(gomock.Any(), gomock.Eq(db.ChargeOverdraftTxParams{AccountID: account1.ID, Date: yesterday})).
+			Times(1).
+			Return(db.ChargeOverdraftTxResult{}, sql.ErrConnDone),
+		store.EXPECT().
+			ChargeOverdraftTx(gomock.Any(), gomock.Eq(db.ChargeOverdraftTxParams{AccountID: account2.ID, Date: yesterday})).
+			Times(1).
+			Return(db.ChargeOverdraftTxResult{}, fmt.Errorf("%w: account %d", db.ErrAccountFrozen, account2.ID)),
+		store.EXPECT().
+			ChargeOverdraftTx(gomock.Any(), gomock.Eq(db.ChargeOverdraftTxParams{AccountID: account1.ID, Date: yesterday})).
+			Times(1).
+			Return(db.ChargeOverdraftTxResult{}, nil),
+		store.EXPECT().
+			ChargeOverdraftTx(gomock.Any(), gomock.Eq(db.ChargeOverdraftTxParams{AccountID: account2.ID, Date: yesterday})).
+			Times(1).
+			Return(db.ChargeOverdraftTxResult{}, fmt.Errorf("%w: account %d", db.ErrAccountFrozen, account2.ID)),
+	)
+
+	err := charger.ChargeDue(context.Background(), today.Add(time.Hour))
+	require.NoError(t, err)
+
+	err = charger.ChargeDue(context.Background(), today.Add(2*time.Hour))
+	require.NoError(t, err)
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+)
+
+// Reconciler checks the ledger every interval and logs the discrepancies it finds
+type Reconciler struct {
+	store db.Store
+	interval time.Duration
+}
+
+// NewReconciler creates a new reconciler
+func NewReconciler(config util.Config, store db.Store) *Reconciler {
+	return &Reconciler{
+		store: store,
+		interval: config.ReconciliationInterval,
+	}
+}
+
+// Start reconciles the ledger every interval until ctx is done
+func (reconciler *Reconciler) Start(ctx context.Context) {
+	ticker := time.NewTicker(reconciler.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, err := reconciler.Reconcile(ctx)
+		if err != nil {
+			log.Println("cannot reconcile ledger:", err)
+		}
+	}
+}
+
+// Reconcile checks the ledger once and logs every discrepancy it found
+func (reconciler *Reconciler) Reconcile(ctx context.Context) (db.ReconciliationReport, error) {
+	report, err := reconciler.store.ReconcileTx(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, mismatch := range report.BalanceMismatches {
+		log.Printf("account %d: balance %d but entries add up to %d", mismatch.AccountID, mismatch.Balance, mismatch.EntriesTotal)
+	}
+	for _, mismatch := range report.HeldAmountMismatches {
+		log.Printf("account %d: held amount %d but authorized holds add up to %d", mismatch.AccountID, mismatch.HeldAmount, mismatch.HoldsTotal)
+	}
+	for _, mismatch := range report.TransferMismatches {
+		log.Printf("transfer %d: %d entries, from account entries add up to %d for an amount of %d and to account entries to %d for a to amount of %d",
+			mismatch.TransferID, mismatch.EntryCount, mismatch.FromEntriesTotal, mismatch.Amount, mismatch.ToEntriesTotal, mismatch.ToAmount)
+	}
+	for _, entry := range report.OrphanEntries {
+		log.Printf("entry %d: no transfer made it", entry.ID)
+	}
+
+	log.Printf("reconciled %d accounts and %d transfers, found %d discrepancies",
+		report.AccountsChecked, report.TransfersChecked, report.Discrepancies())
+	return report, nil
+}
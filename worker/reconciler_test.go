@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	report := db.ReconciliationReport{
+		AccountsChecked: 2,
+		TransfersChecked: 1,
+		BalanceMismatches: []db.ListAccountBalanceMismatchesRow{
+			{AccountID: 1, Balance: 100, EntriesTotal: 90},
+		},
+		TransferMismatches: []db.ListTransferEntryMismatchesRow{
+			{TransferID: 1, FromAccountID: 1, ToAccountID: 2, Amount: 10, ToAmount: 10, EntryCount: 1, FromEntriesTotal: -10},
+		},
+	}
+
+	testCases := []struct {
+		name string
+		buildStubs func(store *mockdb.MockStore)
+		checkResult func(t *testing.T, report db.ReconciliationReport, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReconcileTx(gomock.Any()).Times(1).Return(report, nil)
+			},
+			checkResult: func(t *testing.T, gotReport db.ReconciliationReport, err error) {
+				require.NoError(t, err)
+				require.Equal(t, report, gotReport)
+				require.Equal(t, 2, gotReport.Discrepancies())
+			},
+		},
+		{
+			name: "ReconcileError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReconcileTx(gomock.Any()).Times(1).Return(db.ReconciliationReport{}, sql.ErrConnDone)
+			},
+			checkResult: func(t *testing.T, report db.ReconciliationReport, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			reconciler := NewReconciler(util.Config{ReconciliationInterval: time.Hour}, store)
+			report, err := reconciler.Reconcile(context.Background())
+			tc.checkResult(t, report, err)
+		})
+	}
+}
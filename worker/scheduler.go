@@ -0,0 +1,212 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+)
+
+// claimLease is how long a claimed scheduled transfer is hidden from other schedulers while it runs
+// If a scheduler dies before recording the run, the occurrence becomes due again once the lease is over
+const claimLease = 5 * time.Minute
+
+// Scheduler runs scheduled transfers when they fall due
+// Several schedulers can share a database: each due transfer is claimed by a single one of them,
+// and every occurrence transfers with its own idempotency key so it never moves money twice
+type Scheduler struct {
+	store db.Store
+	interval time.Duration
+	maxAttempts int32
+	retryDelay time.Duration
+	defaultLimits map[string]db.DefaultTransferLimit
+}
+
+// NewScheduler creates a new scheduler
+func NewScheduler(config util.Config, store db.Store) *Scheduler {
+	return &Scheduler{
+		store: store,
+		interval: config.SchedulerInterval,
+		maxAttempts: config.ScheduledTransferMaxAttempts,
+		retryDelay: config.ScheduledTransferRetryDelay,
+		defaultLimits: db.DefaultTransferLimits(config),
+	}
+}
+
+// Start runs the due scheduled transfers every interval until ctx is done
+func (scheduler *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(scheduler.interval)
+	defer ticker.Stop()
+
+	for {
+		err := scheduler.RunDue(ctx)
+		if err != nil {
+			log.Println("cannot run scheduled transfers:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs the scheduled transfers that are due, one at a time, until none is left
+func (scheduler *Scheduler) RunDue(ctx context.Context) error {
+	for {
+		now := time.Now().UTC()
+		scheduled, err := scheduler.store.ClaimDueScheduledTransfer(ctx, db.ClaimDueScheduledTransferParams{
+			LeaseUntil: now.Add(claimLease),
+			Now: now,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return err
+		}
+
+		err = scheduler.run(ctx, scheduled, now)
+		if err != nil && !errors.Is(err, db.ErrRunAlreadyRecorded) {
+			return err
+		}
+	}
+}
+
+// errNotAllowedToPay is the error recorded when the creator of a scheduled transfer may no longer pay from its account
+var errNotAllowedToPay = errors.New("the creator of the scheduled transfer is no longer allowed to pay from the account")
+
+// run transfers the next occurrence of a claimed scheduled transfer and records the outcome
+// A scheduled transfer whose creator was removed from the account, or can no longer pay from it, is cancelled instead
+func (scheduler *Scheduler) run(ctx context.Context, scheduled db.ScheduledTransfer, now time.Time) error {
+	allowed, err := scheduler.mayPay(ctx, scheduled)
+	if err != nil {
+		return err
+	}
+
+	arg := db.RecordScheduledTransferRunTxParams{
+		Run: db.CreateScheduledTransferRunParams{
+			ScheduledTransferID: scheduled.ID,
+			ScheduledFor: scheduled.NextRunAt,
+			Status: db.RunSucceeded,
+		},
+	}
+	if !allowed {
+		arg.Run.Status = db.RunFailed
+		arg.Run.Error = errNotAllowedToPay.Error()
+		arg.Schedule = cancel(scheduled)
+	} else {
+		key := fmt.Sprintf("scheduled_transfer:%d:%d", scheduled.ID, scheduled.NextRunAt.Unix())
+		result, transferErr := scheduler.store.TransferTx(ctx, db.TransferTxParams{
+			FromAccountID: scheduled.FromAccountID,
+			ToAccountID: scheduled.ToAccountID,
+			Amount: scheduled.Amount,
+			InitiatedBy: scheduled.Owner,
+			IdempotencyKey: &db.IdempotencyKeyParams{
+				Username: scheduled.Owner,
+				Key: key,
+				RequestHash: key,
+			},
+			DefaultLimits: scheduler.defaultLimits,
+			CheckApprovalThreshold: true,
+		})
+
+		if transferErr == nil {
+			arg.Run.TransferID = sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+			arg.Schedule = advance(scheduled, now)
+		} else {
+			arg.Run.Status = db.RunFailed
+			arg.Run.Error = transferErr.Error()
+			arg.Schedule = scheduler.retry(scheduled, now)
+		}
+	}
+
+	record, err := scheduler.store.RecordScheduledTransferRunTx(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("scheduled transfer %d for %s %s, now %s", scheduled.ID, scheduled.NextRunAt, record.Run.Status, record.ScheduledTransfer.Status)
+	return nil
+}
+
+// mayPay checks that the creator of a scheduled transfer is still a member of its account who is allowed to pay from it
+func (scheduler *Scheduler) mayPay(ctx context.Context, scheduled db.ScheduledTransfer) (bool, error) {
+	member, err := scheduler.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: scheduled.FromAccountID,
+		Username: scheduled.Owner,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return db.MemberMayPay(member.Role), nil
+}
+
+// advance moves a scheduled transfer on to its next occurrence, or completes it if there is none
+// Occurrences missed while no scheduler was running are skipped, so a late transfer is only made once
+func advance(scheduled db.ScheduledTransfer, now time.Time) db.UpdateScheduledTransferScheduleParams {
+	arg := db.UpdateScheduledTransferScheduleParams{
+		ID: scheduled.ID,
+		Status: db.ScheduledTransferCompleted,
+		NextRunAt: scheduled.NextRunAt,
+		NextAttemptAt: scheduled.NextRunAt,
+		Attempts: scheduled.Attempts,
+	}
+
+	next, ok := util.NextOccurrence(scheduled.Recurrence, int(scheduled.DayOfMonth), scheduled.NextRunAt.UTC())
+	if !ok {
+		return arg
+	}
+	for !next.After(now) {
+		next, _ = util.NextOccurrence(scheduled.Recurrence, int(scheduled.DayOfMonth), next)
+	}
+
+	arg.Status = db.ScheduledTransferActive
+	arg.NextRunAt = next
+	arg.NextAttemptAt = next
+	arg.Attempts = 0
+	return arg
+}
+
+// retry tries a failed occurrence again after the retry delay
+// Once it ran out of attempts a recurring transfer skips the occurrence, and a one-off transfer fails
+func (scheduler *Scheduler) retry(scheduled db.ScheduledTransfer, now time.Time) db.UpdateScheduledTransferScheduleParams {
+	attempts := scheduled.Attempts + 1
+	if attempts < scheduler.maxAttempts {
+		return db.UpdateScheduledTransferScheduleParams{
+			ID: scheduled.ID,
+			Status: db.ScheduledTransferActive,
+			NextRunAt: scheduled.NextRunAt,
+			NextAttemptAt: now.Add(scheduler.retryDelay),
+			Attempts: attempts,
+		}
+	}
+
+	arg := advance(scheduled, now)
+	if arg.Status == db.ScheduledTransferCompleted {
+		arg.Status = db.ScheduledTransferFailed
+		arg.Attempts = attempts
+	}
+	return arg
+}
+
+// cancel stops a scheduled transfer at its current occurrence
+func cancel(scheduled db.ScheduledTransfer) db.UpdateScheduledTransferScheduleParams {
+	return db.UpdateScheduledTransferScheduleParams{
+		ID: scheduled.ID,
+		Status: db.ScheduledTransferCancelled,
+		NextRunAt: scheduled.NextRunAt,
+		NextAttemptAt: scheduled.NextAttemptAt,
+		Attempts: scheduled.Attempts,
+	}
+}
@@ -0,0 +1,367 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nokkvi/simplebank/db/mock"
+	db "github.com/nokkvi/simplebank/db/sqlc"
+	"github.com/nokkvi/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScheduler(store db.Store) *Scheduler {
+	config := util.Config{
+		SchedulerInterval: time.Minute,
+		ScheduledTransferMaxAttempts: 3,
+		ScheduledTransferRetryDelay: time.Hour,
+		DailyTransferLimitAmount: 1000,
+		DailyTransferLimitCount: 10,
+	}
+
+	return NewScheduler(config, store)
+}
+
+func TestRunDue(t *testing.T) {
+	scheduled := randomScheduledTransfer(util.RecurrenceWeekly)
+	transfer := db.Transfer{
+		ID: util.RandomInt(1, 1000),
+		FromAccountID: scheduled.FromAccountID,
+		ToAccountID: scheduled.ToAccountID,
+		Amount: scheduled.Amount,
+	}
+	payer := db.AccountMember{
+		AccountID: scheduled.FromAccountID,
+		Username: scheduled.Owner,
+		Role: db.MemberPayer,
+	}
+
+	testCases := []struct {
+		name string
+		scheduled db.ScheduledTransfer
+		buildStubs func(store *mockdb.MockStore)
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			scheduled: scheduled,
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(scheduled, nil),
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.ScheduledTransfer{}, sql.ErrNoRows),
+				)
+
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: scheduled.FromAccountID, Username: scheduled.Owner})).
+					Times(1).
+					Return(payer, nil)
+
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+						require.Equal(t, scheduled.FromAccountID, arg.FromAccountID)
+						require.Equal(t, scheduled.ToAccountID, arg.ToAccountID)
+						require.Equal(t, scheduled.Amount, arg.Amount)
+						require.Equal(t, scheduled.Owner, arg.InitiatedBy)
+						require.NotNil(t, arg.IdempotencyKey)
+						require.Equal(t, scheduled.Owner, arg.IdempotencyKey.Username)
+						require.True(t, arg.CheckApprovalThreshold)
+						require.Equal(t, int64(1000), arg.DefaultLimits[db.LimitDaily].MaxAmount)
+						return db.TransferTxResult{Transfer: transfer}, nil
+					})
+
+				store.EXPECT().
+					RecordScheduledTransferRunTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RecordScheduledTransferRunTxParams) (db.RecordScheduledTransferRunTxResult, error) {
+						require.Equal(t, db.RunSucceeded, arg.Run.Status)
+						require.Equal(t, sql.NullInt64{Int64: transfer.ID, Valid: true}, arg.Run.TransferID)
+						require.Equal(t, scheduled.NextRunAt, arg.Run.ScheduledFor)
+						require.Equal(t, db.ScheduledTransferActive, arg.Schedule.Status)
+						require.Equal(t, scheduled.NextRunAt.AddDate(0, 0, 7), arg.Schedule.NextRunAt)
+						return db.RecordScheduledTransferRunTxResult{}, nil
+					})
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			scheduled: scheduled,
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(scheduled, nil),
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.ScheduledTransfer{}, sql.ErrNoRows),
+				)
+
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: scheduled.FromAccountID, Username: scheduled.Owner})).
+					Times(1).
+					Return(payer, nil)
+
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+
+				store.EXPECT().
+					RecordScheduledTransferRunTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RecordScheduledTransferRunTxParams) (db.RecordScheduledTransferRunTxResult, error) {
+						require.Equal(t, db.RunFailed, arg.Run.Status)
+						require.Equal(t, db.ErrInsufficientFunds.Error(), arg.Run.Error)
+						require.False(t, arg.Run.TransferID.Valid)
+						require.Equal(t, db.ScheduledTransferActive, arg.Schedule.Status)
+						require.Equal(t, scheduled.NextRunAt, arg.Schedule.NextRunAt)
+						require.True(t, arg.Schedule.NextAttemptAt.After(time.Now()))
+						require.Equal(t, int32(1), arg.Schedule.Attempts)
+						return db.RecordScheduledTransferRunTxResult{}, nil
+					})
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "ApprovalRequired",
+			scheduled: scheduled,
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(scheduled, nil),
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.ScheduledTransfer{}, sql.ErrNoRows),
+				)
+
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(payer, nil)
+
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, db.ErrApprovalRequired)
+
+				store.EXPECT().
+					RecordScheduledTransferRunTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RecordScheduledTransferRunTxParams) (db.RecordScheduledTransferRunTxResult, error) {
+						require.Equal(t, db.RunFailed, arg.Run.Status)
+						require.Equal(t, db.ErrApprovalRequired.Error(), arg.Run.Error)
+						require.False(t, arg.Run.TransferID.Valid)
+						return db.RecordScheduledTransferRunTxResult{}, nil
+					})
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AlreadyRecorded",
+			scheduled: scheduled,
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(scheduled, nil),
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.ScheduledTransfer{}, sql.ErrNoRows),
+				)
+
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: scheduled.FromAccountID, Username: scheduled.Owner})).
+					Times(1).
+					Return(payer, nil)
+
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{Transfer: transfer}, nil)
+
+				store.EXPECT().
+					RecordScheduledTransferRunTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RecordScheduledTransferRunTxResult{}, db.ErrRunAlreadyRecorded)
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "RemovedMember",
+			scheduled: scheduled,
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(scheduled, nil),
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.ScheduledTransfer{}, sql.ErrNoRows),
+				)
+
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountMember{}, sql.ErrNoRows)
+
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					RecordScheduledTransferRunTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RecordScheduledTransferRunTxParams) (db.RecordScheduledTransferRunTxResult, error) {
+						require.Equal(t, db.RunFailed, arg.Run.Status)
+						require.Equal(t, errNotAllowedToPay.Error(), arg.Run.Error)
+						require.False(t, arg.Run.TransferID.Valid)
+						require.Equal(t, db.ScheduledTransferCancelled, arg.Schedule.Status)
+						require.Equal(t, scheduled.NextRunAt, arg.Schedule.NextRunAt)
+						return db.RecordScheduledTransferRunTxResult{}, nil
+					})
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "MemberIsViewer",
+			scheduled: scheduled,
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(scheduled, nil),
+					store.EXPECT().ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.ScheduledTransfer{}, sql.ErrNoRows),
+				)
+
+				viewer := payer
+				viewer.Role = db.MemberViewer
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(viewer, nil)
+
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					RecordScheduledTransferRunTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RecordScheduledTransferRunTxParams) (db.RecordScheduledTransferRunTxResult, error) {
+						require.Equal(t, db.RunFailed, arg.Run.Status)
+						require.Equal(t, db.ScheduledTransferCancelled, arg.Schedule.Status)
+						return db.RecordScheduledTransferRunTxResult{}, nil
+					})
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "MemberLookupError",
+			scheduled: scheduled,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(scheduled, nil)
+
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountMember{}, sql.ErrConnDone)
+
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					RecordScheduledTransferRunTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+		{
+			name: "ClaimError",
+			scheduled: scheduled,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ClaimDueScheduledTransfer(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ScheduledTransfer{}, sql.ErrConnDone)
+
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			scheduler := newTestScheduler(store)
+			err := scheduler.RunDue(context.Background())
+			tc.checkResult(t, err)
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	scheduler := newTestScheduler(nil)
+	now := time.Now().UTC()
+
+	scheduled := randomScheduledTransfer(util.RecurrenceOnce)
+	scheduled.Attempts = scheduler.maxAttempts - 1
+	arg := scheduler.retry(scheduled, now)
+	require.Equal(t, db.ScheduledTransferFailed, arg.Status)
+	require.Equal(t, scheduler.maxAttempts, arg.Attempts)
+
+	// a recurring transfer that ran out of attempts skips to its next occurrence
+	scheduled = randomScheduledTransfer(util.RecurrenceMonthly)
+	scheduled.Attempts = scheduler.maxAttempts - 1
+	arg = scheduler.retry(scheduled, now)
+	require.Equal(t, db.ScheduledTransferActive, arg.Status)
+	require.True(t, arg.NextRunAt.After(now))
+	require.Equal(t, arg.NextRunAt, arg.NextAttemptAt)
+	require.Zero(t, arg.Attempts)
+}
+
+func TestAdvanceSkipsMissedOccurrences(t *testing.T) {
+	now := time.Now().UTC()
+
+	scheduled := randomScheduledTransfer(util.RecurrenceWeekly)
+	scheduled.NextRunAt = now.AddDate(0, 0, -30)
+
+	arg := advance(scheduled, now)
+	require.Equal(t, db.ScheduledTransferActive, arg.Status)
+	require.True(t, arg.NextRunAt.After(now))
+	require.False(t, arg.NextRunAt.After(now.AddDate(0, 0, 7)))
+}
+
+func randomScheduledTransfer(recurrence string) db.ScheduledTransfer {
+	nextRunAt := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+
+	return db.ScheduledTransfer{
+		ID: util.RandomInt(1, 1000),
+		Owner: util.RandomOwner(),
+		FromAccountID: util.RandomInt(1, 1000),
+		ToAccountID: util.RandomInt(1001, 2000),
+		Amount: util.RandomMoney(),
+		Recurrence: recurrence,
+		DayOfMonth: int32(nextRunAt.Day()),
+		Status: db.ScheduledTransferActive,
+		NextRunAt: nextRunAt,
+		NextAttemptAt: nextRunAt,
+	}
+}